@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix 所有 API key 明碼的開頭，用來與 JWT 區分
+const APIKeyPrefix = "dl_"
+
+// API key 權限
+const (
+	ScopeRead  = "read"  // 只能查詢（GET）
+	ScopeWrite = "write" // 可新增 / 修改 / 刪除
+)
+
+// apiKeyDisplayLength 列表中顯示的明碼長度（含 APIKeyPrefix）
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// GenerateAPIKey 產生新的 API key，回傳明碼、顯示用前綴與雜湊
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("generate api key: %w", err)
+	}
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey API key 的 SHA-256（明碼本身是高熵亂數，不需要 bcrypt 這類慢雜湊）
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey token 是否為 API key（而非登入後取得的 JWT）
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// ValidScope scope 是否為 read / write
+func ValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite
+}
@@ -0,0 +1,218 @@
+// Package auth 處理密碼雜湊、JWT 簽發與驗證、個人 API key，以及 Fiber 的驗證 middleware。
+//
+// Authorization: Bearer 後面可以是登入取得的 JWT，或是 dl_ 開頭的 API key。
+// 未帶 token 的請求預設會視為 MVP 單人模式的預設使用者；設定 Required 後才會拒絕（401）。
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/harvc/duellog/apps/api/repo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength 密碼最短長度
+const MinPasswordLength = 8
+
+// c.Locals 中存放驗證結果的 key
+const (
+	localsUserID   = "userID"
+	localsAPIKeyID = "apiKeyID"
+)
+
+// ErrInvalidToken token 格式錯誤、簽章不符或已過期
+var ErrInvalidToken = errors.New("invalid token")
+
+// HashPassword 以 bcrypt 雜湊密碼
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword 密碼是否與雜湊相符（seed 的 placeholder_hash 永遠不相符）
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Tokens 簽發與驗證 HS256 JWT（sub = user ID）
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTokens 建立 Tokens；secret 為空時產生隨機密鑰（重新啟動後舊 token 全部失效）
+func NewTokens(secret string, ttl time.Duration) (*Tokens, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generate jwt secret: %w", err)
+		}
+	}
+	return &Tokens{secret: key, ttl: ttl}, nil
+}
+
+// TTL token 有效期間
+func (t *Tokens) TTL() time.Duration { return t.ttl }
+
+// Issue 簽發 userID 的 token
+func (t *Tokens) Issue(userID string) (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   userID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(t.ttl)),
+		ID:        randomID(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+}
+
+// Parse 驗證 token，回傳 user ID
+func (t *Tokens) Parse(token string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Config middleware 設定
+type Config struct {
+	Tokens  *Tokens
+	Users   repo.UserRepository
+	APIKeys repo.APIKeyRepository
+	// Required 為 true 時未帶 token 的請求回傳 401；否則視為預設使用者
+	Required bool
+}
+
+// New 建立驗證 middleware：驗證 Authorization: Bearer <token> 並把 user ID 存入 c.Locals
+func New(cfg Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, hasToken := bearerToken(c.Get(fiber.HeaderAuthorization))
+
+		if !hasToken {
+			if cfg.Required {
+				return c.Status(401).JSON(fiber.Map{"error": "需要登入"})
+			}
+			userID, err := cfg.Users.DefaultID()
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": "找不到使用者"})
+			}
+			c.Locals(localsUserID, userID)
+			return c.Next()
+		}
+
+		if IsAPIKey(token) {
+			return authenticateAPIKey(c, cfg.APIKeys, token)
+		}
+
+		userID, err := cfg.Tokens.Parse(token)
+		if err != nil {
+			return c.Status(401).JSON(fiber.Map{"error": "登入已失效，請重新登入"})
+		}
+		// 使用者被刪除後 token 也隨之失效
+		if _, err := cfg.Users.GetByID(userID); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return c.Status(401).JSON(fiber.Map{"error": "登入已失效，請重新登入"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": "查詢使用者失敗", "details": err.Error()})
+		}
+		c.Locals(localsUserID, userID)
+		return c.Next()
+	}
+}
+
+// authenticateAPIKey 驗證 API key：已撤銷或不存在回傳 401，read scope 的 key 只能用於 GET / HEAD
+func authenticateAPIKey(c *fiber.Ctx, keys repo.APIKeyRepository, token string) error {
+	key, err := keys.GetByHash(HashAPIKey(token))
+	if errors.Is(err, repo.ErrNotFound) || (err == nil && key.RevokedAt != nil) {
+		return c.Status(401).JSON(fiber.Map{"error": "API key 無效或已撤銷"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢 API key 失敗", "details": err.Error()})
+	}
+
+	if key.Scope != ScopeWrite && c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return c.Status(403).JSON(fiber.Map{"error": "API key 權限不足（read 只能查詢）"})
+	}
+
+	// 記錄最後使用時間失敗不影響請求本身
+	_ = keys.Touch(key.ID, time.Now())
+
+	c.Locals(localsUserID, key.UserID)
+	c.Locals(localsAPIKeyID, key.ID)
+	return c.Next()
+}
+
+// RequireAdmin 只允許管理者：預設使用者（單人模式的擁有者；未登入的請求在非 Required 模式下即為預設使用者）
+// 或 email 列在 adminEmails 中的使用者。用於遊戲、賽季、牌組模板等所有使用者共用的資料，須放在 New 之後。
+func RequireAdmin(users repo.UserRepository, adminEmails []string) fiber.Handler {
+	admins := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			admins[email] = true
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		userID := UserID(c)
+		defaultID, err := users.DefaultID()
+		if err != nil && !errors.Is(err, repo.ErrNotFound) {
+			return c.Status(500).JSON(fiber.Map{"error": "查詢使用者失敗", "details": err.Error()})
+		}
+		if userID != "" && userID == defaultID {
+			return c.Next()
+		}
+		if len(admins) > 0 && userID != "" {
+			user, err := users.GetByID(userID)
+			if err != nil && !errors.Is(err, repo.ErrNotFound) {
+				return c.Status(500).JSON(fiber.Map{"error": "查詢使用者失敗", "details": err.Error()})
+			}
+			if err == nil && admins[strings.ToLower(user.Email)] {
+				return c.Next()
+			}
+		}
+		return c.Status(403).JSON(fiber.Map{"error": "只有管理者可以修改遊戲、賽季與牌組模板"})
+	}
+}
+
+// UserID 取得 middleware 驗證後的使用者 ID（沒有經過 middleware 時為空字串）
+func UserID(c *fiber.Ctx) string {
+	id, _ := c.Locals(localsUserID).(string)
+	return id
+}
+
+// ViaAPIKey 這個請求是否以 API key 驗證（而非登入）
+func ViaAPIKey(c *fiber.Ctx) bool {
+	_, ok := c.Locals(localsAPIKeyID).(string)
+	return ok
+}
+
+// bearerToken 從 Authorization header 取出 token
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
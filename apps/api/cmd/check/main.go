@@ -1,15 +1,21 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
+	"os"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
+// columnsSQL 依資料庫列出 deck_templates 的欄位（序號從 0 開始，與 SQLite 的 cid 相同）
+var columnsSQL = map[storage.Dialect]string{
+	storage.SQLite:   "SELECT cid, name, type FROM pragma_table_info('deck_templates')",
+	storage.Postgres: "SELECT ordinal_position - 1, column_name, data_type FROM information_schema.columns WHERE table_name = 'deck_templates' ORDER BY ordinal_position",
+}
+
 func main() {
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -17,7 +23,7 @@ func main() {
 
 	// 檢查 deck_templates 表結構
 	fmt.Println("Deck Templates 表結構:")
-	rows, err := db.Query("PRAGMA table_info(deck_templates)")
+	rows, err := db.Query(columnsSQL[db.Dialect])
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -26,9 +32,7 @@ func main() {
 	for rows.Next() {
 		var cid int
 		var name, colType string
-		var notNull, pk int
-		var dfltValue sql.NullString
-		rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk)
+		rows.Scan(&cid, &name, &colType)
 		fmt.Printf("  %d: %s (%s)\n", cid, name, colType)
 	}
 
@@ -1,50 +1,70 @@
 package main
 
 import (
-	"database/sql"
+	"flag"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/season"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
 func main() {
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	var (
+		gameKey string
+		code    string
+	)
+	flag.StringVar(&gameKey, "game-key", models.DefaultGameKey, "create the season for this game")
+	flag.StringVar(&code, "code", "", "season code, e.g. S49 (default: the current season of the game's calendar)")
+	flag.Parse()
+
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
+	store := sqlrepo.New(db)
 
 	// 取得正確的 game_id
-	var gameID string
-	err = db.QueryRow("SELECT id FROM games WHERE key = 'master_duel'").Scan(&gameID)
+	game, err := store.Games().GetByKey(gameKey)
 	if err != nil {
 		log.Fatal("找不到遊戲:", err)
 	}
+	gameID := game.ID
 	fmt.Printf("Game ID: %s\n", gameID)
 
-	// 檢查 S49 是否存在
-	var existingID string
-	err = db.QueryRow("SELECT id FROM seasons WHERE code = 'S49' AND game_id = ?", gameID).Scan(&existingID)
-	if err == nil {
-		fmt.Printf("S49 賽季已存在: %s\n", existingID)
-		return
+	// 未指定代碼時建立目前的賽季
+	if code == "" {
+		cal, ok := season.For(gameKey)
+		if !ok {
+			log.Fatalf("%s 沒有賽季曆，請以 -code 指定賽季代碼", gameKey)
+		}
+		code = cal.Current(time.Now()).Code
 	}
 
-	// 建立 S49 (2026年1月)
-	seasonID := uuid.New().String()
-	startDate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+	// 檢查賽季是否存在
+	if existing, err := store.Seasons().GetByCode(gameID, code); err == nil {
+		fmt.Printf("%s 賽季已存在: %s\n", code, existing.ID)
+		return
+	}
 
-	_, err = db.Exec(`
-		INSERT INTO seasons (id, game_id, code, start_date, end_date)
-		VALUES (?, ?, 'S49', ?, ?)
-	`, seasonID, gameID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-	if err != nil {
-		log.Fatal("建立 S49 賽季失敗:", err)
+	// 起訖日依賽季曆推算
+	s := models.Season{ID: uuid.New().String(), GameID: gameID, Code: code}
+	if start, end, ok := season.Dates(gameKey, code); ok {
+		s.StartDate, s.EndDate = &start, &end
+	}
+	if err := store.Seasons().Create(s); err != nil {
+		log.Fatalf("建立 %s 賽季失敗: %v", code, err)
 	}
 
-	fmt.Printf("✓ 建立 S49 賽季成功: %s\n", seasonID)
+	if s.StartDate != nil {
+		fmt.Printf("✓ 建立 %s 賽季成功: %s（%s ~ %s）\n", code, s.ID, *s.StartDate, *s.EndDate)
+	} else {
+		fmt.Printf("✓ 建立 %s 賽季成功: %s（無法推算起訖日）\n", code, s.ID)
+	}
 }
@@ -1,23 +1,17 @@
 package main
 
 import (
-	"database/sql"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
-type deckTemplateRow struct {
-	ID       string
-	Main     string
-	Theme    string
-	DeckType string
-}
-
 func sqlQuote(s string) string {
 	// SQLite single-quote escaping
 	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
@@ -25,13 +19,13 @@ func sqlQuote(s string) string {
 
 func main() {
 	var (
-		dbPath    string
-		gameIDOut string
-		outPath   string
+		dbPath  string
+		gameKey string
+		outPath string
 	)
 
 	flag.StringVar(&dbPath, "db", os.Getenv("DB_PATH"), "path to sqlite db (default: DB_PATH env or ./duellog.db)")
-	flag.StringVar(&gameIDOut, "game-id", "game-md", "game_id to emit (default: game-md)")
+	flag.StringVar(&gameKey, "game-key", models.DefaultGameKey, "export the templates of this game")
 	flag.StringVar(&outPath, "out", "", "output file path (default: stdout)")
 	flag.Parse()
 
@@ -39,32 +33,21 @@ func main() {
 		dbPath = "./duellog.db"
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), dbPath)
 	if err != nil {
 		log.Fatalf("open db: %v", err)
 	}
 	defer db.Close()
 
-	rows, err := db.Query(`
-		SELECT id, main, COALESCE(theme, ''), deck_type
-		FROM deck_templates
-		ORDER BY deck_type ASC, main ASC, id ASC
-	`)
+	store := sqlrepo.New(db)
+	game, err := store.Games().GetByKey(gameKey)
 	if err != nil {
-		log.Fatalf("query deck_templates: %v", err)
+		log.Fatalf("find game %q: %v", gameKey, err)
 	}
-	defer rows.Close()
 
-	var all []deckTemplateRow
-	for rows.Next() {
-		var r deckTemplateRow
-		if err := rows.Scan(&r.ID, &r.Main, &r.Theme, &r.DeckType); err != nil {
-			log.Fatalf("scan deck_templates: %v", err)
-		}
-		all = append(all, r)
-	}
-	if err := rows.Err(); err != nil {
-		log.Fatalf("rows: %v", err)
+	all, err := store.Templates().List(game.ID, "")
+	if err != nil {
+		log.Fatalf("query deck_templates: %v", err)
 	}
 
 	var b strings.Builder
@@ -72,18 +55,21 @@ func main() {
 	b.WriteString("-- Source DB: " + dbPath + "\n")
 	b.WriteString("-- Usage: replace the deck_templates section in apps/api/seed.sql\n\n")
 
-	b.WriteString("INSERT OR IGNORE INTO deck_templates (id, game_id, main, theme, deck_type) VALUES\n")
+	// game_id is looked up by key so the output does not depend on the source DB's game IDs.
+	gameIDOut := "(SELECT id FROM games WHERE key = " + sqlQuote(gameKey) + ")"
+
+	b.WriteString("INSERT INTO deck_templates (id, game_id, main, theme, deck_type) VALUES\n")
 	for i, r := range all {
 		line := fmt.Sprintf(
 			"  (%s, %s, %s, %s, %s)",
 			sqlQuote(r.ID),
-			sqlQuote(gameIDOut),
-			sqlQuote(r.Main),
+			gameIDOut,
+			sqlQuote(r.Name),
 			sqlQuote(r.Theme),
 			sqlQuote(r.DeckType),
 		)
 		if i == len(all)-1 {
-			line += ";\n"
+			line += "\nON CONFLICT DO NOTHING;\n"
 		} else {
 			line += ",\n"
 		}
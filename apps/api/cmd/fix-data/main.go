@@ -1,53 +1,52 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
+	"os"
 	"unicode/utf8"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
 func main() {
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
+	templates := sqlrepo.New(db).Templates()
 
 	// 找出有問題的牌組模板（名稱太短或包含無效 UTF-8）
 	fmt.Println("檢查異常資料:")
-	rows, err := db.Query("SELECT id, main FROM deck_templates")
+	all, err := templates.List("", "")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	var badIDs []string
-	for rows.Next() {
-		var id, name string
-		rows.Scan(&id, &name)
+	var bad []models.DeckTemplate
+	for _, t := range all {
 		// 檢查是否有異常
-		if len(name) < 2 || !utf8.ValidString(name) {
-			fmt.Printf("  發現異常: id=%s, name=[%s], bytes=%v\n", id, name, []byte(name))
-			badIDs = append(badIDs, id)
+		if len(t.Name) < 2 || !utf8.ValidString(t.Name) {
+			fmt.Printf("  發現異常: id=%s, name=[%s], bytes=%v\n", t.ID, t.Name, []byte(t.Name))
+			bad = append(bad, t)
 		}
 	}
-	rows.Close()
 
-	if len(badIDs) == 0 {
+	if len(bad) == 0 {
 		fmt.Println("  無異常資料")
 		return
 	}
 
 	// 刪除異常資料
-	fmt.Printf("\n刪除 %d 筆異常資料...\n", len(badIDs))
-	for _, id := range badIDs {
-		_, err := db.Exec("DELETE FROM deck_templates WHERE id = ?", id)
-		if err != nil {
-			fmt.Printf("  刪除 %s 失敗: %v\n", id, err)
+	fmt.Printf("\n刪除 %d 筆異常資料...\n", len(bad))
+	for _, t := range bad {
+		if err := templates.Delete(t.GameID, t.ID); err != nil {
+			fmt.Printf("  刪除 %s 失敗: %v\n", t.ID, err)
 		} else {
-			fmt.Printf("  已刪除: %s\n", id)
+			fmt.Printf("  已刪除: %s\n", t.ID)
 		}
 	}
 
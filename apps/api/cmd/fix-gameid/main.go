@@ -1,30 +1,36 @@
+// fix-gameid 將 game_id 為空或指向不存在遊戲的舊資料指定給 Master Duel（其他遊戲的資料不受影響）。
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
+	"os"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
 func main() {
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
 
 	// 取得正確的 game_id
-	var correctGameID string
-	err = db.QueryRow("SELECT id FROM games WHERE key = 'master_duel'").Scan(&correctGameID)
+	game, err := sqlrepo.New(db).Games().GetByKey(models.DefaultGameKey)
 	if err != nil {
 		log.Fatal("找不到遊戲:", err)
 	}
+	correctGameID := game.ID
 	fmt.Printf("正確的 game_id: %s\n", correctGameID)
 
+	// 只修正 game_id 為空或指向不存在遊戲的資料；其他遊戲的資料不受影響
+	const orphaned = " WHERE game_id IS NULL OR game_id NOT IN (SELECT id FROM games)"
+
 	// 更新 seasons 表
-	result, err := db.Exec("UPDATE seasons SET game_id = ?", correctGameID)
+	result, err := db.Exec("UPDATE seasons SET game_id = ?"+orphaned, correctGameID)
 	if err != nil {
 		log.Fatal("更新 seasons 失敗:", err)
 	}
@@ -32,7 +38,7 @@ func main() {
 	fmt.Printf("更新 seasons: %d 筆\n", rows)
 
 	// 更新 matches 表
-	result, err = db.Exec("UPDATE matches SET game_id = ?", correctGameID)
+	result, err = db.Exec("UPDATE matches SET game_id = ?"+orphaned, correctGameID)
 	if err != nil {
 		log.Fatal("更新 matches 失敗:", err)
 	}
@@ -40,7 +46,7 @@ func main() {
 	fmt.Printf("更新 matches: %d 筆\n", rows)
 
 	// 更新 decks 表
-	result, err = db.Exec("UPDATE decks SET game_id = ?", correctGameID)
+	result, err = db.Exec("UPDATE decks SET game_id = ?"+orphaned, correctGameID)
 	if err != nil {
 		log.Fatal("更新 decks 失敗:", err)
 	}
@@ -48,7 +54,7 @@ func main() {
 	fmt.Printf("更新 decks: %d 筆\n", rows)
 
 	// 更新 deck_templates 表
-	result, err = db.Exec("UPDATE deck_templates SET game_id = ?", correctGameID)
+	result, err = db.Exec("UPDATE deck_templates SET game_id = ?"+orphaned, correctGameID)
 	if err != nil {
 		log.Fatal("更新 deck_templates 失敗:", err)
 	}
@@ -0,0 +1,175 @@
+// fix-match-seasons 重新指定歷史對局的賽季：
+// 日期不在所屬賽季內的對局（e.g. 早期由前端送錯 seasonCode）改到日期所在的賽季，賽季不存在時自動建立。
+// 與新增對局相同，資料庫中的起訖日（PATCH /seasons 調整過的）優先，其次依賽季曆（見 repo.ResolveSeason）。
+// 有 played_at 的對局依遊戲的賽季切換時間判斷（修改 PATCH /games/:key 的切換時間後可用來重新指定）。
+//
+// 預設只列出會變更的對局；加上 -apply 才會寫入（所有使用者的對局，在單一 transaction 內完成）。
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/season"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// misseasoned 需要改賽季的對局
+type misseasoned struct {
+	matchID string
+	userID  string
+	from    string
+	to      string
+}
+
+func main() {
+	var (
+		gameKey string
+		apply   bool
+	)
+	flag.StringVar(&gameKey, "game-key", models.DefaultGameKey, "fix the matches of this game")
+	flag.BoolVar(&apply, "apply", false, "write the changes (default: dry run)")
+	flag.Parse()
+
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	store := sqlrepo.New(db)
+
+	game, err := store.Games().GetByKey(gameKey)
+	if err != nil {
+		log.Fatalf("找不到遊戲 %s: %v", gameKey, err)
+	}
+	if _, ok := season.For(gameKey); !ok {
+		log.Fatalf("%s 沒有賽季曆，無法由日期推算賽季", gameKey)
+	}
+
+	fixes, err := findMisseasoned(db, store.Seasons(), game)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(fixes) == 0 {
+		fmt.Println("✓ 所有對局的賽季都與日期相符")
+		return
+	}
+
+	// 依 from → to 彙總
+	summary := map[string]int{}
+	for _, f := range fixes {
+		summary[f.from+" → "+f.to]++
+	}
+	keys := make([]string, 0, len(summary))
+	for k := range summary {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("找到 %d 筆賽季與日期不符的對局:\n", len(fixes))
+	for _, k := range keys {
+		fmt.Printf("  %s: %d 筆\n", k, summary[k])
+	}
+
+	if !apply {
+		fmt.Println("\n（dry run，加上 -apply 才會寫入）")
+		return
+	}
+
+	err = store.InTx(func(tx repo.Store) error {
+		seasonIDs := map[string]string{}
+		for _, f := range fixes {
+			seasonID, ok := seasonIDs[f.to]
+			if !ok {
+				s := models.Season{ID: uuid.New().String(), GameID: game.ID, Code: f.to}
+				if start, end, ok := season.Dates(gameKey, f.to); ok {
+					s.StartDate, s.EndDate = &start, &end
+				}
+				if seasonID, err = tx.Seasons().GetOrCreate(s); err != nil {
+					return fmt.Errorf("建立賽季 %s 失敗: %w", f.to, err)
+				}
+				seasonIDs[f.to] = seasonID
+			}
+			if err := tx.Matches().Update(f.userID, f.matchID, repo.MatchPatch{SeasonID: &seasonID}); err != nil {
+				return fmt.Errorf("更新對局 %s 失敗: %w", f.matchID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\n✓ 已更新 %d 筆對局\n", len(fixes))
+}
+
+// findMisseasoned 找出日期不在所屬賽季內的對局（資料庫與賽季曆都推算不出起訖日的代碼不處理）
+func findMisseasoned(db *storage.DB, seasons repo.SeasonRepository, game models.Game) ([]misseasoned, error) {
+	rows, err := db.Query(`
+		SELECT m.id, m.user_id, m.date, m.played_at, s.code
+		FROM matches m
+		JOIN seasons s ON m.season_id = s.id
+		WHERE m.game_id = ?
+		ORDER BY m.date
+	`, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reset, err := season.ParseReset(game.SeasonResetTime, game.SeasonTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先讀完所有對局再查賽季，避免查詢時還占著 rows 的連線
+	type candidate struct {
+		misseasoned
+		date time.Time
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var f misseasoned
+		var dateStr string
+		var playedAt sql.NullTime
+		if err := rows.Scan(&f.matchID, &f.userID, &dateStr, &playedAt, &f.from); err != nil {
+			return nil, err
+		}
+		date, ok := season.ParseDate(dateStr)
+		if playedAt.Valid {
+			date, ok = reset.Date(playedAt.Time), true
+		}
+		if !ok {
+			log.Printf("略過日期格式錯誤的對局 %s: %s", f.matchID, dateStr)
+			continue
+		}
+
+		candidates = append(candidates, candidate{f, date})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	var fixes []misseasoned
+	for _, c := range candidates {
+		var mismatch *season.MismatchError
+		_, err := repo.ResolveSeason(seasons, game, c.from, c.date)
+		switch {
+		case errors.As(err, &mismatch) && mismatch.Expected != "":
+			c.to = mismatch.Expected
+			fixes = append(fixes, c.misseasoned)
+		case err != nil && mismatch == nil:
+			return nil, err
+		}
+	}
+	return fixes, nil
+}
@@ -0,0 +1,166 @@
+// fix-ranks 處理 migration 010_structured_rank 無法轉換的階級（記錄在 unmapped_ranks 表）：
+// 依遊戲規則重新解析（接受代碼與任一語系的顯示名稱），也可用 -map 指定寫法對應的階級。
+//
+// 預設只列出各寫法的筆數與轉換結果；加上 -apply 才會寫入 rank_tier / rank_level 並從 unmapped_ranks 移除。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// unmapped 一筆無法轉換階級的對局
+type unmapped struct {
+	matchID string
+	userID  string
+	gameKey string
+	mode    string
+	raw     string
+	rank    *models.Rank // 轉換結果；仍無法轉換時為 nil
+}
+
+func main() {
+	var apply bool
+	overrides := map[string]string{}
+	flag.BoolVar(&apply, "apply", false, "write the converted ranks (default: dry run)")
+	flag.Func("map", `map a raw rank to a rank code, e.g. -map "鑽5=diamond-5" (repeatable)`, func(v string) error {
+		raw, code, ok := strings.Cut(v, "=")
+		if !ok {
+			return fmt.Errorf("格式需為 <原始寫法>=<階級代碼>")
+		}
+		overrides[strings.TrimSpace(raw)] = strings.TrimSpace(code)
+		return nil
+	})
+	flag.Parse()
+
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	store := sqlrepo.New(db)
+
+	exists, err := db.TableExists("unmapped_ranks")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !exists {
+		log.Fatal("找不到 unmapped_ranks 表，請先啟動後端或執行 go run ./cmd/migrate up 套用 migration")
+	}
+
+	rows, err := findUnmapped(db, overrides)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(rows) == 0 {
+		fmt.Println("✓ 沒有無法轉換的階級")
+		return
+	}
+
+	// 依原始寫法彙總
+	type group struct {
+		count  int
+		result string
+	}
+	groups := map[string]*group{}
+	fixable := 0
+	for _, u := range rows {
+		key := u.gameKey + " " + u.raw
+		g, ok := groups[key]
+		if !ok {
+			g = &group{result: "無法轉換（可用 -map 指定）"}
+			if u.rank != nil {
+				g.result = "→ " + u.rank.Code()
+			}
+			groups[key] = g
+		}
+		g.count++
+		if u.rank != nil {
+			fixable++
+		}
+	}
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("找到 %d 筆無法轉換階級的對局（可轉換 %d 筆）:\n", len(rows), fixable)
+	for _, k := range keys {
+		fmt.Printf("  %s: %d 筆 %s\n", k, groups[k].count, groups[k].result)
+	}
+
+	if !apply {
+		fmt.Println("\n（dry run，加上 -apply 才會寫入）")
+		return
+	}
+
+	err = store.InTx(func(tx repo.Store) error {
+		for _, u := range rows {
+			if u.rank == nil {
+				continue
+			}
+			if err := tx.Matches().Update(u.userID, u.matchID, repo.MatchPatch{Rank: u.rank}); err != nil {
+				return fmt.Errorf("更新對局 %s 失敗: %w", u.matchID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, u := range rows {
+		if u.rank == nil {
+			continue
+		}
+		if _, err := db.Exec("DELETE FROM unmapped_ranks WHERE match_id = ?", u.matchID); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// 對局已刪除的記錄一併清掉
+	if _, err := db.Exec("DELETE FROM unmapped_ranks WHERE match_id NOT IN (SELECT id FROM matches)"); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\n✓ 已更新 %d 筆對局，剩餘 %d 筆無法轉換\n", fixable, len(rows)-fixable)
+}
+
+// findUnmapped 讀出 unmapped_ranks 中仍存在的對局，並依 overrides 與遊戲規則嘗試轉換
+func findUnmapped(db *storage.DB, overrides map[string]string) ([]unmapped, error) {
+	rows, err := db.Query(`
+		SELECT u.match_id, m.user_id, g.key, m.mode, u.rank
+		FROM unmapped_ranks u
+		JOIN matches m ON u.match_id = m.id
+		JOIN games g ON m.game_id = g.id
+		ORDER BY u.rank
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []unmapped
+	for rows.Next() {
+		var u unmapped
+		if err := rows.Scan(&u.matchID, &u.userID, &u.gameKey, &u.mode, &u.raw); err != nil {
+			return nil, err
+		}
+		value := u.raw
+		if code, ok := overrides[strings.TrimSpace(u.raw)]; ok {
+			value = code
+		}
+		if rank, err := rules.Rank(rules.For(u.gameKey), u.mode, value); err == nil {
+			u.rank = rank
+		}
+		result = append(result, u)
+	}
+	return result, rows.Err()
+}
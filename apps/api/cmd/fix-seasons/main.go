@@ -1,19 +1,23 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
+	"os"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/season"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
 func main() {
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
+	seasons := sqlrepo.New(db).Seasons()
 
 	// 找出所有在 matches 中但不在 seasons 中的 season_id
 	rows, err := db.Query(`
@@ -37,27 +41,43 @@ func main() {
 	fmt.Printf("找到 %d 個缺失的 season_id\n", len(missingSeasonsIDs))
 
 	// 為每個缺失的 season 建立記錄
-	// 由於原始的 season code 已經遺失，我們統一設為 "S48" 或從 matches 中推斷
+	// 由於原始的 season code 已經遺失，依該 season 第一筆 match 的日期以賽季曆推算；
+	// 遊戲沒有賽季曆時退回舊做法（從 S48 往前推，起訖日為對局的日期範圍）
 	for i, seasonID := range missingSeasonsIDs {
 		// 從該 season 的第一筆 match 取得日期作為參考
-		var minDate, maxDate string
-		db.QueryRow(`
-			SELECT MIN(date), MAX(date) 
-			FROM matches 
-			WHERE season_id = ?
-		`, seasonID).Scan(&minDate, &maxDate)
+		var minDate, maxDate, gameID, gameKey string
+		if err := db.QueryRow(`
+			SELECT MIN(m.date), MAX(m.date), MIN(g.id), MIN(g.key)
+			FROM matches m
+			JOIN games g ON m.game_id = g.id
+			WHERE m.season_id = ?
+		`, seasonID).Scan(&minDate, &maxDate, &gameID, &gameKey); err != nil {
+			log.Printf("讀取 season %s 的對局失敗: %v", seasonID, err)
+			continue
+		}
+		minDate, maxDate = dateOnly(minDate), dateOnly(maxDate)
 
-		// 建立 season 記錄
-		code := fmt.Sprintf("S%d", 48-i) // 假設從 S48 往前推
-		_, err := db.Exec(`
-			INSERT INTO seasons (id, game_id, code, start_date, end_date)
-			VALUES (?, 'game-md', ?, ?, ?)
-		`, seasonID, code, minDate, maxDate)
+		code := fmt.Sprintf("S%d", 48-i)
+		startDate, endDate := minDate, maxDate
+		if cal, ok := season.For(gameKey); ok {
+			if t, ok := season.ParseDate(minDate); ok {
+				info := cal.ForDate(t)
+				code, startDate, endDate = info.Code, info.StartDate(), info.EndDate()
+			}
+		}
 
+		// 建立 season 記錄
+		err := seasons.Create(models.Season{
+			ID:        seasonID,
+			GameID:    gameID,
+			Code:      code,
+			StartDate: &startDate,
+			EndDate:   &endDate,
+		})
 		if err != nil {
 			log.Printf("建立 season %s 失敗: %v", seasonID, err)
 		} else {
-			fmt.Printf("建立 season: %s (code: %s, 日期: %s ~ %s)\n", seasonID, code, minDate, maxDate)
+			fmt.Printf("建立 season: %s (code: %s, 日期: %s ~ %s)\n", seasonID, code, startDate, endDate)
 		}
 	}
 
@@ -75,3 +95,11 @@ func main() {
 	`).Scan(&joinedCount)
 	fmt.Printf("JOIN 後可查詢的 matches 數量: %d\n", joinedCount)
 }
+
+// dateOnly 取 YYYY-MM-DD（PostgreSQL 的 date 欄位會帶時間部分）
+func dateOnly(s string) string {
+	if len(s) > len(season.DateLayout) {
+		return s[:len(season.DateLayout)]
+	}
+	return s
+}
@@ -1,42 +1,66 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/season"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
-// 階級轉換映射
-var rankMapping = map[string]string{
-	"銅5": "銅 V", "銅4": "銅 IV", "銅3": "銅 III", "銅2": "銅 II", "銅1": "銅 I",
-	"銀5": "銀 V", "銀4": "銀 IV", "銀3": "銀 III", "銀2": "銀 II", "銀1": "銀 I",
-	"金5": "金 V", "金4": "金 IV", "金3": "金 III", "金2": "金 II", "金1": "金 I",
-	"白金5": "白金 V", "白金4": "白金 IV", "白金3": "白金 III", "白金2": "白金 II", "白金1": "白金 I",
-	"鑽5": "鑽石 V", "鑽4": "鑽石 IV", "鑽3": "鑽石 III", "鑽2": "鑽石 II", "鑽1": "鑽石 I",
-	"大師5": "大師 V", "大師4": "大師 IV", "大師3": "大師 III", "大師2": "大師 II", "大師1": "大師 I",
+// importUserID 匯入對象：設定 IMPORT_EMAIL 時為該帳號，否則為預設使用者
+func importUserID(store repo.Store) (string, error) {
+	email := strings.TrimSpace(os.Getenv("IMPORT_EMAIL"))
+	if email == "" {
+		return store.Users().DefaultID()
+	}
+	user, err := store.Users().GetByEmail(email)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", email, err)
+	}
+	return user.ID, nil
+}
+
+// 階級縮寫（e.g. "鑽5" = 鑽石 V）的 tier 代碼
+var rankTierAbbrevs = map[string]string{
+	"銅": "bronze", "銀": "silver", "金": "gold", "白金": "platinum", "鑽": "diamond", "大師": "master",
+}
+
+// rankFromAbbrev 將 "金4" 形式的縮寫轉成階級代碼 "gold-4"；不是縮寫時照原樣回傳（交給 rules 解析代碼或顯示名稱）
+func rankFromAbbrev(raw string) string {
+	if n := len(raw); n > 1 && raw[n-1] >= '1' && raw[n-1] <= '5' {
+		if tier, ok := rankTierAbbrevs[raw[:n-1]]; ok {
+			return tier + "-" + raw[n-1:]
+		}
+	}
+	return raw
+}
+
+// 勝負轉換映射（平手：時間到、同時敗北等）
+var resultMapping = map[string]string{
+	"O": rules.ResultWin, "o": rules.ResultWin, "W": rules.ResultWin, "w": rules.ResultWin, "勝": rules.ResultWin,
+	"X": rules.ResultLoss, "x": rules.ResultLoss, "L": rules.ResultLoss, "l": rules.ResultLoss, "敗": rules.ResultLoss,
+	"D": rules.ResultDraw, "d": rules.ResultDraw, "△": rules.ResultDraw, "平": rules.ResultDraw, "和": rules.ResultDraw, "平手": rules.ResultDraw,
 }
 
 func main() {
-	// 開啟資料庫
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	// 開啟資料庫（設定 DATABASE_URL 時使用 PostgreSQL）
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal("無法開啟資料庫:", err)
 	}
 	defer db.Close()
-
-	// ===== 清空現有資料 =====
-	log.Println("清空現有資料...")
-	db.Exec("DELETE FROM matches")
-	db.Exec("DELETE FROM decks")
-	db.Exec("DELETE FROM seasons WHERE id != 'season-s49'") // 保留當季
-	db.Exec("DELETE FROM deck_templates")
-	log.Println("✓ 資料已清空")
+	store := sqlrepo.New(db)
 
 	// 開啟 CSV 檔案
 	file, err := os.Open("./import.csv")
@@ -56,16 +80,32 @@ func main() {
 		log.Fatal("CSV 檔案沒有資料")
 	}
 
-	// 取得預設資料
-	gameID := "game-md"
-	var userID string
-	err = db.QueryRow("SELECT id FROM users LIMIT 1").Scan(&userID)
+	// 取得預設資料（IMPORT_GAME_KEY 未設定時為 master_duel）
+	gameKey := os.Getenv("IMPORT_GAME_KEY")
+	if gameKey == "" {
+		gameKey = models.DefaultGameKey
+	}
+	game, err := store.Games().GetByKey(gameKey)
+	if err != nil {
+		log.Fatalf("找不到遊戲 %s: %v", gameKey, err)
+	}
+	gameID := game.ID
+	gameRules := rules.For(gameKey)
+	userID, err := importUserID(store)
 	if err != nil {
 		log.Fatal("找不到使用者:", err)
 	}
 
-	// 建立賽季快取
+	// ===== 清空該使用者現有資料 =====
+	log.Println("清空現有資料...")
+	if err := clearImportData(db, userID, gameID, currentSeasonCode(store.Seasons(), game)); err != nil {
+		log.Fatal("清空資料失敗:", err)
+	}
+	log.Println("✓ 資料已清空")
+
+	// 建立賽季 / 遊戲帳號快取
 	seasonCache := make(map[string]string)
+	accountCache := make(map[string]string)
 
 	// 跳過標題列，處理每一筆資料
 	header := records[0]
@@ -74,6 +114,7 @@ func main() {
 
 	successCount := 0
 	errorCount := 0
+	mismatchCount := 0 // 賽季代碼與日期不符（計入 errorCount）
 
 	for i, row := range records[1:] {
 		if len(row) < 11 {
@@ -85,7 +126,7 @@ func main() {
 		// 解析欄位 (根據你的格式)
 		// Rank, Account, 本家(我方), 小軸(我方), 勝負, 先後攻, 本家(敵方), 小軸(敵方), 備註, Date, Season, (可選) Mode
 		rankRaw := strings.TrimSpace(row[0])
-		// account := row[1] // 不使用
+		accountName := strings.TrimSpace(row[1])
 		myMain := strings.TrimSpace(row[2])
 		mySub := strings.TrimSpace(row[3])
 		resultRaw := strings.TrimSpace(row[4])
@@ -94,7 +135,7 @@ func main() {
 		oppSub := strings.TrimSpace(row[7])
 		note := strings.TrimSpace(row[8])
 		dateRaw := strings.TrimSpace(row[9])
-		seasonCode := strings.TrimSpace(row[10]) // 直接使用 CSV 中的 Season 欄位
+		seasonCode := strings.TrimSpace(row[10]) // CSV 的 Season 欄位（空白時依日期推算）
 
 		mode := "Ranked"
 		if len(row) >= 12 {
@@ -119,30 +160,32 @@ func main() {
 			}
 		}
 
-		// 轉換階級格式
-		rank, ok := rankMapping[rankRaw]
-		if !ok {
-			rank = rankRaw // 如果沒有映射，使用原始值
+		// 依遊戲規則驗證並轉成代碼（"金4" / "金 IV" → gold / 4、"先攻" → "first"；不記錄階級的模式不存階級）
+		rank, ruleErr := rules.Rank(gameRules, mode, rankFromAbbrev(rankRaw))
+		if ruleErr == nil {
+			mode, ruleErr = rules.Mode(gameRules, mode)
 		}
-		if mode != "Ranked" {
-			// rank is still required by schema; use placeholder.
-			rank = "—"
+		if ruleErr == nil {
+			playOrder, ruleErr = rules.PlayOrder(gameRules, playOrder)
+		}
+		if ruleErr != nil {
+			log.Printf("[%d] %v", i+1, ruleErr)
+			errorCount++
+			continue
 		}
 
-		// 轉換勝負
-		var result string
-		if resultRaw == "O" || resultRaw == "勝" || resultRaw == "o" {
-			result = "W"
-		} else if resultRaw == "X" || resultRaw == "敗" || resultRaw == "x" {
-			result = "L"
-		} else {
-			result = resultRaw
+		// 轉換勝負（無法辨識的標記不匯入）
+		result, ok := resultMapping[resultRaw]
+		if !ok {
+			log.Printf("[%d] 無法辨識的勝負: %s", i+1, resultRaw)
+			errorCount++
+			continue
 		}
 
 		// 轉換日期格式 (2025/12/31 → 2025-12-31)
 		date := strings.ReplaceAll(dateRaw, "/", "-")
 		// 驗證日期格式
-		_, err := time.Parse("2006-01-02", date)
+		day, err := time.Parse("2006-01-02", date)
 		if err != nil {
 			// 嘗試其他格式
 			t, err2 := time.Parse("2006-1-2", date)
@@ -151,7 +194,27 @@ func main() {
 				errorCount++
 				continue
 			}
-			date = t.Format("2006-01-02")
+			day, date = t, t.Format("2006-01-02")
+		}
+
+		// 與新增對局相同：賽季代碼必須與日期相符（e.g. 月初打的對局記成上一季），不符的不匯入
+		seasonCode, err = repo.ResolveSeason(store.Seasons(), game, seasonCode, day)
+		var mismatch *season.MismatchError
+		if errors.As(err, &mismatch) {
+			log.Printf("[%d] %v", i+1, err)
+			mismatchCount++
+			errorCount++
+			continue
+		}
+		if errors.Is(err, repo.ErrNoSeasonForDate) {
+			log.Printf("[%d] %s 沒有賽季曆，Season 欄位不可空白", i+1, game.Key)
+			errorCount++
+			continue
+		}
+		if err != nil {
+			log.Printf("[%d] 無法決定賽季: %v", i+1, err)
+			errorCount++
+			continue
 		}
 
 		// 處理副軸為空的情況
@@ -165,25 +228,53 @@ func main() {
 		// 取得或建立賽季 (使用 CSV 中的 seasonCode)
 		seasonID, ok := seasonCache[seasonCode]
 		if !ok {
-			err := db.QueryRow("SELECT id FROM seasons WHERE code = ? AND game_id = ?", seasonCode, gameID).Scan(&seasonID)
-			if err != nil {
-				// 建立新賽季
+			existing, err := store.Seasons().GetByCode(gameID, seasonCode)
+			seasonID = existing.ID
+			if err != nil && !errors.Is(err, repo.ErrNotFound) {
+				log.Printf("[%d] 查詢賽季失敗: %v", i+1, err)
+				errorCount++
+				continue
+			}
+			if errors.Is(err, repo.ErrNotFound) {
+				// 建立新賽季（起訖日依賽季曆推算，無法推算時以這筆對局的日期代替）
 				seasonID = "season-" + strings.ToLower(seasonCode)
-				_, err = db.Exec(`
-					INSERT INTO seasons (id, game_id, code, start_date, end_date)
-					VALUES (?, ?, ?, ?, ?)
-				`, seasonID, gameID, seasonCode, date, date)
+				start, end, ok := season.Dates(gameKey, seasonCode)
+				if !ok {
+					start, end = date, date
+				}
+				err = store.Seasons().Create(models.Season{
+					ID: seasonID, GameID: gameID, Code: seasonCode, StartDate: &start, EndDate: &end,
+				})
 				if err != nil {
+					// 不快取：之後同一賽季的對局會再嘗試建立，而不是指向不存在的賽季
 					log.Printf("[%d] 建立賽季失敗: %v", i+1, err)
-				} else {
-					log.Printf("  → 建立賽季: %s", seasonCode)
+					errorCount++
+					continue
 				}
+				log.Printf("  → 建立賽季: %s", seasonCode)
 			}
 			seasonCache[seasonCode] = seasonID
 		}
 
+		// 取得或建立遊戲帳號（Account 欄位空白時不指定帳號）
+		var accountID *string
+		if accountName != "" {
+			id, ok := accountCache[accountName]
+			if !ok {
+				id, err = store.Accounts().GetOrCreate(userID, gameID, accountName)
+				if err != nil {
+					log.Printf("[%d] 建立遊戲帳號失敗: %v", i+1, err)
+					errorCount++
+					continue
+				}
+				accountCache[accountName] = id
+				log.Printf("  → 遊戲帳號: %s", accountName)
+			}
+			accountID = &id
+		}
+
 		// 取得或建立我方牌組
-		myDeckID, err := findOrCreateDeck(db, gameID, myMain, mySub)
+		myDeckID, err := store.Decks().FindOrCreate(gameID, myMain, &mySub)
 		if err != nil {
 			log.Printf("[%d] 建立我方牌組失敗: %v", i+1, err)
 			errorCount++
@@ -191,7 +282,7 @@ func main() {
 		}
 
 		// 取得或建立對手牌組
-		oppDeckID, err := findOrCreateDeck(db, gameID, oppMain, oppSub)
+		oppDeckID, err := store.Decks().FindOrCreate(gameID, oppMain, &oppSub)
 		if err != nil {
 			log.Printf("[%d] 建立對手牌組失敗: %v", i+1, err)
 			errorCount++
@@ -199,18 +290,21 @@ func main() {
 		}
 
 		// 插入對局記錄
-		matchID := uuid.New().String()
-		_, err = db.Exec(`
-			INSERT INTO matches (
-				id, user_id, game_id, season_id, date, mode, rank,
-				my_deck_id, opp_deck_id, play_order, result, note,
-				created_at, updated_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`,
-			matchID, userID, gameID, seasonID, date, mode, rank,
-			myDeckID, oppDeckID, playOrder, result, note,
-			time.Now(), time.Now(),
-		)
+		err = store.Matches().Create(models.Match{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			GameID:    gameID,
+			SeasonID:  seasonID,
+			AccountID: accountID,
+			Date:      date,
+			Mode:      mode,
+			Rank:      rank,
+			MyDeckID:  myDeckID,
+			OppDeckID: oppDeckID,
+			PlayOrder: playOrder,
+			Result:    result,
+			Note:      &note,
+		})
 		if err != nil {
 			log.Printf("[%d] 插入對局失敗: %v", i+1, err)
 			errorCount++
@@ -226,16 +320,23 @@ func main() {
 	log.Printf("\n========== 匯入完成 ==========")
 	log.Printf("成功: %d 筆", successCount)
 	log.Printf("失敗: %d 筆", errorCount)
+	if mismatchCount > 0 {
+		log.Printf("  其中賽季與日期不符: %d 筆（請修正 CSV 的 Season 欄位，或留白由日期推算）", mismatchCount)
+	}
 
 	// 顯示賽季統計
 	log.Println("\n賽季統計:")
-	rows, _ := db.Query(`
-		SELECT s.code, COUNT(m.id) as cnt 
-		FROM seasons s 
-		LEFT JOIN matches m ON s.id = m.season_id 
-		GROUP BY s.id 
+	rows, err := db.Query(`
+		SELECT s.code, COUNT(m.id) as cnt
+		FROM seasons s
+		LEFT JOIN matches m ON s.id = m.season_id AND m.user_id = ?
+		WHERE s.game_id = ?
+		GROUP BY s.id, s.code
 		ORDER BY s.code DESC
-	`)
+	`, userID, gameID)
+	if err != nil {
+		log.Fatal("查詢賽季統計失敗:", err)
+	}
 	for rows.Next() {
 		var code string
 		var cnt int
@@ -247,51 +348,43 @@ func main() {
 	log.Println("================================")
 }
 
-// findOrCreateDeck 尋找或建立牌組
-func findOrCreateDeck(db *sql.DB, gameID, main, sub string) (string, error) {
-	var deckID string
-
-	// 先嘗試尋找
-	err := db.QueryRow(
-		"SELECT id FROM decks WHERE game_id = ? AND main = ? AND sub = ?",
-		gameID, main, sub,
-	).Scan(&deckID)
-	if err == nil {
-		return deckID, nil // 找到了
-	}
-
-	// 沒找到，建立新的
-	deckID = uuid.New().String()
-	_, err = db.Exec(
-		"INSERT INTO decks (id, game_id, main, sub) VALUES (?, ?, ?, ?)",
-		deckID, gameID, main, sub,
-	)
+// clearImportData 清空 userID 在 gameID 的對局，並移除因此不再被使用的牌組、賽季與牌組模板。
+// 其他使用者與其他遊戲的資料不受影響；keepSeason（當季）即使沒有對局也保留。
+func clearImportData(db *storage.DB, userID, gameID, keepSeason string) error {
+	tx, err := db.Begin()
 	if err != nil {
-		return "", err
+		return err
 	}
-
-	// 確保 deck_templates 中有這個牌組（用於顏色顯示）
-	ensureDeckTemplate(db, gameID, main)
-	if sub != "" && sub != "無" {
-		ensureDeckTemplate(db, gameID, sub)
+	defer tx.Rollback()
+
+	steps := []struct {
+		name  string
+		query string
+		args  []interface{}
+	}{
+		{"matches", "DELETE FROM matches WHERE user_id = ? AND game_id = ?", []interface{}{userID, gameID}},
+		{"decks", `DELETE FROM decks WHERE game_id = ?
+			AND NOT EXISTS (SELECT 1 FROM matches WHERE my_deck_id = decks.id OR opp_deck_id = decks.id)`, []interface{}{gameID}},
+		{"seasons", `DELETE FROM seasons WHERE game_id = ? AND code != ?
+			AND NOT EXISTS (SELECT 1 FROM matches WHERE season_id = seasons.id)`, []interface{}{gameID, keepSeason}},
+		// 其他使用者的牌組仍在使用的模板保留（匯入時會依 CSV 的牌組重新建立）
+		{"deck_templates", `DELETE FROM deck_templates WHERE game_id = ?
+			AND NOT EXISTS (SELECT 1 FROM decks WHERE decks.game_id = deck_templates.game_id
+				AND (decks.main = deck_templates.main OR decks.sub = deck_templates.main))`, []interface{}{gameID}},
 	}
-
-	return deckID, nil
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+	return tx.Commit()
 }
 
-// ensureDeckTemplate 確保牌組模板存在
-func ensureDeckTemplate(db *sql.DB, gameID, deckName string) {
-	var exists bool
-	db.QueryRow("SELECT EXISTS(SELECT 1 FROM deck_templates WHERE main = ?)", deckName).Scan(&exists)
-	if exists {
-		return
+// currentSeasonCode game 目前的賽季代碼（資料庫中的起訖日優先，其次依賽季曆）；推算不出來時回傳空字串
+func currentSeasonCode(seasons repo.SeasonRepository, game models.Game) string {
+	code, err := repo.ResolveSeason(seasons, game, "", time.Now())
+	if err != nil {
+		return ""
 	}
-
-	// 建立新模板（預設主題為「無」= 灰色）
-	templateID := "tpl-auto-" + uuid.New().String()[:8]
-	db.Exec(`
-		INSERT INTO deck_templates (id, game_id, main, theme, deck_type, created_at)
-		VALUES (?, ?, ?, '無', 'main', CURRENT_TIMESTAMP)
-	`, templateID, gameID, deckName)
-	log.Printf("  → 自動建立牌組模板: %s (主題: 無)", deckName)
+	return code
 }
@@ -0,0 +1,113 @@
+// ladder-sim 天梯模擬（與 GET /stats/ladder-sim 相同）：以最近的對局推算目前階級與先後攻勝率，
+// 模擬到達目標階級（預設大師 V）需要的場數，印出百分位數與分布圖。
+//
+// 對象為 -email 指定的帳號，未指定時為預設使用者；可用 -from / -first-win-rate 等覆蓋由對局推算的值。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/stats"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+func main() {
+	var (
+		email string
+		f     = models.MatchFilter{}
+		opts  = stats.NewLadderOptions()
+	)
+	flag.StringVar(&email, "email", "", "simulate for this user (default: the default user)")
+	flag.StringVar(&f.GameKey, "game-key", models.DefaultGameKey, "simulate the ladder of this game")
+	flag.StringVar(&f.AccountID, "account", "", "only use the matches of this game account ID")
+	flag.StringVar(&f.SeasonCode, "season", "", "only use the matches of this season, e.g. S49")
+	flag.StringVar(&opts.From, "from", "", `current rank, e.g. "diamond-2" or "鑽石 II" (default: the rank of the latest match)`)
+	flag.StringVar(&opts.Target, "target", opts.Target, `target rank, e.g. "master-5" or "大師 V"`)
+	flag.IntVar(&opts.Recent, "recent", opts.Recent, "estimate win rates from this many latest matches")
+	flag.IntVar(&opts.MinSample, "min-sample", opts.MinSample, "warn when a rate is estimated from fewer matches than this")
+	flag.Func("first-rate", "chance of going first, 0-100 (default: from matches)", percentFlag(&opts.FirstRate))
+	flag.Func("first-win-rate", "win rate going first, 0-100 (default: from matches)", percentFlag(&opts.FirstWinRate))
+	flag.Func("second-win-rate", "win rate going second, 0-100 (default: from matches)", percentFlag(&opts.SecondWinRate))
+	flag.IntVar(&opts.Runs, "runs", opts.Runs, "number of simulated climbs")
+	flag.IntVar(&opts.MaxGames, "max-games", opts.MaxGames, "give up a climb after this many games")
+	flag.Int64Var(&opts.Seed, "seed", 0, "random seed (default: random)")
+	flag.Parse()
+
+	opts, err := opts.Validate(f.GameKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	store := sqlrepo.New(db)
+
+	if email == "" {
+		f.UserID, err = store.Users().DefaultID()
+	} else {
+		var user models.User
+		user, err = store.Users().GetByEmail(email)
+		f.UserID = user.ID
+	}
+	if err != nil {
+		log.Fatalf("找不到使用者 %s: %v", email, err)
+	}
+
+	sim, err := stats.SimulateLadder(context.Background(), db, f, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r := rules.For(f.GameKey)
+	rank := func(code string) string { return rules.Label(r, rules.FieldRank, code, rules.LangZhTW) }
+	fmt.Printf("%s → %s（模擬 %d 次，seed %d）\n", rank(sim.From), rank(sim.Target), sim.Runs, sim.Seed)
+	fmt.Printf("樣本: 最近 %d 場（先攻 %d 場、後攻 %d 場）\n", sim.Sample.Games, sim.Sample.First, sim.Sample.Second)
+	fmt.Printf("先攻機率 %.1f%%，先攻勝率 %.1f%%（平手 %.1f%%），後攻勝率 %.1f%%（平手 %.1f%%）\n\n",
+		sim.Rates.FirstRate, sim.Rates.FirstWinRate, sim.Rates.FirstDrawRate, sim.Rates.SecondWinRate, sim.Rates.SecondDrawRate)
+	if sim.LowConfidence {
+		fmt.Printf("⚠ %s\n\n", sim.Warning)
+	}
+
+	fmt.Printf("%d 場內到達: %.1f%%\n", sim.MaxGames, sim.ReachedRate)
+	if sim.Mean == nil {
+		return
+	}
+	fmt.Printf("平均 %.1f 場（最少 %d、最多 %d）\n", *sim.Mean, *sim.Min, *sim.Max)
+	for _, p := range sim.Percentiles {
+		games := "未到達"
+		if p.Games != nil {
+			games = fmt.Sprintf("%d 場", *p.Games)
+		}
+		fmt.Printf("  P%-3d %s\n", p.P, games)
+	}
+
+	fmt.Println("\n場數分布:")
+	for _, b := range sim.Histogram {
+		fmt.Printf("  %5d-%-5d %5.1f%% %s\n", b.From, b.To, b.Pct, strings.Repeat("█", int(b.Pct/2+0.5)))
+	}
+}
+
+// percentFlag 解析 0-100 的比率並寫入 dst
+func percentFlag(dst **float64) func(string) error {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.New("必須是數字")
+		}
+		*dst = &f
+		return nil
+	}
+}
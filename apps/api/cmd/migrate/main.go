@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/harvc/duellog/apps/api/migrate"
+	"github.com/harvc/duellog/apps/api/migrations"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: go run ./cmd/migrate [flags] <status|up|down>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "  status  列出每個 migration 的套用狀態")
+	fmt.Fprintln(os.Stderr, "  up      套用所有尚未套用的 migration")
+	fmt.Fprintln(os.Stderr, "  down    回滾最近的 migration（預設 1 個，可用 -steps 指定）")
+	fmt.Fprintln(os.Stderr, "")
+	flag.PrintDefaults()
+}
+
+func main() {
+	var (
+		dbPath      string
+		databaseURL string
+		dir         string
+		steps       int
+	)
+
+	flag.StringVar(&dbPath, "db", os.Getenv("DB_PATH"), "path to sqlite db (default: DB_PATH env or ./duellog.db)")
+	flag.StringVar(&databaseURL, "database-url", os.Getenv("DATABASE_URL"), "postgres connection URL; takes precedence over -db (default: DATABASE_URL env)")
+	flag.StringVar(&dir, "dir", "", "load migrations from this directory instead of the embedded set")
+	flag.IntVar(&steps, "steps", 1, "number of migrations to roll back (down only)")
+	flag.Usage = usage
+	flag.Parse()
+
+	// Allow flags after the subcommand too: `migrate down -steps 2`.
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+	if err := flag.CommandLine.Parse(flag.Args()[1:]); err != nil || flag.NArg() != 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if dbPath == "" {
+		dbPath = "./duellog.db"
+	}
+
+	db, err := storage.Open(databaseURL, dbPath)
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	var source fs.FS
+	if dir != "" {
+		source = os.DirFS(dir)
+	} else if source, err = migrations.For(db.Dialect); err != nil {
+		log.Fatalf("load embedded migrations: %v", err)
+	}
+	set, err := migrate.Load(source)
+	if err != nil {
+		log.Fatalf("load migrations: %v", err)
+	}
+	m := migrate.New(db, set)
+
+	if _, err := m.AdoptLegacy(); err != nil {
+		log.Fatalf("adopt legacy schema: %v", err)
+	}
+
+	switch command {
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			log.Fatalf("status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt
+			}
+			if s.Missing {
+				state += " (missing file)"
+			}
+			fmt.Printf("  %s_%-30s %s\n", s.Version, s.Name, state)
+		}
+
+	case "up":
+		applied, err := m.Up()
+		for _, mig := range applied {
+			fmt.Printf("✓ up   %s_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("已是最新版本")
+		}
+
+	case "down":
+		if steps < 1 {
+			log.Fatal("-steps 必須 >= 1")
+		}
+		rolledBack, err := m.Down(steps)
+		for _, mig := range rolledBack {
+			fmt.Printf("✓ down %s_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("沒有可回滾的 migration")
+		}
+
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
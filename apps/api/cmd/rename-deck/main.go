@@ -2,24 +2,25 @@ package main
 
 import (
 	"bufio"
-	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
-var db *sql.DB
+var store repo.Store
 
 func main() {
-	var err error
-	db, err = sql.Open("sqlite3", "./duellog.db")
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
+	store = sqlrepo.New(db)
 
 	if len(os.Args) < 3 {
 		// 互動模式
@@ -27,22 +28,22 @@ func main() {
 		fmt.Println("用法: go run main.go <舊名稱> <新名稱>")
 		fmt.Println("")
 		fmt.Println("或者輸入要重命名的牌組：")
-		
+
 		reader := bufio.NewReader(os.Stdin)
-		
+
 		fmt.Print("舊名稱: ")
 		oldName, _ := reader.ReadString('\n')
 		oldName = strings.TrimSpace(oldName)
-		
+
 		fmt.Print("新名稱: ")
 		newName, _ := reader.ReadString('\n')
 		newName = strings.TrimSpace(newName)
-		
+
 		if oldName == "" || newName == "" {
 			fmt.Println("名稱不能為空")
 			return
 		}
-		
+
 		renameDeck(oldName, newName)
 	} else {
 		oldName := os.Args[1]
@@ -53,59 +54,42 @@ func main() {
 
 func renameDeck(oldName, newName string) {
 	fmt.Printf("\n重命名: [%s] → [%s]\n", oldName, newName)
-	
+
 	// 檢查舊名稱是否存在
-	var count int
-	db.QueryRow("SELECT COUNT(*) FROM decks WHERE main = ? OR sub = ?", oldName, oldName).Scan(&count)
+	count, _ := store.Decks().CountByName(oldName)
 	if count == 0 {
 		// 也檢查 deck_templates
-		db.QueryRow("SELECT COUNT(*) FROM deck_templates WHERE main = ?", oldName).Scan(&count)
-		if count == 0 {
+		mainExists, _ := store.Templates().Exists("", oldName, "main")
+		subExists, _ := store.Templates().Exists("", oldName, "sub")
+		if !mainExists && !subExists {
 			fmt.Printf("  ⚠️ 找不到名稱為 [%s] 的牌組\n", oldName)
 			return
 		}
 	}
-	
-	// 開始事務
-	tx, err := db.Begin()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer tx.Rollback()
-	
-	// 1. 更新 deck_templates
-	result, err := tx.Exec("UPDATE deck_templates SET main = ? WHERE main = ?", newName, oldName)
-	if err != nil {
-		fmt.Printf("  ❌ 更新 deck_templates 失敗: %v\n", err)
-		return
-	}
-	rows1, _ := result.RowsAffected()
-	fmt.Printf("  ✓ deck_templates: %d 筆\n", rows1)
-	
-	// 2. 更新 decks 表的 main 欄位
-	result, err = tx.Exec("UPDATE decks SET main = ? WHERE main = ?", newName, oldName)
-	if err != nil {
-		fmt.Printf("  ❌ 更新 decks.main 失敗: %v\n", err)
-		return
-	}
-	rows2, _ := result.RowsAffected()
-	fmt.Printf("  ✓ decks.main: %d 筆\n", rows2)
-	
-	// 3. 更新 decks 表的 sub 欄位
-	result, err = tx.Exec("UPDATE decks SET sub = ? WHERE sub = ?", newName, oldName)
+
+	// 在同一個事務內更新 deck_templates 與 decks
+	var rows1, rows2, rows3 int64
+	err := store.InTx(func(tx repo.Store) error {
+		var err error
+		// 1. 更新 deck_templates
+		if rows1, err = tx.Templates().Rename(oldName, newName); err != nil {
+			return fmt.Errorf("更新 deck_templates 失敗: %w", err)
+		}
+		fmt.Printf("  ✓ deck_templates: %d 筆\n", rows1)
+
+		// 2. 更新 decks 表的 main / sub 欄位
+		if rows2, rows3, err = tx.Decks().Rename(oldName, newName); err != nil {
+			return fmt.Errorf("更新 decks 失敗: %w", err)
+		}
+		fmt.Printf("  ✓ decks.main: %d 筆\n", rows2)
+		fmt.Printf("  ✓ decks.sub: %d 筆\n", rows3)
+		return nil
+	})
 	if err != nil {
-		fmt.Printf("  ❌ 更新 decks.sub 失敗: %v\n", err)
+		fmt.Printf("  ❌ %v\n", err)
 		return
 	}
-	rows3, _ := result.RowsAffected()
-	fmt.Printf("  ✓ decks.sub: %d 筆\n", rows3)
-	
-	// 提交事務
-	if err := tx.Commit(); err != nil {
-		fmt.Printf("  ❌ 提交失敗: %v\n", err)
-		return
-	}
-	
+
 	total := rows1 + rows2 + rows3
 	fmt.Printf("\n✅ 重命名完成！共更新 %d 筆記錄\n", total)
 }
@@ -1,17 +1,16 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"os"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/harvc/duellog/apps/api/storage"
 )
 
 func main() {
-	// 連接資料庫
-	db, err := sql.Open("sqlite3", "./duellog.db")
+	// 連接資料庫：設定 DATABASE_URL 時使用 PostgreSQL（seed.sql 兩種資料庫都能用）
+	db, err := storage.Open(os.Getenv("DATABASE_URL"), "./duellog.db")
 	if err != nil {
 		log.Fatal("Failed to open database:", err)
 	}
@@ -32,20 +31,17 @@ func main() {
 	fmt.Println("✅ Seed 資料插入成功！")
 
 	// 顯示統計
-	var count int
-	
-	db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
-	fmt.Printf("   Users: %d\n", count)
-	
-	db.QueryRow("SELECT COUNT(*) FROM games").Scan(&count)
-	fmt.Printf("   Games: %d\n", count)
-	
-	db.QueryRow("SELECT COUNT(*) FROM seasons").Scan(&count)
-	fmt.Printf("   Seasons: %d\n", count)
-	
-	db.QueryRow("SELECT COUNT(*) FROM decks").Scan(&count)
-	fmt.Printf("   Decks: %d\n", count)
-	
-	db.QueryRow("SELECT COUNT(*) FROM matches").Scan(&count)
-	fmt.Printf("   Matches: %d\n", count)
+	for _, table := range []struct{ label, name string }{
+		{"Users", "users"},
+		{"Games", "games"},
+		{"Seasons", "seasons"},
+		{"Decks", "decks"},
+		{"Matches", "matches"},
+	} {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table.name).Scan(&count); err != nil {
+			log.Fatalf("Failed to count %s: %v", table.name, err)
+		}
+		fmt.Printf("   %s: %d\n", table.label, count)
+	}
 }
@@ -7,6 +7,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 )
 
 func main() {
@@ -14,7 +16,7 @@ func main() {
 		"gameKey":    "master_duel",
 		"seasonCode": "S49",
 		"date":       "2026-01-13",
-		"rank":       "鑽石 I",
+		"rank":       map[string]interface{}{"tier": "diamond", "level": 1},
 		"myDeck": map[string]interface{}{
 			"main": "蛇眼",
 			"sub":  nil,
@@ -23,14 +25,28 @@ func main() {
 			"main": "天盃",
 			"sub":  nil,
 		},
-		"playOrder": "先攻",
+		"playOrder": "first",
 		"result":    "W",
 	}
 
 	jsonData, _ := json.Marshal(payload)
 	fmt.Printf("發送資料: %s\n", string(jsonData))
 
-	resp, err := http.Post("http://localhost:8080/matches", "application/json", bytes.NewReader(jsonData))
+	// DUELLOG_URL：API 位址（預設本機）；DUELLOG_API_KEY：對共用伺服器送出時使用的 API key（需 write 權限）
+	baseURL := strings.TrimRight(os.Getenv("DUELLOG_URL"), "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/matches", bytes.NewReader(jsonData))
+	if err != nil {
+		log.Fatal("建立請求失敗:", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey := os.Getenv("DUELLOG_API_KEY"); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		log.Fatal("請求失敗:", err)
 	}
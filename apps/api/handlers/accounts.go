@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/auth"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+)
+
+// AccountsHandler 處理遊戲帳號（同一個使用者可以有多個遊戲帳號）
+type AccountsHandler struct {
+	store repo.Store
+}
+
+// NewAccountsHandler 建立新的 accounts handler
+func NewAccountsHandler(store repo.Store) *AccountsHandler {
+	return &AccountsHandler{store: store}
+}
+
+// CreateAccountRequest 新增遊戲帳號的請求結構
+type CreateAccountRequest struct {
+	GameKey string `json:"gameKey"` // e.g. "master_duel"（預設）
+	Name    string `json:"name"`
+}
+
+// GetAccounts 列出自己的遊戲帳號 (GET /accounts)
+// 可加上 gameKey 只列出某款遊戲的帳號。
+func (h *AccountsHandler) GetAccounts(c *fiber.Ctx) error {
+	gameID := ""
+	if key := c.Query("gameKey"); key != "" {
+		game, err := h.store.Games().GetByKey(key)
+		if err != nil {
+			return gameLookupError(c, key, err)
+		}
+		gameID = game.ID
+	}
+
+	accounts, err := h.store.Accounts().List(auth.UserID(c), gameID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"accounts": accounts,
+		"total":    len(accounts),
+	})
+}
+
+// CreateAccount 新增遊戲帳號 (POST /accounts)
+func (h *AccountsHandler) CreateAccount(c *fiber.Ctx) error {
+	var req CreateAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "name 為必填"})
+	}
+	req.GameKey = gameKeyOrDefault(req.GameKey)
+
+	game, err := h.store.Games().GetByKey(req.GameKey)
+	if err != nil {
+		return gameLookupError(c, req.GameKey, err)
+	}
+
+	userID := auth.UserID(c)
+	account := models.Account{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		GameID: game.ID,
+		Name:   req.Name,
+	}
+	if err := h.store.Accounts().Create(account); err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			return c.Status(409).JSON(fiber.Map{"error": "帳號名稱已存在"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "新增失敗", "details": err.Error()})
+	}
+
+	created, err := h.store.Accounts().Get(userID, account.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "新增失敗", "details": err.Error()})
+	}
+	return c.Status(201).JSON(created)
+}
+
+// DeleteAccount 刪除遊戲帳號 (DELETE /accounts/:id)
+// 仍有對局使用的帳號不能刪除。
+func (h *AccountsHandler) DeleteAccount(c *fiber.Ctx) error {
+	id := c.Params("id")
+	err := h.store.Accounts().Delete(auth.UserID(c), id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "找不到遊戲帳號"})
+	}
+	if errors.Is(err, repo.ErrConflict) {
+		return c.Status(409).JSON(fiber.Map{"error": "仍有對局使用此帳號，無法刪除"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "刪除失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"message": "帳號刪除成功",
+		"id":      id,
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/auth"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+)
+
+// APIKeysHandler 管理個人 API key（給腳本 / bot 使用）
+//
+// 只能以登入（或單人模式的預設使用者）管理；用 API key 本身呼叫這些端點會回傳 403，
+// 避免外流的 key 被拿來建立新的 key。
+type APIKeysHandler struct {
+	keys repo.APIKeyRepository
+}
+
+// NewAPIKeysHandler 建立新的 api keys handler
+func NewAPIKeysHandler(keys repo.APIKeyRepository) *APIKeysHandler {
+	return &APIKeysHandler{keys: keys}
+}
+
+// CreateAPIKeyRequest 建立 API key 的請求結構
+type CreateAPIKeyRequest struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"` // "read" | "write"（預設 read）
+}
+
+// GetAPIKeys 列出自己的 API key (GET /api-keys)
+func (h *APIKeysHandler) GetAPIKeys(c *fiber.Ctx) error {
+	if auth.ViaAPIKey(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "API key 不能用來管理 API key，請先登入"})
+	}
+
+	keys, err := h.keys.ListByUser(auth.UserID(c))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"apiKeys": keys,
+		"total":   len(keys),
+	})
+}
+
+// CreateAPIKey 建立 API key (POST /api-keys)
+// 明碼只會在這個回應中出現一次（key 欄位），之後只能看到前綴。
+func (h *APIKeysHandler) CreateAPIKey(c *fiber.Ctx) error {
+	if auth.ViaAPIKey(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "API key 不能用來管理 API key，請先登入"})
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "name 為必填"})
+	}
+	if req.Scope == "" {
+		req.Scope = auth.ScopeRead
+	}
+	if !auth.ValidScope(req.Scope) {
+		return c.Status(400).JSON(fiber.Map{"error": "scope 必須是 read 或 write"})
+	}
+
+	plain, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "建立失敗", "details": err.Error()})
+	}
+	key := models.APIKey{
+		ID:      uuid.New().String(),
+		UserID:  auth.UserID(c),
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: hash,
+		Scope:   req.Scope,
+	}
+	if err := h.keys.Create(key); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "建立失敗", "details": err.Error()})
+	}
+
+	created, err := h.keys.GetByHash(hash)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "建立失敗", "details": err.Error()})
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"apiKey": created,
+		"key":    plain,
+	})
+}
+
+// RevokeAPIKey 撤銷 API key (DELETE /api-keys/:id)
+func (h *APIKeysHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if auth.ViaAPIKey(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "API key 不能用來管理 API key，請先登入"})
+	}
+
+	id := c.Params("id")
+	err := h.keys.Revoke(auth.UserID(c), id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "找不到 API key"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "撤銷失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"message": "API key 已撤銷",
+		"id":      id,
+	})
+}
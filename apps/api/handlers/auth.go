@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/auth"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/season"
+)
+
+// AuthHandler 處理註冊 / 登入與使用者設定
+type AuthHandler struct {
+	store  repo.Store
+	tokens *auth.Tokens
+}
+
+// NewAuthHandler 建立新的 auth handler
+func NewAuthHandler(store repo.Store, tokens *auth.Tokens) *AuthHandler {
+	return &AuthHandler{store: store, tokens: tokens}
+}
+
+// CredentialsRequest 註冊 / 登入的請求結構
+type CredentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Timezone string `json:"timezone"` // 註冊時可選，IANA 時區（預設 UTC）
+}
+
+// UpdateMeRequest 更新使用者設定的請求結構
+type UpdateMeRequest struct {
+	Timezone string `json:"timezone"` // IANA 時區，e.g. "Asia/Taipei"
+}
+
+// Register 註冊 (POST /auth/register)
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	var req CredentialsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+
+	email := normalizeEmail(req.Email)
+	if _, err := mail.ParseAddress(email); err != nil || email == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "email 格式錯誤"})
+	}
+	if len(req.Password) < auth.MinPasswordLength {
+		return c.Status(400).JSON(fiber.Map{"error": "密碼至少需要 8 個字元"})
+	}
+	if req.Timezone == "" {
+		req.Timezone = models.DefaultTimezone
+	}
+	if _, err := season.LoadLocation(req.Timezone); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "無效的時區", "timezone": req.Timezone})
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "註冊失敗", "details": err.Error()})
+	}
+
+	user := models.User{
+		ID:           uuid.New().String(),
+		Email:        email,
+		PasswordHash: hash,
+		Timezone:     req.Timezone,
+	}
+	if _, err := h.store.Users().GetByEmail(email); err == nil {
+		return c.Status(409).JSON(fiber.Map{"error": "email 已被註冊"})
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		return c.Status(500).JSON(fiber.Map{"error": "註冊失敗", "details": err.Error()})
+	}
+	if err := h.store.Users().Create(user); err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			return c.Status(409).JSON(fiber.Map{"error": "email 已被註冊"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "註冊失敗", "details": err.Error()})
+	}
+
+	created, err := h.store.Users().GetByID(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "註冊失敗", "details": err.Error()})
+	}
+	return h.respondWithToken(c.Status(201), created)
+}
+
+// Login 登入 (POST /auth/login)
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req CredentialsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+
+	user, err := h.store.Users().GetByEmail(normalizeEmail(req.Email))
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return c.Status(500).JSON(fiber.Map{"error": "登入失敗", "details": err.Error()})
+	}
+	// 帳號不存在與密碼錯誤回傳同樣的訊息
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		return c.Status(401).JSON(fiber.Map{"error": "帳號或密碼錯誤"})
+	}
+
+	return h.respondWithToken(c, user)
+}
+
+// Me 目前登入的使用者 (GET /auth/me)
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	user, err := h.store.Users().GetByID(auth.UserID(c))
+	if errors.Is(err, repo.ErrNotFound) {
+		return c.Status(401).JSON(fiber.Map{"error": "需要登入"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢使用者失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"user": user})
+}
+
+// UpdateMe 更新使用者設定 (PATCH /auth/me)
+//
+// 修改時區時，有 playedAt 的對局會依新時區重新計算當地日期（每日統計依此分組）。
+func (h *AuthHandler) UpdateMe(c *fiber.Ctx) error {
+	var req UpdateMeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	req.Timezone = strings.TrimSpace(req.Timezone)
+	if req.Timezone == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "沒有要更新的欄位"})
+	}
+	loc, err := season.LoadLocation(req.Timezone)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "無效的時區", "timezone": req.Timezone})
+	}
+
+	userID := auth.UserID(c)
+	var relocalized int64
+	err = h.store.InTx(func(tx repo.Store) error {
+		if err := tx.Users().UpdateTimezone(userID, loc.String()); err != nil {
+			return err
+		}
+		relocalized, err = tx.Matches().Relocalize(userID, loc)
+		return err
+	})
+	if errors.Is(err, repo.ErrNotFound) {
+		return c.Status(401).JSON(fiber.Map{"error": "需要登入"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "更新失敗", "details": err.Error()})
+	}
+
+	user, err := h.store.Users().GetByID(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢使用者失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"user": user, "relocalizedMatches": relocalized})
+}
+
+// respondWithToken 簽發 token 並回傳 {token, expiresIn, user}
+func (h *AuthHandler) respondWithToken(c *fiber.Ctx, user models.User) error {
+	token, err := h.tokens.Issue(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "簽發 token 失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"token":     token,
+		"expiresIn": int(h.tokens.TTL().Seconds()),
+		"user":      user,
+	})
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -1,24 +1,17 @@
 package handlers
 
 import (
-	"database/sql"
-	"time"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
 )
 
-// DeckTemplate 牌組模板（前端選項用）
-type DeckTemplate struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Theme     string    `json:"theme"`
-	DeckType  string    `json:"deckType"` // "main" or "sub"
-	CreatedAt time.Time `json:"createdAt"`
-}
-
 // CreateDeckTemplateRequest 新增牌組模板請求
 type CreateDeckTemplateRequest struct {
+	GameKey  string `json:"gameKey"` // e.g. "master_duel"（預設）
 	Name     string `json:"name"`
 	Theme    string `json:"theme"`
 	DeckType string `json:"deckType"` // "main" or "sub"
@@ -30,64 +23,33 @@ type UpdateDeckTemplateRequest struct {
 	Theme string `json:"theme,omitempty"`
 }
 
-// GetDeckTemplates 取得所有牌組模板
-func GetDeckTemplates(c *fiber.Ctx, db *sql.DB) error {
+// GetDeckTemplates 取得某款遊戲（gameKey，預設 master_duel）的所有牌組模板
+func GetDeckTemplates(c *fiber.Ctx, store repo.Store) error {
 	deckType := c.Query("type", "") // "main", "sub", or "" for all
 
-	var query string
-	var args []interface{}
-
-	if deckType != "" {
-		query = `
-			SELECT id, main as name, theme, deck_type, created_at
-			FROM deck_templates
-			WHERE deck_type = ?
-			ORDER BY name ASC
-		`
-		args = append(args, deckType)
-	} else {
-		query = `
-			SELECT id, main as name, theme, deck_type, created_at
-			FROM deck_templates
-			ORDER BY deck_type ASC, name ASC
-		`
-	}
-
-	rows, err := db.Query(query, args...)
+	key := gameKeyOrDefault(c.Query("gameKey"))
+	game, err := store.Games().GetByKey(key)
+	if err != nil {
+		return gameLookupError(c, key, err)
+	}
+
+	list, err := store.Templates().List(game.ID, deckType)
 	if err != nil {
 		// 如果表不存在，返回空陣列
 		return c.JSON(fiber.Map{
-			"templates": []DeckTemplate{},
+			"templates": []models.DeckTemplate{},
 			"total":     0,
 		})
 	}
-	defer rows.Close()
-
-	var templates []DeckTemplate
-	for rows.Next() {
-		var t DeckTemplate
-		var createdAt sql.NullTime
-		if err := rows.Scan(&t.ID, &t.Name, &t.Theme, &t.DeckType, &createdAt); err != nil {
-			continue
-		}
-		if createdAt.Valid {
-			t.CreatedAt = createdAt.Time
-		}
-		templates = append(templates, t)
-	}
-
-	if templates == nil {
-		templates = []DeckTemplate{}
-	}
 
 	return c.JSON(fiber.Map{
-		"templates": templates,
-		"total":     len(templates),
+		"templates": list,
+		"total":     len(list),
 	})
 }
 
 // CreateDeckTemplate 新增牌組模板
-func CreateDeckTemplate(c *fiber.Ctx, db *sql.DB) error {
+func CreateDeckTemplate(c *fiber.Ctx, store repo.Store) error {
 	var req CreateDeckTemplateRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
@@ -103,19 +65,26 @@ func CreateDeckTemplate(c *fiber.Ctx, db *sql.DB) error {
 		req.DeckType = "main"
 	}
 
+	key := gameKeyOrDefault(req.GameKey)
+	game, err := store.Games().GetByKey(key)
+	if err != nil {
+		return gameLookupError(c, key, err)
+	}
+
 	// 檢查是否已存在
-	var exists bool
-	err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM deck_templates WHERE main = ? AND deck_type = ?)`, req.Name, req.DeckType).Scan(&exists)
+	exists, err := store.Templates().Exists(game.ID, req.Name, req.DeckType)
 	if err == nil && exists {
 		return c.Status(400).JSON(fiber.Map{"error": "Deck template already exists"})
 	}
 
 	id := uuid.New().String()
-	_, err = db.Exec(`
-		INSERT INTO deck_templates (id, game_id, main, theme, deck_type, created_at)
-		VALUES (?, 'game-md', ?, ?, ?, CURRENT_TIMESTAMP)
-	`, id, req.Name, req.Theme, req.DeckType)
-
+	err = store.Templates().Create(models.DeckTemplate{
+		ID:       id,
+		GameID:   game.ID,
+		Name:     req.Name,
+		Theme:    req.Theme,
+		DeckType: req.DeckType,
+	})
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create deck template: " + err.Error()})
 	}
@@ -126,8 +95,9 @@ func CreateDeckTemplate(c *fiber.Ctx, db *sql.DB) error {
 	})
 }
 
-// UpdateDeckTemplate 更新牌組模板
-func UpdateDeckTemplate(c *fiber.Ctx, db *sql.DB) error {
+// UpdateDeckTemplate 更新牌組模板 (PATCH /deck-templates/:id?gameKey=)
+// 只會更新 gameKey（預設 master_duel）這款遊戲的模板；其他遊戲的模板視同不存在
+func UpdateDeckTemplate(c *fiber.Ctx, store repo.Store) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "ID is required"})
@@ -138,57 +108,46 @@ func UpdateDeckTemplate(c *fiber.Ctx, db *sql.DB) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	// 建構動態更新語句
-	updates := []string{}
-	args := []interface{}{}
-
-	if req.Name != "" {
-		updates = append(updates, "main = ?")
-		args = append(args, req.Name)
-	}
-	if req.Theme != "" {
-		updates = append(updates, "theme = ?")
-		args = append(args, req.Theme)
-	}
-
-	if len(updates) == 0 {
+	if req.Name == "" && req.Theme == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "No fields to update"})
 	}
 
-	args = append(args, id)
-	query := "UPDATE deck_templates SET " + joinStrings(updates, ", ") + " WHERE id = ?"
-
-	result, err := db.Exec(query, args...)
+	key := gameKeyOrDefault(c.Query("gameKey"))
+	game, err := store.Games().GetByKey(key)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to update deck template"})
+		return gameLookupError(c, key, err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return c.Status(404).JSON(fiber.Map{"error": "Deck template not found"})
+	if err := store.Templates().Update(game.ID, id, req.Name, req.Theme); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": "Deck template not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update deck template"})
 	}
 
 	return c.JSON(fiber.Map{"message": "Deck template updated successfully"})
 }
 
-// DeleteDeckTemplate 刪除牌組模板
-func DeleteDeckTemplate(c *fiber.Ctx, db *sql.DB) error {
+// DeleteDeckTemplate 刪除牌組模板 (DELETE /deck-templates/:id?gameKey=)
+// 只會刪除 gameKey（預設 master_duel）這款遊戲的模板；其他遊戲的模板視同不存在
+func DeleteDeckTemplate(c *fiber.Ctx, store repo.Store) error {
 	id := c.Params("id")
 	if id == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "ID is required"})
 	}
 
-	result, err := db.Exec(`DELETE FROM deck_templates WHERE id = ?`, id)
+	key := gameKeyOrDefault(c.Query("gameKey"))
+	game, err := store.Games().GetByKey(key)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete deck template"})
+		return gameLookupError(c, key, err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return c.Status(404).JSON(fiber.Map{"error": "Deck template not found"})
+	if err := store.Templates().Delete(game.ID, id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": "Deck template not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete deck template"})
 	}
 
 	return c.JSON(fiber.Map{"message": "Deck template deleted successfully"})
 }
-
-// Note: joinStrings is defined in matches.go
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/season"
+)
+
+// GamesHandler 處理遊戲（master_duel 等）
+type GamesHandler struct {
+	games repo.GameRepository
+}
+
+// NewGamesHandler 建立新的 games handler
+func NewGamesHandler(games repo.GameRepository) *GamesHandler {
+	return &GamesHandler{games: games}
+}
+
+// CreateGameRequest 新增遊戲的請求結構
+type CreateGameRequest struct {
+	Key  string `json:"key"`  // e.g. "master_duel"（小寫英數與底線）
+	Name string `json:"name"` // e.g. "Yu-Gi-Oh! Master Duel"
+}
+
+// UpdateGameRequest 更新遊戲的請求結構（空字串代表不變）
+type UpdateGameRequest struct {
+	Name            string `json:"name"`
+	SeasonResetTime string `json:"seasonResetTime"` // HH:MM，e.g. "06:00"
+	SeasonTimezone  string `json:"seasonTimezone"`  // IANA 時區，e.g. "Asia/Tokyo"
+}
+
+// gameKeyPattern gameKey 會出現在網址與查詢參數中，限制為小寫英數與底線
+var gameKeyPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// GetGames 列出所有遊戲 (GET /games)
+func (h *GamesHandler) GetGames(c *fiber.Ctx) error {
+	games, err := h.games.List()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"games": games,
+		"total": len(games),
+	})
+}
+
+// CreateGame 新增遊戲 (POST /games)
+func (h *GamesHandler) CreateGame(c *fiber.Ctx) error {
+	var req CreateGameRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	req.Key = strings.TrimSpace(req.Key)
+	req.Name = strings.TrimSpace(req.Name)
+	if !gameKeyPattern.MatchString(req.Key) {
+		return c.Status(400).JSON(fiber.Map{"error": "key 只能包含小寫英數與底線"})
+	}
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "name 為必填"})
+	}
+
+	game := models.Game{
+		ID:              uuid.New().String(),
+		Key:             req.Key,
+		Name:            req.Name,
+		SeasonResetTime: models.DefaultSeasonResetTime,
+		SeasonTimezone:  models.DefaultTimezone,
+	}
+	if err := h.games.Create(game); err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			return c.Status(409).JSON(fiber.Map{"error": "遊戲已存在", "gameKey": req.Key})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "新增失敗", "details": err.Error()})
+	}
+	return c.Status(201).JSON(game)
+}
+
+// UpdateGame 更新遊戲名稱與賽季切換時間 (PATCH /games/:key)
+//
+// 賽季切換時間只影響之後寫入、有 playedAt 的對局；既有對局的賽季可用 cmd/fix-match-seasons 重新指定。
+func (h *GamesHandler) UpdateGame(c *fiber.Ctx) error {
+	key := c.Params("key")
+
+	var req UpdateGameRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.SeasonResetTime = strings.TrimSpace(req.SeasonResetTime)
+	req.SeasonTimezone = strings.TrimSpace(req.SeasonTimezone)
+	if req.Name == "" && req.SeasonResetTime == "" && req.SeasonTimezone == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "沒有要更新的欄位"})
+	}
+
+	game, err := h.games.GetByKey(key)
+	if err != nil {
+		return gameLookupError(c, key, err)
+	}
+	if req.Name != "" {
+		game.Name = req.Name
+	}
+	if req.SeasonResetTime != "" {
+		game.SeasonResetTime = req.SeasonResetTime
+	}
+	if req.SeasonTimezone != "" {
+		game.SeasonTimezone = req.SeasonTimezone
+	}
+	reset, err := season.ParseReset(game.SeasonResetTime, game.SeasonTimezone)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "賽季切換時間設定錯誤", "details": err.Error()})
+	}
+	game.SeasonResetTime = fmt.Sprintf("%02d:%02d", reset.Hour, reset.Minute)
+
+	if err := h.games.Update(game); err != nil {
+		return gameLookupError(c, key, err)
+	}
+	return c.JSON(game)
+}
+
+// GetGameRules 遊戲的對局規則 (GET /games/:key/rules)
+//
+// 回傳可用的模式（第一個為預設）、階級（由低到高）、先後攻、結果，以及記錄階級的模式；
+// 各欄位皆為代碼，顯示名稱見 labels（field → code → label，語系依 ?lang= / Accept-Language）。
+// 沒有註冊規則的遊戲 freeform 為 true，各欄位不限制。
+func (h *GamesHandler) GetGameRules(c *fiber.Ctx) error {
+	key := c.Params("key")
+	if _, err := h.games.GetByKey(key); err != nil {
+		return gameLookupError(c, key, err)
+	}
+
+	gameRules := rules.For(key)
+	return c.JSON(fiber.Map{
+		"gameKey":     key,
+		"freeform":    !rules.Registered(key),
+		"modes":       nonNil(gameRules.Modes()),
+		"defaultMode": rules.DefaultMode(gameRules),
+		"ranks":       nonNil(gameRules.Ranks()),
+		"playOrders":  nonNil(gameRules.PlayOrders()),
+		"results":     rules.Results,
+		"rankModes":   rules.RankModes(gameRules),
+		"ratingModes": rules.RatingModes(gameRules),
+		"labels":      rules.Labels(gameRules, requestLang(c)),
+	})
+}
+
+// nonNil 讓空的選項輸出成 [] 而不是 null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// gameKeyOrDefault 請求沒有指定 gameKey 時使用 models.DefaultGameKey
+func gameKeyOrDefault(key string) string {
+	if key == "" {
+		return models.DefaultGameKey
+	}
+	return key
+}
+
+// gameLookupError 查詢 / 更新遊戲失敗時的回應：找不到回傳 404，其餘 500
+func gameLookupError(c *fiber.Ctx, key string, err error) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "找不到遊戲", "gameKey": key})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": "查詢遊戲失敗", "details": err.Error()})
+}
@@ -0,0 +1,229 @@
+package handlers_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+// routeParam 路由中的參數（e.g. :id）
+var routeParam = regexp.MustCompile(`:[A-Za-z]+`)
+
+// 新增到 handlers.Register 的路由若忘了掛上 auth middleware，未登入也能存取
+func TestEveryRouteRequiresAuth(t *testing.T) {
+	s := newTestServer(t)
+	public := map[string]bool{
+		"POST /auth/register": true,
+		"POST /auth/login":    true,
+	}
+
+	checked := 0
+	for _, r := range s.app.GetRoutes(true) {
+		route := r.Method + " " + r.Path
+		if r.Method == http.MethodHead || public[route] {
+			continue
+		}
+		path := routeParam.ReplaceAllString(r.Path, "missing")
+		if status, body := s.do(r.Method, path, "", nil); status != http.StatusUnauthorized {
+			t.Errorf("%s without a token = %d %v, want 401", route, status, body)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("no routes registered")
+	}
+}
+
+func TestMatchesAreIsolatedPerUser(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		alice := s.register("alice@example.com")
+		bob := s.register("bob@example.com")
+		matchID := s.createMatch(alice, nil)
+
+		if status, body := s.do(http.MethodGet, "/matches", bob, nil); status != http.StatusOK || listLen(t, body, "matches") != 0 {
+			t.Errorf("bob GET /matches = %d %v, want 200 with no matches", status, body)
+		}
+
+		patch := map[string]interface{}{"result": "L", "note": "changed by bob"}
+		if status, body := s.do(http.MethodPatch, "/matches/"+matchID, bob, patch); status != http.StatusNotFound {
+			t.Errorf("bob PATCH alice's match = %d %v, want 404", status, body)
+		}
+		if status, body := s.do(http.MethodDelete, "/matches/"+matchID, bob, nil); status != http.StatusNotFound {
+			t.Errorf("bob DELETE alice's match = %d %v, want 404", status, body)
+		}
+
+		// alice 的對局沒有被改動
+		status, body := s.do(http.MethodGet, "/matches", alice, nil)
+		if status != http.StatusOK || listLen(t, body, "matches") != 1 {
+			t.Fatalf("alice GET /matches = %d %v, want her one match", status, body)
+		}
+		match := body["matches"].([]interface{})[0].(map[string]interface{})
+		if match["id"] != matchID || match["result"] != "W" || match["note"] != nil {
+			t.Errorf("alice's match was modified: %v", match)
+		}
+
+		if status, body := s.do(http.MethodDelete, "/matches/"+matchID, alice, nil); status != http.StatusOK {
+			t.Errorf("alice DELETE her match = %d %v, want 200", status, body)
+		}
+	})
+}
+
+func TestStatsAreIsolatedPerUser(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.register("alice@example.com")
+	bob := s.register("bob@example.com")
+	s.createMatch(alice, nil)
+	s.createMatch(alice, map[string]interface{}{"mode": "DC", "rank": "", "rating": map[string]int{"before": 1000, "after": 1010}})
+
+	lists := []struct {
+		path string
+		key  string
+	}{
+		{"/stats/daily", "daily"},
+		{"/stats/opponents", "decks"},
+		{"/stats/my-decks", "decks"},
+		{"/stats/matchups", "cells"},
+		{"/stats/rank-timeline", "seasons"},
+		{"/stats/rating", "events"},
+	}
+	for _, l := range lists {
+		if status, body := s.do(http.MethodGet, l.path, alice, nil); status != http.StatusOK || listLen(t, body, l.key) == 0 {
+			t.Errorf("alice GET %s = %d %v, want her data", l.path, status, body)
+		}
+		if status, body := s.do(http.MethodGet, l.path, bob, nil); status != http.StatusOK || listLen(t, body, l.key) != 0 {
+			t.Errorf("bob GET %s = %d %v, want 200 with no rows", l.path, status, body)
+		}
+	}
+
+	if status, body := s.do(http.MethodGet, "/stats/summary", alice, nil); status != http.StatusOK || body["total"] != float64(2) {
+		t.Errorf("alice GET /stats/summary = %d %v, want total 2", status, body)
+	}
+	if status, body := s.do(http.MethodGet, "/stats/summary", bob, nil); status != http.StatusOK || body["total"] != float64(0) {
+		t.Errorf("bob GET /stats/summary = %d %v, want total 0", status, body)
+	}
+}
+
+func TestAccountsAreIsolatedPerUser(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.register("alice@example.com")
+	bob := s.register("bob@example.com")
+
+	status, body := s.do(http.MethodPost, "/accounts", alice, map[string]string{"name": "main", "gameKey": "master_duel"})
+	if status != http.StatusCreated {
+		t.Fatalf("alice POST /accounts = %d %v", status, body)
+	}
+	accountID := body["id"].(string)
+	s.createMatch(alice, map[string]interface{}{"accountId": accountID})
+
+	if status, body := s.do(http.MethodGet, "/accounts", bob, nil); status != http.StatusOK || listLen(t, body, "accounts") != 0 {
+		t.Errorf("bob GET /accounts = %d %v, want 200 with no accounts", status, body)
+	}
+	if status, body := s.do(http.MethodGet, "/matches?accountId="+accountID, bob, nil); status != http.StatusOK || listLen(t, body, "matches") != 0 {
+		t.Errorf("bob GET /matches?accountId= = %d %v, want 200 with no matches", status, body)
+	}
+	if status, body := s.do(http.MethodGet, "/stats/summary?accountId="+accountID, bob, nil); status != http.StatusOK || body["total"] != float64(0) {
+		t.Errorf("bob GET /stats/summary?accountId= = %d %v, want total 0", status, body)
+	}
+
+	// 不能把對局記在別人的帳號上，也不能刪除別人的帳號
+	if status, body := s.do(http.MethodPost, "/matches", bob, matchRequest(map[string]interface{}{"accountId": accountID})); status != http.StatusBadRequest {
+		t.Errorf("bob POST /matches with alice's account = %d %v, want 400", status, body)
+	}
+	if status, body := s.do(http.MethodDelete, "/accounts/"+accountID, bob, nil); status != http.StatusNotFound {
+		t.Errorf("bob DELETE alice's account = %d %v, want 404", status, body)
+	}
+
+	if status, body := s.do(http.MethodGet, "/accounts", alice, nil); status != http.StatusOK || listLen(t, body, "accounts") != 1 {
+		t.Errorf("alice GET /accounts = %d %v, want her account", status, body)
+	}
+}
+
+func TestSharedRecordsRequireAdmin(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		alice := s.register("alice@example.com")
+		admin := s.register("admin@example.com")
+
+		writes := []struct {
+			method string
+			path   string
+			body   map[string]string
+		}{
+			{http.MethodPost, "/games", map[string]string{"key": "ptcg", "name": "Pokémon TCG Live"}},
+			{http.MethodPatch, "/games/master_duel", map[string]string{"name": "Renamed"}},
+			{http.MethodPost, "/seasons", map[string]string{"code": "S57"}},
+			{http.MethodPatch, "/seasons/S57", map[string]string{"startDate": "2026-09-01", "endDate": "2026-09-30"}},
+			{http.MethodPost, "/deck-templates", map[string]string{"name": "Tenpai Dragon", "gameKey": "master_duel"}},
+		}
+		for _, w := range writes {
+			if status, body := s.do(w.method, w.path, alice, w.body); status != http.StatusForbidden {
+				t.Errorf("alice %s %s = %d %v, want 403", w.method, w.path, status, body)
+			}
+		}
+		for _, w := range writes {
+			if status, body := s.do(w.method, w.path, admin, w.body); status >= 300 {
+				t.Errorf("admin %s %s = %d %v, want success", w.method, w.path, status, body)
+			}
+		}
+	})
+}
+
+func TestDeckTemplateWritesAreAdminOnlyAndScopedByGame(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		alice := s.register("alice@example.com")
+		admin := s.register("admin@example.com")
+
+		if status, body := s.do(http.MethodPost, "/games", admin, map[string]string{"key": "ptcg", "name": "Pokémon TCG Live"}); status != http.StatusCreated {
+			t.Fatalf("admin POST /games = %d %v", status, body)
+		}
+		status, body := s.do(http.MethodPost, "/deck-templates", admin, map[string]string{"name": "Tenpai Dragon", "gameKey": "master_duel"})
+		if status != http.StatusCreated {
+			t.Fatalf("admin POST /deck-templates = %d %v", status, body)
+		}
+		id := body["id"].(string)
+		path := "/deck-templates/" + id
+		rename := map[string]string{"name": "Renamed"}
+
+		if status, body := s.do(http.MethodPatch, path, alice, rename); status != http.StatusForbidden {
+			t.Errorf("alice PATCH %s = %d %v, want 403", path, status, body)
+		}
+		if status, body := s.do(http.MethodDelete, path, alice, nil); status != http.StatusForbidden {
+			t.Errorf("alice DELETE %s = %d %v, want 403", path, status, body)
+		}
+
+		// 以其他遊戲的 gameKey 存取時視同不存在
+		if status, body := s.do(http.MethodPatch, path+"?gameKey=ptcg", admin, rename); status != http.StatusNotFound {
+			t.Errorf("admin PATCH %s?gameKey=ptcg = %d %v, want 404", path, status, body)
+		}
+		if status, body := s.do(http.MethodDelete, path+"?gameKey=ptcg", admin, nil); status != http.StatusNotFound {
+			t.Errorf("admin DELETE %s?gameKey=ptcg = %d %v, want 404", path, status, body)
+		}
+		if name := s.templateName(alice, id); name != "Tenpai Dragon" {
+			t.Errorf("template renamed through another game: name = %q", name)
+		}
+
+		if status, body := s.do(http.MethodPatch, path+"?gameKey=master_duel", admin, rename); status != http.StatusOK {
+			t.Errorf("admin PATCH %s?gameKey=master_duel = %d %v, want 200", path, status, body)
+		}
+		if status, body := s.do(http.MethodDelete, path, admin, nil); status != http.StatusOK {
+			t.Errorf("admin DELETE %s = %d %v, want 200", path, status, body)
+		}
+		if name := s.templateName(alice, id); name != "" {
+			t.Errorf("template still listed after DELETE: name = %q", name)
+		}
+	})
+}
+
+// templateName 從 GET /deck-templates 取出 id 這個模板的名稱；找不到時回傳空字串
+func (s *testServer) templateName(token, id string) string {
+	s.t.Helper()
+	status, body := s.do(http.MethodGet, "/deck-templates", token, nil)
+	if status != http.StatusOK {
+		s.t.Fatalf("GET /deck-templates = %d %v", status, body)
+	}
+	for _, v := range body["templates"].([]interface{}) {
+		if tmpl := v.(map[string]interface{}); tmpl["id"] == id {
+			return tmpl["name"].(string)
+		}
+	}
+	return ""
+}
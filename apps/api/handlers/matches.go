@@ -1,157 +1,37 @@
 package handlers
 
 import (
-	"database/sql"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/auth"
 	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/season"
 )
 
 // MatchesHandler 處理 matches 相關請求
 type MatchesHandler struct {
-	db *sql.DB
+	store repo.Store
 }
 
 // NewMatchesHandler 建立新的 matches handler
-func NewMatchesHandler(db *sql.DB) *MatchesHandler {
-	return &MatchesHandler{db: db}
+func NewMatchesHandler(store repo.Store) *MatchesHandler {
+	return &MatchesHandler{store: store}
 }
 
 // GetMatches 查詢對局列表 (GET /matches)
 func (h *MatchesHandler) GetMatches(c *fiber.Ctx) error {
-	// 取得查詢參數
-	seasonCode := c.Query("seasonCode")
-	mode := c.Query("mode")
-	myDeckMain := c.Query("myDeckMain")
-	oppDeckMain := c.Query("oppDeckMain")
-	result := c.Query("result")
-	playOrder := c.Query("playOrder")
-	dateFrom := c.Query("dateFrom")
-	dateTo := c.Query("dateTo")
-
-	// 建立基礎 SQL 查詢（JOIN 取得完整資訊）
-	query := `
-		SELECT 
-			m.id,
-			m.date,
-			m.mode,
-			m.rank,
-			m.play_order,
-			m.result,
-			m.note,
-			m.created_at,
-			m.updated_at,
-			s.code as season_code,
-			my_deck.id as my_deck_id,
-			my_deck.main as my_deck_main,
-			my_deck.sub as my_deck_sub,
-			opp_deck.id as opp_deck_id,
-			opp_deck.main as opp_deck_main,
-			opp_deck.sub as opp_deck_sub
-		FROM matches m
-		JOIN seasons s ON m.season_id = s.id
-		JOIN decks my_deck ON m.my_deck_id = my_deck.id
-		JOIN decks opp_deck ON m.opp_deck_id = opp_deck.id
-		WHERE 1=1
-	`
-
-	// 動態加入篩選條件（SQLite 使用 ? 佔位符）
-	args := []interface{}{}
-
-	if seasonCode != "" {
-		query += " AND s.code = ?"
-		args = append(args, seasonCode)
-	}
-
-	if mode != "" {
-		query += " AND m.mode = ?"
-		args = append(args, mode)
-	}
-
-	if myDeckMain != "" {
-		query += " AND my_deck.main = ?"
-		args = append(args, myDeckMain)
-	}
-
-	if oppDeckMain != "" {
-		query += " AND opp_deck.main = ?"
-		args = append(args, oppDeckMain)
-	}
-
-	if result != "" {
-		query += " AND m.result = ?"
-		args = append(args, result)
-	}
-
-	if playOrder != "" {
-		query += " AND m.play_order = ?"
-		args = append(args, playOrder)
-	}
-
-	if dateFrom != "" {
-		query += " AND m.date >= ?"
-		args = append(args, dateFrom)
-	}
-
-	if dateTo != "" {
-		query += " AND m.date <= ?"
-		args = append(args, dateTo)
-	}
-
-	// 按日期排序（最新在前）
-	query += " ORDER BY m.date DESC, m.created_at DESC"
-
-	// 執行查詢
-	rows, err := h.db.Query(query, args...)
+	matches, err := h.store.Matches().List(parseMatchFilter(c))
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "查詢失敗", "details": err.Error()})
 	}
-	defer rows.Close()
-
-	// 解析結果
-	matches := []models.MatchWithDetails{}
-	for rows.Next() {
-		var m models.MatchWithDetails
-		var myDeckSub, oppDeckSub, note sql.NullString
-
-		err := rows.Scan(
-			&m.ID,
-			&m.Date,
-			&m.Mode,
-			&m.Rank,
-			&m.PlayOrder,
-			&m.Result,
-			&note,
-			&m.CreatedAt,
-			&m.UpdatedAt,
-			&m.SeasonCode,
-			&m.MyDeck.ID,
-			&m.MyDeck.Main,
-			&myDeckSub,
-			&m.OppDeck.ID,
-			&m.OppDeck.Main,
-			&oppDeckSub,
-		)
-		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "解析資料失敗", "details": err.Error()})
-		}
-
-		// 處理 nullable 欄位
-		if myDeckSub.Valid {
-			m.MyDeck.Sub = &myDeckSub.String
-		}
-		if oppDeckSub.Valid {
-			m.OppDeck.Sub = &oppDeckSub.String
-		}
-		if note.Valid {
-			m.Note = &note.String
-		}
-
-		matches = append(matches, m)
+	lang := requestLang(c)
+	for i := range matches {
+		labelMatch(&matches[i], lang)
 	}
 
 	return c.JSON(fiber.Map{
@@ -160,6 +40,63 @@ func (h *MatchesHandler) GetMatches(c *fiber.Ctx) error {
 	})
 }
 
+// parseMatchFilter 從查詢參數取出篩選條件（GET /matches 與 /stats/* 共用）
+// 使用者固定為 auth middleware 驗證後的使用者，不接受查詢參數指定。
+// mode / result / playOrder 也接受顯示名稱（e.g. playOrder=先攻），會轉成代碼再查詢。
+func parseMatchFilter(c *fiber.Ctx) models.MatchFilter {
+	f := models.MatchFilter{
+		UserID:      auth.UserID(c),
+		GameKey:     gameKeyOrDefault(c.Query("gameKey")),
+		AccountID:   c.Query("accountId"),
+		SeasonCode:  c.Query("seasonCode"),
+		Mode:        c.Query("mode"),
+		MyDeckMain:  c.Query("myDeckMain"),
+		OppDeckMain: c.Query("oppDeckMain"),
+		Result:      c.Query("result"),
+		PlayOrder:   c.Query("playOrder"),
+		DateFrom:    c.Query("dateFrom"),
+		DateTo:      c.Query("dateTo"),
+	}
+	gameRules := rules.For(f.GameKey)
+	f.Mode = filterCode(rules.Mode, gameRules, f.Mode)
+	f.Result = filterCode(rules.Result, gameRules, f.Result)
+	f.PlayOrder = filterCode(rules.PlayOrder, gameRules, f.PlayOrder)
+	return f
+}
+
+// filterCode 將篩選值轉成代碼；空值或無效的值照原樣保留（無效的值查不到任何對局）
+func filterCode(canonical func(rules.GameRules, string) (string, error), r rules.GameRules, value string) string {
+	if value == "" {
+		return ""
+	}
+	if code, err := canonical(r, value); err == nil {
+		return code
+	}
+	return value
+}
+
+// requestLang 顯示名稱的語系：?lang= 優先，其次為 Accept-Language
+func requestLang(c *fiber.Ctx) string {
+	if lang := c.Query("lang"); lang != "" {
+		return rules.ParseLang(lang)
+	}
+	return rules.ParseLang(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// labelMatch 依對局所屬遊戲的規則填入代碼欄位的顯示名稱
+func labelMatch(m *models.MatchWithDetails, lang string) {
+	r := rules.For(m.GameKey)
+	m.Labels = models.MatchLabels{
+		Mode:      rules.Label(r, rules.FieldMode, m.Mode, lang),
+		PlayOrder: rules.Label(r, rules.FieldPlayOrder, m.PlayOrder, lang),
+		Result:    rules.Label(r, rules.FieldResult, m.Result, lang),
+	}
+	if m.Rank != nil {
+		rank := rules.Label(r, rules.FieldRank, *m.Rank, lang)
+		m.Labels.Rank = &rank
+	}
+}
+
 // CreateMatch 新增對局 (POST /matches)
 func (h *MatchesHandler) CreateMatch(c *fiber.Ctx) error {
 	var req models.CreateMatchRequest
@@ -167,291 +104,489 @@ func (h *MatchesHandler) CreateMatch(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
 	}
 
-	// 驗證必要欄位
-	if req.GameKey == "" || req.SeasonCode == "" || req.Date == "" {
+	// 驗證必要欄位（seasonCode 可省略，由日期推算；有 playedAt 時 date 可省略）
+	if req.Date == "" && req.PlayedAt == nil {
 		return c.Status(400).JSON(fiber.Map{"error": "缺少必要欄位"})
 	}
+	req.GameKey = gameKeyOrDefault(req.GameKey)
 
-	if req.Mode == "" {
-		req.Mode = "Ranked"
-	}
-	if req.Mode != "Ranked" && req.Rank == "" {
-		req.Rank = "—"
+	// 目前登入的使用者（未登入時為 MVP 單人模式的預設使用者）
+	userID := auth.UserID(c)
+	if userID == "" {
+		return c.Status(401).JSON(fiber.Map{"error": "需要登入"})
 	}
 
-	// 取得 game_id
-	var gameID string
-	err := h.db.QueryRow("SELECT id FROM games WHERE key = ?", req.GameKey).Scan(&gameID)
+	// date 為使用者當地的日期：有 playedAt 時依使用者時區換算
+	loc, err := userLocation(h.store.Users(), userID)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "找不到遊戲", "gameKey": req.GameKey})
+		return c.Status(500).JSON(fiber.Map{"error": "查詢使用者失敗", "details": err.Error()})
+	}
+	if req.Date, err = localMatchDate(loc, req.Date, req.PlayedAt); err != nil {
+		return matchWriteError(c, err, "新增對局失敗")
 	}
 
-	// 取得 season_id
-	seasonID, err := h.getOrCreateSeasonID(gameID, req.SeasonCode)
+	// 取得 game_id
+	game, err := h.store.Games().GetByKey(req.GameKey)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "處理賽季失敗", "details": err.Error()})
+		return gameLookupError(c, req.GameKey, err)
 	}
 
-	// 取得或建立我的牌組
-	myDeckID, err := h.findOrCreateDeck(gameID, req.MyDeck.Main, req.MyDeck.Sub)
+	// 模式 / 階級 / 先後攻 / 結果依遊戲規則驗證並轉成代碼；不記錄階級的模式不存階級，積分只限記錄積分的模式
+	gameRules := rules.For(game.Key)
+	if req.Mode == "" {
+		req.Mode = rules.DefaultMode(gameRules)
+	}
+	if req.Mode, err = rules.Mode(gameRules, req.Mode); err != nil {
+		return rulesError(c, err)
+	}
+	if req.PlayOrder, err = rules.PlayOrder(gameRules, req.PlayOrder); err != nil {
+		return rulesError(c, err)
+	}
+	if req.Result, err = rules.Result(gameRules, req.Result); err != nil {
+		return rulesError(c, err)
+	}
+	rank, err := rules.Rank(gameRules, req.Mode, string(req.Rank))
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "處理我的牌組失敗", "details": err.Error()})
+		return rulesError(c, err)
+	}
+	if req.Rating, err = rules.Rating(gameRules, req.Mode, req.Rating); err != nil {
+		return rulesError(c, err)
 	}
 
-	// 取得或建立對手牌組
-	oppDeckID, err := h.findOrCreateDeck(gameID, req.OppDeck.Main, req.OppDeck.Sub)
+	// 賽季依遊戲的賽季曆由日期（有 playedAt 時依遊戲的賽季切換時間）推算；有指定 seasonCode 時必須與日期相符
+	seasonDate, err := matchSeasonDate(game, req.Date, req.PlayedAt)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "處理對手牌組失敗", "details": err.Error()})
+		return matchWriteError(c, err, "處理賽季失敗")
+	}
+	if req.SeasonCode, err = repo.ResolveSeason(h.store.Seasons(), game, req.SeasonCode, seasonDate); err != nil {
+		return matchWriteError(c, err, "處理賽季失敗")
 	}
 
-	// 生成新的 match ID
-	matchID := uuid.New().String()
+	// 遊戲帳號（可選）必須是自己在這款遊戲的帳號
+	if req.AccountID != nil && *req.AccountID == "" {
+		req.AccountID = nil
+	}
+	if req.AccountID != nil {
+		account, err := h.store.Accounts().Get(userID, *req.AccountID)
+		if errors.Is(err, repo.ErrNotFound) || (err == nil && account.GameID != game.ID) {
+			return c.Status(400).JSON(fiber.Map{"error": "找不到遊戲帳號", "accountId": *req.AccountID})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "查詢遊戲帳號失敗", "details": err.Error()})
+		}
+	}
 
-	// 取得預設 user_id（MVP 單人模式）
-	var userID string
-	err = h.db.QueryRow("SELECT id FROM users LIMIT 1").Scan(&userID)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "找不到使用者"})
-	}
-
-	// 插入對局記錄
-	_, err = h.db.Exec(`
-		INSERT INTO matches (
-			id, user_id, game_id, season_id, date, mode, rank,
-			my_deck_id, opp_deck_id, play_order, result, note,
-			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`,
-		matchID, userID, gameID, seasonID, req.Date, req.Mode, req.Rank,
-		myDeckID, oppDeckID, req.PlayOrder, req.Result, req.Note,
-		time.Now(), time.Now(),
-	)
+	// 賽季、牌組、模板與對局在同一個 transaction 內建立；
+	// 同時有其他請求建立同一個牌組 / 賽季時會撞到 UNIQUE 限制，整批重試即可讀到對方建立的資料。
+	var created models.MatchWithDetails
+	err = retryOnConflict(func() (err error) {
+		created, err = h.createMatch(game, userID, rank, req)
+		return err
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "新增對局失敗", "details": err.Error()})
+		return matchWriteError(c, err, "新增對局失敗")
 	}
 
-	return c.Status(201).JSON(fiber.Map{
-		"id":      matchID,
-		"message": "對局新增成功",
-	})
+	labelMatch(&created, requestLang(c))
+	return c.Status(201).JSON(created)
 }
 
-// UpdateMatch 更新對局 (PATCH /matches/:id)
-func (h *MatchesHandler) UpdateMatch(c *fiber.Ctx) error {
-	matchID := c.Params("id")
-	if matchID == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "缺少對局 ID"})
-	}
+// conflictAttempts 寫入遇到 UNIQUE 衝突時最多嘗試的次數
+const conflictAttempts = 3
 
-	var req models.UpdateMatchRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
-	}
+// errNoMatchUpdates PATCH 沒有任何要更新的欄位
+var errNoMatchUpdates = errors.New("沒有要更新的欄位")
 
-	// 檢查對局是否存在
-	var exists bool
-	err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM matches WHERE id = ?)", matchID).Scan(&exists)
-	if err != nil || !exists {
-		return c.Status(404).JSON(fiber.Map{"error": "找不到對局"})
-	}
+// matchDateMismatchError 同時指定 date 與 playedAt，但 playedAt 在使用者時區的日期不是 date
+type matchDateMismatchError struct {
+	date     string
+	expected string
+	timezone string
+}
 
-	// 動態建立更新語句
-	updates := []string{}
-	args := []interface{}{}
+func (e *matchDateMismatchError) Error() string {
+	return "date " + e.date + " 與 playedAt 在 " + e.timezone + " 的日期 " + e.expected + " 不符"
+}
 
-	if req.Date != nil {
-		updates = append(updates, "date = ?")
-		args = append(args, *req.Date)
-	}
-	if req.Mode != nil {
-		updates = append(updates, "mode = ?")
-		args = append(args, *req.Mode)
-		// If switching away from Ranked and no explicit rank provided, set rank to '—' to satisfy NOT NULL.
-		if *req.Mode != "Ranked" && req.Rank == nil {
-			updates = append(updates, "rank = ?")
-			args = append(args, "—")
+// matchStepError 標示對局寫入在哪個步驟失敗（step 即回傳給前端的錯誤訊息）
+type matchStepError struct {
+	step string
+	err  error
+}
+
+func (e *matchStepError) Error() string { return e.step + ": " + e.err.Error() }
+func (e *matchStepError) Unwrap() error { return e.err }
+
+// retryOnConflict 執行 fn；遇到 repo.ErrConflict（同時有其他請求建立了同一個牌組 / 賽季）時整批重試，
+// 重試時就能讀到對方建立的資料
+func retryOnConflict(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < conflictAttempts; attempt++ {
+		if err = fn(); !errors.Is(err, repo.ErrConflict) {
+			return err
 		}
 	}
-	if req.Rank != nil {
-		updates = append(updates, "rank = ?")
-		args = append(args, *req.Rank)
-	}
-	if req.PlayOrder != nil {
-		updates = append(updates, "play_order = ?")
-		args = append(args, *req.PlayOrder)
-	}
-	if req.Result != nil {
-		updates = append(updates, "result = ?")
-		args = append(args, *req.Result)
-	}
-	if req.Note != nil {
-		updates = append(updates, "note = ?")
-		args = append(args, *req.Note)
-	}
+	return err
+}
 
-	// TODO: 處理 MyDeck 和 OppDeck 的更新（需要 findOrCreateDeck）
+// rulesError 對局欄位不符合遊戲規則時回傳 400（含欄位名稱與可用值）
+func rulesError(c *fiber.Ctx, err error) error {
+	var ruleErr *rules.ValidationError
+	if !errors.As(err, &ruleErr) {
+		return c.Status(500).JSON(fiber.Map{"error": "驗證對局失敗", "details": err.Error()})
+	}
+	return c.Status(400).JSON(fiber.Map{
+		"error":   ruleErr.Error(),
+		"field":   ruleErr.Field,
+		"allowed": nonNil(ruleErr.Allowed),
+	})
+}
 
-	if len(updates) == 0 {
-		return c.Status(400).JSON(fiber.Map{"error": "沒有要更新的欄位"})
+// matchWriteError 將對局寫入的錯誤轉成 HTTP 回應；fallback 為未分類錯誤的訊息
+func matchWriteError(c *fiber.Ctx, err error, fallback string) error {
+	var stepErr *matchStepError
+	var ruleErr *rules.ValidationError
+	var mismatch *season.MismatchError
+	var dateMismatch *matchDateMismatchError
+	switch {
+	case errors.As(err, &ruleErr):
+		return rulesError(c, err)
+	case errors.As(err, &mismatch):
+		return c.Status(400).JSON(fiber.Map{
+			"error":      mismatch.Error(),
+			"seasonCode": mismatch.Code,
+			"expected":   mismatch.Expected,
+		})
+	case errors.As(err, &dateMismatch):
+		return c.Status(400).JSON(fiber.Map{
+			"error":    dateMismatch.Error(),
+			"date":     dateMismatch.date,
+			"expected": dateMismatch.expected,
+			"timezone": dateMismatch.timezone,
+		})
+	case errors.Is(err, errInvalidMatchDate), errors.Is(err, repo.ErrNoSeasonForDate):
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repo.ErrNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "找不到對局"})
+	case errors.Is(err, repo.ErrConflict):
+		return c.Status(409).JSON(fiber.Map{"error": "資料衝突，請重試", "details": err.Error()})
+	case errors.As(err, &stepErr):
+		return c.Status(500).JSON(fiber.Map{"error": stepErr.step, "details": stepErr.err.Error()})
+	default:
+		return c.Status(500).JSON(fiber.Map{"error": fallback, "details": err.Error()})
 	}
+}
 
-	// 加入 updated_at
-	updates = append(updates, "updated_at = ?")
-	args = append(args, time.Now())
+// createMatch 在單一 transaction 內建立對局（含自動建立的賽季 / 牌組 / 模板），回傳完整的對局資料；
+// rank 為依遊戲規則解析後的階級（不記錄階級時為 nil）
+func (h *MatchesHandler) createMatch(game models.Game, userID string, rank *models.Rank, req models.CreateMatchRequest) (models.MatchWithDetails, error) {
+	gameID := game.ID
+	var created models.MatchWithDetails
+	err := h.store.InTx(func(tx repo.Store) error {
+		// 取得 season_id（新賽季的起訖日依賽季曆推算）
+		seasonID, err := matchSeasonID(tx, game, req.SeasonCode)
+		if err != nil {
+			return &matchStepError{"處理賽季失敗", err}
+		}
 
-	// 加入 WHERE 條件
-	args = append(args, matchID)
+		// 取得或建立我的牌組
+		myDeckID, err := tx.Decks().FindOrCreate(gameID, req.MyDeck.Main, req.MyDeck.Sub)
+		if err != nil {
+			return &matchStepError{"處理我的牌組失敗", err}
+		}
 
-	// 執行更新
-	query := fmt.Sprintf("UPDATE matches SET %s WHERE id = ?", joinStrings(updates, ", "))
-	_, err = h.db.Exec(query, args...)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "更新失敗", "details": err.Error()})
-	}
+		// 取得或建立對手牌組
+		oppDeckID, err := tx.Decks().FindOrCreate(gameID, req.OppDeck.Main, req.OppDeck.Sub)
+		if err != nil {
+			return &matchStepError{"處理對手牌組失敗", err}
+		}
 
-	return c.JSON(fiber.Map{
-		"message": "對局更新成功",
-		"id":      matchID,
+		// 插入對局記錄
+		matchID := uuid.New().String()
+		err = tx.Matches().Create(models.Match{
+			ID:        matchID,
+			UserID:    userID,
+			GameID:    gameID,
+			SeasonID:  seasonID,
+			AccountID: req.AccountID,
+			Date:      req.Date,
+			PlayedAt:  req.PlayedAt,
+			Mode:      req.Mode,
+			Rank:      rank,
+			Rating:    req.Rating,
+			MyDeckID:  myDeckID,
+			OppDeckID: oppDeckID,
+			PlayOrder: req.PlayOrder,
+			Result:    req.Result,
+			Note:      req.Note,
+		})
+		if err != nil {
+			return &matchStepError{"新增對局失敗", err}
+		}
+
+		created, err = tx.Matches().Get(userID, matchID)
+		return err
 	})
+	return created, err
 }
 
-// DeleteMatch 刪除對局 (DELETE /matches/:id)
-func (h *MatchesHandler) DeleteMatch(c *fiber.Ctx) error {
+// UpdateMatch 更新對局 (PATCH /matches/:id)
+//
+// myDeck / oppDeck 會經由 FindOrCreate 換成對應的牌組；修改 date / playedAt 時賽季會依新日期重新推算，
+// 指定 seasonCode 時必須與日期相符（不存在時自動建立）；
+// 換掉的舊牌組若已沒有任何對局使用，會在同一個 transaction 內刪除。
+func (h *MatchesHandler) UpdateMatch(c *fiber.Ctx) error {
 	matchID := c.Params("id")
 	if matchID == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "缺少對局 ID"})
 	}
 
-	// 執行刪除
-	result, err := h.db.Exec("DELETE FROM matches WHERE id = ?", matchID)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "刪除失敗", "details": err.Error()})
+	var req models.UpdateMatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
 	}
-
-	// 檢查是否有刪除任何記錄
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return c.Status(404).JSON(fiber.Map{"error": "找不到對局"})
+	if req.SeasonCode != nil && *req.SeasonCode == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "seasonCode 不可為空"})
+	}
+	if (req.MyDeck != nil && req.MyDeck.Main == "") || (req.OppDeck != nil && req.OppDeck.Main == "") {
+		return c.Status(400).JSON(fiber.Map{"error": "牌組大軸不可為空"})
+	}
+
+	// 只能修改自己的對局；其他使用者的對局一律回傳 404
+	userID := auth.UserID(c)
+
+	err := retryOnConflict(func() error {
+		return h.store.InTx(func(tx repo.Store) error {
+			// 檢查對局是否存在
+			current, err := tx.Matches().Get(userID, matchID)
+			if err != nil {
+				return err
+			}
+
+			patch := repo.MatchPatch{Note: req.Note}
+			if err := validateMatchPatch(current, req, &patch); err != nil {
+				return err
+			}
+
+			if req.Date != nil || req.PlayedAt != nil {
+				loc, err := userLocation(tx.Users(), userID)
+				if err != nil {
+					return &matchStepError{"查詢使用者失敗", err}
+				}
+				if err := patchMatchTime(current, req, loc, &patch); err != nil {
+					return err
+				}
+			}
+			if req.SeasonCode != nil || patch.Date != nil {
+				seasonID, err := updatedSeasonID(tx, current, req.SeasonCode, patch)
+				if err != nil {
+					return err
+				}
+				patch.SeasonID = &seasonID
+			}
+			if req.MyDeck != nil {
+				myDeckID, err := tx.Decks().FindOrCreate(current.GameID, req.MyDeck.Main, req.MyDeck.Sub)
+				if err != nil {
+					return &matchStepError{"處理我的牌組失敗", err}
+				}
+				patch.MyDeckID = &myDeckID
+			}
+			if req.OppDeck != nil {
+				oppDeckID, err := tx.Decks().FindOrCreate(current.GameID, req.OppDeck.Main, req.OppDeck.Sub)
+				if err != nil {
+					return &matchStepError{"處理對手牌組失敗", err}
+				}
+				patch.OppDeckID = &oppDeckID
+			}
+
+			if patch.Empty() {
+				return errNoMatchUpdates
+			}
+
+			// 執行更新
+			if err := tx.Matches().Update(userID, matchID, patch); err != nil {
+				return &matchStepError{"更新失敗", err}
+			}
+
+			// 清掉不再被任何對局使用的舊牌組
+			if _, err := tx.Decks().DeleteUnused(current.MyDeck.ID, current.OppDeck.ID); err != nil {
+				return &matchStepError{"清理牌組失敗", err}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		if errors.Is(err, errNoMatchUpdates) {
+			return c.Status(400).JSON(fiber.Map{"error": "沒有要更新的欄位"})
+		}
+		return matchWriteError(c, err, "更新失敗")
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "對局刪除成功",
+		"message": "對局更新成功",
 		"id":      matchID,
 	})
 }
 
-// findOrCreateDeck 尋找或建立牌組
-func (h *MatchesHandler) findOrCreateDeck(gameID, main string, sub *string) (string, error) {
-	var deckID string
-	var subValue sql.NullString
+// validateMatchPatch 依對局所屬遊戲的規則驗證要更新的模式 / 階級 / 積分 / 先後攻 / 結果，並以代碼寫入 patch。
+// 模式或階級有變動時，以更新後的模式重新決定 rank（e.g. 改成不記錄階級的模式時清除階級）；積分亦同。
+func validateMatchPatch(current models.MatchWithDetails, req models.UpdateMatchRequest, patch *repo.MatchPatch) error {
+	gameRules := rules.For(current.GameKey)
 
-	if sub != nil {
-		subValue.String = *sub
-		subValue.Valid = true
+	mode := current.Mode
+	if req.Mode != nil {
+		code, err := rules.Mode(gameRules, *req.Mode)
+		if err != nil {
+			return err
+		}
+		mode, patch.Mode = code, &code
 	}
-
-	// 先嘗試尋找
-	var query string
-	if sub == nil {
-		query = "SELECT id FROM decks WHERE game_id = ? AND main = ? AND sub IS NULL"
-		err := h.db.QueryRow(query, gameID, main).Scan(&deckID)
-		if err == nil {
-			return deckID, nil // 找到了
+	if req.PlayOrder != nil {
+		code, err := rules.PlayOrder(gameRules, *req.PlayOrder)
+		if err != nil {
+			return err
 		}
-	} else {
-		query = "SELECT id FROM decks WHERE game_id = ? AND main = ? AND sub = ?"
-		err := h.db.QueryRow(query, gameID, main, *sub).Scan(&deckID)
-		if err == nil {
-			return deckID, nil // 找到了
+		patch.PlayOrder = &code
+	}
+	if req.Result != nil {
+		code, err := rules.Result(gameRules, *req.Result)
+		if err != nil {
+			return err
 		}
+		patch.Result = &code
 	}
-
-	// 沒找到，建立新的
-	deckID = uuid.New().String()
-	_, err := h.db.Exec(
-		"INSERT INTO decks (id, game_id, main, sub) VALUES (?, ?, ?, ?)",
-		deckID, gameID, main, subValue,
-	)
-	if err != nil {
-		return "", err
+	if req.Mode != nil || req.Rank != nil {
+		value := ""
+		if current.Rank != nil {
+			value = *current.Rank
+		}
+		if req.Rank != nil {
+			value = string(*req.Rank)
+		}
+		rank, err := rules.Rank(gameRules, mode, value)
+		if err != nil {
+			return err
+		}
+		if rank == nil {
+			rank = &models.Rank{} // 清除階級
+		}
+		patch.Rank = rank
+	}
+	if req.Mode != nil || req.Rating != nil {
+		value := current.Rating
+		if req.Rating != nil {
+			value = req.Rating
+		} else if !rules.RatingApplies(gameRules, mode) {
+			value = nil // 改成不記錄積分的模式
+		}
+		rating, err := rules.Rating(gameRules, mode, value)
+		if err != nil {
+			return err
+		}
+		if rating == nil {
+			rating = &models.Rating{} // 清除積分
+		}
+		patch.Rating = rating
 	}
+	return nil
+}
 
-	// 同時確保 deck_templates 中有這個牌組（用於顏色顯示）
-	h.ensureDeckTemplate(gameID, main)
-	if sub != nil && *sub != "" && *sub != "無" {
-		h.ensureDeckTemplate(gameID, *sub)
+// patchMatchTime 決定更新後的 date / played_at：
+//   - 指定 playedAt 時 date 為其在使用者時區的日期（同時指定 date 時必須相符）
+//   - 只改 date 且對局有 played_at 時，played_at 移到新日期的同一個當地時間
+func patchMatchTime(current models.MatchWithDetails, req models.UpdateMatchRequest, loc *time.Location, patch *repo.MatchPatch) error {
+	if req.PlayedAt != nil {
+		requested := ""
+		if req.Date != nil {
+			requested = *req.Date
+		}
+		date, err := localMatchDate(loc, requested, req.PlayedAt)
+		if err != nil {
+			return err
+		}
+		patch.Date, patch.PlayedAt = &date, req.PlayedAt
+		return nil
 	}
 
-	return deckID, nil
+	date, ok := season.ParseDate(*req.Date)
+	if !ok {
+		return errInvalidMatchDate
+	}
+	normalized := date.Format(season.DateLayout)
+	patch.Date = &normalized
+	if current.PlayedAt != nil {
+		local := current.PlayedAt.In(loc)
+		moved := time.Date(date.Year(), date.Month(), date.Day(),
+			local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), loc)
+		patch.PlayedAt = &moved
+	}
+	return nil
 }
 
-// ensureDeckTemplate 確保牌組模板存在，不存在則建立（預設主題為「無」）
-func (h *MatchesHandler) ensureDeckTemplate(gameID, deckName string) {
-	// 檢查是否已存在
-	var exists bool
-	err := h.db.QueryRow(
-		"SELECT EXISTS(SELECT 1 FROM deck_templates WHERE game_id = ? AND main = ? AND deck_type = 'main')",
-		gameID,
-		deckName,
-	).Scan(&exists)
-	if err != nil || exists {
-		return // 已存在或查詢失敗，不需要建立
-	}
-
-	// 不存在，建立新的模板（預設主題為「無」= 灰色）
-	templateID := "tpl-auto-" + uuid.New().String()[:8]
-	_, _ = h.db.Exec(`
-		INSERT INTO deck_templates (id, game_id, main, theme, deck_type, created_at)
-		VALUES (?, ?, ?, '無', 'main', CURRENT_TIMESTAMP)
-	`, templateID, gameID, deckName)
-}
+// updatedSeasonID 更新日期或賽季後對局應屬的 season_id。
+// 只改日期時，有賽季曆的遊戲依新日期重新推算賽季，其他遊戲沿用原本的賽季；兩者都必須與日期相符。
+func updatedSeasonID(tx repo.Store, current models.MatchWithDetails, seasonCode *string, patch repo.MatchPatch) (string, error) {
+	game, err := tx.Games().GetByKey(current.GameKey)
+	if err != nil {
+		return "", &matchStepError{"查詢遊戲失敗", err}
+	}
 
-func (h *MatchesHandler) getOrCreateSeasonID(gameID, seasonCode string) (string, error) {
-	var seasonID string
-	err := h.db.QueryRow("SELECT id FROM seasons WHERE code = ? AND game_id = ?", seasonCode, gameID).Scan(&seasonID)
-	if err == nil {
-		return seasonID, nil
+	dateStr, playedAt := current.Date, current.PlayedAt
+	if patch.Date != nil {
+		dateStr = *patch.Date
 	}
-	if !errors.Is(err, sql.ErrNoRows) {
+	if patch.PlayedAt != nil {
+		playedAt = patch.PlayedAt
+	}
+	date, err := matchSeasonDate(game, dateStr, playedAt)
+	if err != nil {
 		return "", err
 	}
 
-	// Not found: auto-create so users can start recording immediately.
-	seasonID = uuid.New().String()
-
-	// If seasonCode looks like YYYY-MM, fill start/end dates; otherwise leave them NULL.
-	var startDate any = nil
-	var endDate any = nil
-	if t, parseErr := time.Parse("2006-01", seasonCode); parseErr == nil {
-		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
-		end := start.AddDate(0, 1, 0).AddDate(0, 0, -1)
-		startDate = start.Format("2006-01-02")
-		endDate = end.Format("2006-01-02")
+	code := ""
+	if seasonCode != nil {
+		code = *seasonCode
+	} else if _, hasCalendar := season.For(game.Key); !hasCalendar {
+		code = current.SeasonCode
 	}
-
-	_, err = h.db.Exec(
-		"INSERT INTO seasons (id, game_id, code, start_date, end_date) VALUES (?, ?, ?, ?, ?)",
-		seasonID, gameID, seasonCode, startDate, endDate,
-	)
+	code, err = repo.ResolveSeason(tx.Seasons(), game, code, date)
 	if err != nil {
-		// If another request created it concurrently, just re-read.
-		if readErr := h.db.QueryRow("SELECT id FROM seasons WHERE code = ? AND game_id = ?", seasonCode, gameID).Scan(&seasonID); readErr == nil {
-			return seasonID, nil
-		}
 		return "", err
 	}
 
+	seasonID, err := matchSeasonID(tx, game, code)
+	if err != nil {
+		return "", &matchStepError{"處理賽季失敗", err}
+	}
 	return seasonID, nil
 }
 
-// joinStrings 連接字串陣列（輔助函數）
-func joinStrings(strs []string, sep string) string {
-	if len(strs) == 0 {
-		return ""
+// DeleteMatch 刪除對局 (DELETE /matches/:id)
+func (h *MatchesHandler) DeleteMatch(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "缺少對局 ID"})
 	}
-	result := strs[0]
-	for i := 1; i < len(strs); i++ {
-		result += sep + strs[i]
+
+	// 只能刪除自己的對局，並清掉不再被任何對局使用的牌組
+	userID := auth.UserID(c)
+	err := h.store.InTx(func(tx repo.Store) error {
+		current, err := tx.Matches().Get(userID, matchID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Matches().Delete(userID, matchID); err != nil {
+			return err
+		}
+		_, err = tx.Decks().DeleteUnused(current.MyDeck.ID, current.OppDeck.ID)
+		return err
+	})
+	if err != nil {
+		return matchWriteError(c, err, "刪除失敗")
 	}
-	return result
+
+	return c.JSON(fiber.Map{
+		"message": "對局刪除成功",
+		"id":      matchID,
+	})
 }
@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+// matchRequest 一場 2026-09-03（S57）的 Ranked 對局；extra 覆蓋或新增欄位，值為 nil 時移除該欄位
+func matchRequest(extra map[string]interface{}) map[string]interface{} {
+	req := map[string]interface{}{
+		"gameKey":   "master_duel",
+		"date":      "2026-09-03",
+		"mode":      "Ranked",
+		"rank":      "gold-3",
+		"myDeck":    map[string]interface{}{"main": "Tenpai", "sub": nil},
+		"oppDeck":   map[string]interface{}{"main": "Yubel", "sub": nil},
+		"playOrder": "first",
+		"result":    "W",
+	}
+	for k, v := range extra {
+		if v == nil {
+			delete(req, k)
+			continue
+		}
+		req[k] = v
+	}
+	return req
+}
+
+// getMatch 以 token 的使用者從 GET /matches 取出 id 這場對局
+func (s *testServer) getMatch(token, id string) map[string]interface{} {
+	s.t.Helper()
+	status, body := s.do(http.MethodGet, "/matches", token, nil)
+	if status != http.StatusOK {
+		s.t.Fatalf("GET /matches = %d %v", status, body)
+	}
+	for _, m := range body["matches"].([]interface{}) {
+		if match := m.(map[string]interface{}); match["id"] == id {
+			return match
+		}
+	}
+	s.t.Fatalf("match %s not found in %v", id, body)
+	return nil
+}
+
+func TestCreateMatchValidatesAgainstGameRules(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		alice := s.register("alice@example.com")
+
+		tests := []struct {
+			name  string
+			extra map[string]interface{}
+			field string // 規則錯誤時回應的 field；其他 400 為空字串
+		}{
+			{"unknown result", map[string]interface{}{"result": "X"}, "result"},
+			{"unknown mode", map[string]interface{}{"mode": "Arena"}, "mode"},
+			{"rank outside the ladder", map[string]interface{}{"rank": "gold-9"}, "rank"},
+			{"rating in ranked", map[string]interface{}{"rating": map[string]int{"before": 1000, "after": 1010}}, "rating"},
+			{"bad date", map[string]interface{}{"date": "2026-13-40"}, ""},
+			{"missing date", map[string]interface{}{"date": nil}, ""},
+		}
+		for _, tt := range tests {
+			status, body := s.do(http.MethodPost, "/matches", alice, matchRequest(tt.extra))
+			if status != http.StatusBadRequest {
+				t.Errorf("%s: POST /matches = %d %v, want 400", tt.name, status, body)
+				continue
+			}
+			if tt.field != "" && body["field"] != tt.field {
+				t.Errorf("%s: field = %v, want %s", tt.name, body["field"], tt.field)
+			}
+		}
+
+		if status, body := s.do(http.MethodGet, "/matches", alice, nil); listLen(t, body, "matches") != 0 {
+			t.Errorf("rejected matches were stored: %d %v", status, body)
+		}
+	})
+}
+
+func TestCreateMatchResolvesSeasonFromDate(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		alice := s.register("alice@example.com")
+
+		tests := []struct {
+			seasonCode interface{}
+			date       string
+			want       string
+		}{
+			{nil, "2026-09-03", "S57"},
+			{"s57", "2026-09-30", "S57"},
+			{"S58", "2026-10-01", "S58"},
+		}
+		for _, tt := range tests {
+			status, body := s.do(http.MethodPost, "/matches", alice, matchRequest(map[string]interface{}{"seasonCode": tt.seasonCode, "date": tt.date}))
+			if status != http.StatusCreated || body["seasonCode"] != tt.want {
+				t.Errorf("POST /matches seasonCode=%v date=%s = %d %v, want 201 in %s", tt.seasonCode, tt.date, status, body, tt.want)
+			}
+		}
+
+		status, body := s.do(http.MethodPost, "/matches", alice, matchRequest(map[string]interface{}{"seasonCode": "S57", "date": "2026-10-01"}))
+		if status != http.StatusBadRequest || body["expected"] != "S58" {
+			t.Errorf("POST /matches with S57 on 2026-10-01 = %d %v, want 400 expecting S58", status, body)
+		}
+	})
+}
+
+func TestUpdateMatchReplacesDecksAndSeason(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		alice := s.register("alice@example.com")
+		id := s.createMatch(alice, nil)
+		path := "/matches/" + id
+
+		patch := map[string]interface{}{"myDeck": map[string]interface{}{"main": "Snake-Eye", "sub": "Fire King"}, "result": "L"}
+		if status, body := s.do(http.MethodPatch, path, alice, patch); status != http.StatusOK {
+			t.Fatalf("PATCH decks = %d %v", status, body)
+		}
+		match := s.getMatch(alice, id)
+		myDeck := match["myDeck"].(map[string]interface{})
+		if myDeck["main"] != "Snake-Eye" || myDeck["sub"] != "Fire King" || match["result"] != "L" {
+			t.Errorf("after PATCH myDeck = %v, result = %v", myDeck, match["result"])
+		}
+
+		// 改日期時賽季跟著重新推算；指定不相符的賽季則拒絕
+		if status, body := s.do(http.MethodPatch, path, alice, map[string]interface{}{"date": "2026-10-05"}); status != http.StatusOK {
+			t.Fatalf("PATCH date = %d %v", status, body)
+		}
+		if match := s.getMatch(alice, id); match["date"] != "2026-10-05" || match["seasonCode"] != "S58" {
+			t.Errorf("after PATCH date: date = %v, seasonCode = %v, want 2026-10-05 in S58", match["date"], match["seasonCode"])
+		}
+		if status, body := s.do(http.MethodPatch, path, alice, map[string]interface{}{"seasonCode": "S57"}); status != http.StatusBadRequest {
+			t.Errorf("PATCH seasonCode S57 for an October match = %d %v, want 400", status, body)
+		}
+
+		if status, body := s.do(http.MethodPatch, path, alice, map[string]interface{}{}); status != http.StatusBadRequest {
+			t.Errorf("empty PATCH = %d %v, want 400", status, body)
+		}
+		if status, body := s.do(http.MethodPatch, "/matches/missing", alice, map[string]interface{}{"result": "W"}); status != http.StatusNotFound {
+			t.Errorf("PATCH unknown match = %d %v, want 404", status, body)
+		}
+	})
+}
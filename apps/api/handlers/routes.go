@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/harvc/duellog/apps/api/auth"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// Deps Register 需要的相依物件
+type Deps struct {
+	Store  repo.Store
+	DB     *storage.DB // 統計端點直接以 SQL 彙總；nil 時不註冊 /stats（e.g. 使用 memrepo 的測試）
+	Tokens *auth.Tokens
+
+	// AuthRequired 為 true 時未登入的請求一律 401，否則視為預設使用者（MVP 單人模式）
+	AuthRequired bool
+	// AdminEmails 可以修改共用資料（遊戲、賽季、牌組模板）的使用者；預設使用者一律可以
+	AdminEmails []string
+}
+
+// Register 註冊所有 API 路由與其 middleware；main.go 與 handler 測試共用同一份路由表
+func Register(app *fiber.App, d Deps) {
+	store := d.Store
+
+	requireAuth := auth.New(auth.Config{
+		Tokens:   d.Tokens,
+		Users:    store.Users(),
+		APIKeys:  store.APIKeys(),
+		Required: d.AuthRequired,
+	})
+	for _, prefix := range []string{"/auth/me", "/api-keys", "/games", "/seasons", "/accounts", "/matches", "/stats", "/deck-templates"} {
+		app.Use(prefix, requireAuth)
+	}
+	// 遊戲、賽季與牌組模板是所有使用者共用的資料：只有預設使用者或 AdminEmails 中的使用者可以修改
+	requireAdmin := auth.RequireAdmin(store.Users(), d.AdminEmails)
+
+	authHandler := NewAuthHandler(store, d.Tokens)
+	app.Post("/auth/register", authHandler.Register)
+	app.Post("/auth/login", authHandler.Login)
+	app.Get("/auth/me", authHandler.Me)
+	app.Patch("/auth/me", authHandler.UpdateMe)
+
+	// API Keys（給腳本 / bot 使用：Authorization: Bearer dl_...）
+	apiKeysHandler := NewAPIKeysHandler(store.APIKeys())
+	app.Get("/api-keys", apiKeysHandler.GetAPIKeys)
+	app.Post("/api-keys", apiKeysHandler.CreateAPIKey)
+	app.Delete("/api-keys/:id", apiKeysHandler.RevokeAPIKey)
+
+	// Matches API
+	matchesHandler := NewMatchesHandler(store)
+	app.Get("/matches", matchesHandler.GetMatches)
+	app.Post("/matches", matchesHandler.CreateMatch)
+	app.Patch("/matches/:id", matchesHandler.UpdateMatch)
+	app.Delete("/matches/:id", matchesHandler.DeleteMatch)
+
+	// Games API
+	gamesHandler := NewGamesHandler(store.Games())
+	app.Get("/games", gamesHandler.GetGames)
+	app.Post("/games", requireAdmin, gamesHandler.CreateGame)
+	app.Patch("/games/:key", requireAdmin, gamesHandler.UpdateGame)
+	app.Get("/games/:key/rules", gamesHandler.GetGameRules)
+
+	// Seasons API（新增對局時也會依賽季曆自動建立）
+	seasonsHandler := NewSeasonsHandler(store)
+	app.Get("/seasons", seasonsHandler.GetSeasons)
+	app.Post("/seasons", requireAdmin, seasonsHandler.CreateSeason)
+	app.Patch("/seasons/:code", requireAdmin, seasonsHandler.UpdateSeason)
+
+	// Accounts API（同一個使用者的多個遊戲帳號）
+	accountsHandler := NewAccountsHandler(store)
+	app.Get("/accounts", accountsHandler.GetAccounts)
+	app.Post("/accounts", accountsHandler.CreateAccount)
+	app.Delete("/accounts/:id", accountsHandler.DeleteAccount)
+
+	// Stats API
+	if d.DB != nil {
+		statsHandler := NewStatsHandler(d.DB)
+		app.Get("/stats/summary", statsHandler.GetSummary)
+		app.Get("/stats/daily", statsHandler.GetDaily)
+		app.Get("/stats/opponents", statsHandler.GetOpponents)
+		app.Get("/stats/my-decks", statsHandler.GetMyDecks)
+		app.Get("/stats/matchups", statsHandler.GetMatchups)
+		app.Get("/stats/rank-timeline", statsHandler.GetRankTimeline)
+		app.Get("/stats/rating", statsHandler.GetRatingHistory)
+		app.Get("/stats/ladder-sim", statsHandler.SimulateLadder)
+	}
+
+	// Deck Templates API
+	app.Get("/deck-templates", func(c *fiber.Ctx) error { return GetDeckTemplates(c, store) })
+	app.Post("/deck-templates", requireAdmin, func(c *fiber.Ctx) error { return CreateDeckTemplate(c, store) })
+	app.Patch("/deck-templates/:id", requireAdmin, func(c *fiber.Ctx) error { return UpdateDeckTemplate(c, store) })
+	app.Delete("/deck-templates/:id", requireAdmin, func(c *fiber.Ctx) error { return DeleteDeckTemplate(c, store) })
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/season"
+)
+
+// SeasonsHandler 處理賽季（各遊戲共用，不分使用者）
+type SeasonsHandler struct {
+	store repo.Store
+}
+
+// NewSeasonsHandler 建立新的 seasons handler
+func NewSeasonsHandler(store repo.Store) *SeasonsHandler {
+	return &SeasonsHandler{store: store}
+}
+
+// CreateSeasonRequest 新增賽季的請求結構
+type CreateSeasonRequest struct {
+	GameKey   string  `json:"gameKey"`   // 預設 master_duel
+	Code      string  `json:"code"`      // e.g. "S49"
+	StartDate *string `json:"startDate"` // 省略時依賽季曆推算
+	EndDate   *string `json:"endDate"`   // 省略時依賽季曆推算
+}
+
+// UpdateSeasonRequest 更新賽季起訖日的請求結構
+type UpdateSeasonRequest struct {
+	StartDate *string `json:"startDate"`
+	EndDate   *string `json:"endDate"`
+}
+
+// GetSeasons 列出賽季 (GET /seasons?gameKey=)
+//
+// 一併回傳目前賽季的代碼（current；資料庫中的起訖日優先，其次依賽季曆推算，即使資料庫中還沒有這一季），
+// 推算不出來時為 null。
+func (h *SeasonsHandler) GetSeasons(c *fiber.Ctx) error {
+	gameKey := gameKeyOrDefault(c.Query("gameKey"))
+	game, err := h.store.Games().GetByKey(gameKey)
+	if err != nil {
+		return gameLookupError(c, gameKey, err)
+	}
+
+	seasons, err := h.store.Seasons().List(game.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "查詢失敗", "details": err.Error()})
+	}
+
+	var current *string
+	if code, err := repo.ResolveSeason(h.store.Seasons(), game, "", time.Now()); err == nil {
+		current = &code
+	}
+	return c.JSON(fiber.Map{
+		"seasons": seasons,
+		"current": current,
+		"total":   len(seasons),
+	})
+}
+
+// CreateSeason 新增賽季 (POST /seasons)
+//
+// 有賽季曆的遊戲只接受賽季曆認得的代碼，並正規化（e.g. "s49" → "S49"），與對局記錄的代碼一致。
+func (h *SeasonsHandler) CreateSeason(c *fiber.Ctx) error {
+	var req CreateSeasonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	req.GameKey = gameKeyOrDefault(req.GameKey)
+	req.Code = strings.TrimSpace(req.Code)
+	if req.Code == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "code 為必填"})
+	}
+
+	game, err := h.store.Games().GetByKey(req.GameKey)
+	if err != nil {
+		return gameLookupError(c, req.GameKey, err)
+	}
+	code, ok := normalizeSeasonCode(game, req.Code)
+	if !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "無效的賽季代碼（e.g. S49）", "code": req.Code})
+	}
+
+	s := newSeason(game, code)
+	if req.StartDate != nil {
+		s.StartDate = req.StartDate
+	}
+	if req.EndDate != nil {
+		s.EndDate = req.EndDate
+	}
+	if msg := validateSeasonDates(s); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if err := h.store.Seasons().Create(s); err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			return c.Status(409).JSON(fiber.Map{"error": "賽季已存在", "code": s.Code})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "新增失敗", "details": err.Error()})
+	}
+	return c.Status(201).JSON(s)
+}
+
+// UpdateSeason 更新賽季起訖日 (PATCH /seasons/:code?gameKey=)
+//
+// 調整後的起訖日會用於之後新增 / 修改對局時判斷賽季（見 repo.ResolveSeason）。
+func (h *SeasonsHandler) UpdateSeason(c *fiber.Ctx) error {
+	gameKey := gameKeyOrDefault(c.Query("gameKey"))
+
+	var req UpdateSeasonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "請求格式錯誤", "details": err.Error()})
+	}
+	if req.StartDate == nil && req.EndDate == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "沒有要更新的欄位"})
+	}
+
+	game, err := h.store.Games().GetByKey(gameKey)
+	if err != nil {
+		return gameLookupError(c, gameKey, err)
+	}
+	code, ok := normalizeSeasonCode(game, c.Params("code"))
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "找不到賽季", "code": c.Params("code")})
+	}
+
+	var updated models.Season
+	err = h.store.InTx(func(tx repo.Store) error {
+		s, err := tx.Seasons().GetByCode(game.ID, code)
+		if err != nil {
+			return err
+		}
+		if req.StartDate != nil {
+			s.StartDate = req.StartDate
+		}
+		if req.EndDate != nil {
+			s.EndDate = req.EndDate
+		}
+		if msg := validateSeasonDates(s); msg != "" {
+			return &seasonDateError{msg}
+		}
+		updated = s
+		return tx.Seasons().Update(s)
+	})
+
+	var dateErr *seasonDateError
+	switch {
+	case errors.As(err, &dateErr):
+		return c.Status(400).JSON(fiber.Map{"error": dateErr.msg})
+	case errors.Is(err, repo.ErrNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "找不到賽季", "code": code})
+	case err != nil:
+		return c.Status(500).JSON(fiber.Map{"error": "更新失敗", "details": err.Error()})
+	}
+	return c.JSON(updated)
+}
+
+// seasonDateError 起訖日不合法（msg 即回傳給前端的錯誤訊息）
+type seasonDateError struct{ msg string }
+
+func (e *seasonDateError) Error() string { return e.msg }
+
+// validateSeasonDates 起訖日必須是 YYYY-MM-DD，且開始日不晚於結束日；合法時回傳空字串
+func validateSeasonDates(s models.Season) string {
+	for _, d := range []*string{s.StartDate, s.EndDate} {
+		if d == nil {
+			continue
+		}
+		if _, err := time.Parse(season.DateLayout, *d); err != nil {
+			return "日期格式錯誤（YYYY-MM-DD）"
+		}
+	}
+	if s.StartDate != nil && s.EndDate != nil && *s.StartDate > *s.EndDate {
+		return "startDate 不可晚於 endDate"
+	}
+	return ""
+}
+
+// normalizeSeasonCode 有賽季曆的遊戲依賽季曆正規化代碼，不認得時 ok 為 false；沒有賽季曆時原樣回傳
+func normalizeSeasonCode(game models.Game, code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	cal, hasCalendar := season.For(game.Key)
+	if !hasCalendar {
+		return code, true
+	}
+	info, ok := cal.Parse(code)
+	return info.Code, ok
+}
+
+// newSeason 建立賽季資料，起訖日依遊戲的賽季曆推算（無法推算時為 null）
+func newSeason(game models.Game, code string) models.Season {
+	s := models.Season{ID: uuid.New().String(), GameID: game.ID, Code: code}
+	if start, end, ok := season.Dates(game.Key, code); ok {
+		s.StartDate, s.EndDate = &start, &end
+	}
+	return s
+}
+
+// errInvalidMatchDate 對局日期不是 YYYY-MM-DD
+var errInvalidMatchDate = errors.New("date 格式錯誤（YYYY-MM-DD）")
+
+// userLocation 使用者設定的時區；找不到使用者時為 UTC
+func userLocation(users repo.UserRepository, userID string) (*time.Location, error) {
+	user, err := users.GetByID(userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return season.LoadLocation(user.Timezone)
+}
+
+// localMatchDate 對局在使用者當地的日期（YYYY-MM-DD）：
+// 有 playedAt 時依 loc 換算（同時指定 date 時必須相符），否則為 date 本身
+func localMatchDate(loc *time.Location, date string, playedAt *time.Time) (string, error) {
+	if playedAt != nil {
+		local := season.LocalDate(*playedAt, loc)
+		if date == "" {
+			return local, nil
+		}
+		if parsed, ok := season.ParseDate(date); !ok || parsed.Format(season.DateLayout) != local {
+			return "", &matchDateMismatchError{date: date, expected: local, timezone: loc.String()}
+		}
+		return local, nil
+	}
+	parsed, ok := season.ParseDate(date)
+	if !ok {
+		return "", errInvalidMatchDate
+	}
+	return parsed.Format(season.DateLayout), nil
+}
+
+// matchSeasonDate 判斷賽季用的日期：有 playedAt 時依遊戲的賽季切換時間換算，否則為 date
+func matchSeasonDate(game models.Game, date string, playedAt *time.Time) (time.Time, error) {
+	if playedAt != nil {
+		reset, err := season.ParseReset(game.SeasonResetTime, game.SeasonTimezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return reset.Date(*playedAt), nil
+	}
+	parsed, ok := season.ParseDate(date)
+	if !ok {
+		return time.Time{}, errInvalidMatchDate
+	}
+	return parsed, nil
+}
+
+// matchSeasonID 取得對局要使用的 season_id：不存在時建立（起訖日依賽季曆推算），
+// 代碼已存在但沒有起訖日（舊資料）時補上
+func matchSeasonID(tx repo.Store, game models.Game, code string) (string, error) {
+	s := newSeason(game, code)
+	existing, err := tx.Seasons().GetByCode(game.ID, code)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return tx.Seasons().GetOrCreate(s)
+	case err != nil:
+		return "", err
+	case existing.StartDate == nil && existing.EndDate == nil && s.StartDate != nil:
+		s.ID = existing.ID
+		return existing.ID, tx.Seasons().Update(s)
+	default:
+		return existing.ID, nil
+	}
+}
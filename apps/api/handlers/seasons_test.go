@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCreateSeasonNormalizesCode(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		admin := s.register("admin@example.com")
+
+		status, body := s.do(http.MethodPost, "/seasons", admin, map[string]string{"code": " s49 "})
+		if status != http.StatusCreated || body["code"] != "S49" || body["startDate"] != "2026-01-01" || body["endDate"] != "2026-01-31" {
+			t.Fatalf("POST /seasons s49 = %d %v, want 201 S49 for 2026-01", status, body)
+		}
+		if status, body := s.do(http.MethodPost, "/seasons", admin, map[string]string{"code": "S49"}); status != http.StatusConflict {
+			t.Errorf("POST /seasons S49 again = %d %v, want 409", status, body)
+		}
+		for _, code := range []string{"S4x", "2026-01", "Season 49"} {
+			if status, body := s.do(http.MethodPost, "/seasons", admin, map[string]string{"code": code}); status != http.StatusBadRequest {
+				t.Errorf("POST /seasons %q = %d %v, want 400", code, status, body)
+			}
+		}
+
+		if status, body := s.do(http.MethodPatch, "/seasons/s49", admin, map[string]string{"endDate": "2026-02-01"}); status != http.StatusOK || body["code"] != "S49" {
+			t.Errorf("PATCH /seasons/s49 = %d %v, want 200 for S49", status, body)
+		}
+	})
+}
+
+func TestMatchSeasonFollowsAdjustedDates(t *testing.T) {
+	eachStore(t, func(t *testing.T, s *testServer) {
+		admin := s.register("admin@example.com")
+		alice := s.register("alice@example.com")
+
+		// S57 延長到 10/2，S58 改從 10/3 開始
+		adjust := []struct {
+			code  string
+			patch map[string]string
+		}{
+			{"S57", map[string]string{"endDate": "2026-10-02"}},
+			{"S58", map[string]string{"startDate": "2026-10-03"}},
+		}
+		for _, a := range adjust {
+			if status, body := s.do(http.MethodPost, "/seasons", admin, map[string]string{"code": a.code}); status != http.StatusCreated {
+				t.Fatalf("POST /seasons %s = %d %v", a.code, status, body)
+			}
+			if status, body := s.do(http.MethodPatch, "/seasons/"+a.code, admin, a.patch); status != http.StatusOK {
+				t.Fatalf("PATCH /seasons/%s = %d %v", a.code, status, body)
+			}
+		}
+
+		tests := []struct {
+			seasonCode interface{}
+			date       string
+			want       string
+		}{
+			{nil, "2026-10-02", "S57"},
+			{"S57", "2026-10-02", "S57"},
+			{nil, "2026-10-03", "S58"},
+			{nil, "2026-11-01", "S59"}, // 資料庫中沒有的賽季依賽季曆推算
+		}
+		for _, tt := range tests {
+			status, body := s.do(http.MethodPost, "/matches", alice, matchRequest(map[string]interface{}{"seasonCode": tt.seasonCode, "date": tt.date}))
+			if status != http.StatusCreated || body["seasonCode"] != tt.want {
+				t.Errorf("POST /matches seasonCode=%v date=%s = %d %v, want 201 in %s", tt.seasonCode, tt.date, status, body, tt.want)
+			}
+		}
+
+		status, body := s.do(http.MethodPost, "/matches", alice, matchRequest(map[string]interface{}{"seasonCode": "S58", "date": "2026-10-02"}))
+		if status != http.StatusBadRequest || body["expected"] != "S57" {
+			t.Errorf("POST /matches with S58 on 2026-10-02 = %d %v, want 400 expecting S57", status, body)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/harvc/duellog/apps/api/auth"
+	"github.com/harvc/duellog/apps/api/handlers"
+	"github.com/harvc/duellog/apps/api/migrate"
+	"github.com/harvc/duellog/apps/api/migrations"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/repo/memrepo"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// testServer 經由 handlers.Register 組出與 main.go 相同的路由（需要登入）
+type testServer struct {
+	t   *testing.T
+	app *fiber.App
+}
+
+// newTestServer 使用暫存的 SQLite 檔案與真正的 sqlrepo（含 /stats）
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	db, err := storage.Open("", filepath.Join(t.TempDir(), "duellog.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	source, err := migrations.For(db.Dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := migrate.Load(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrate.New(db, set).Up(); err != nil {
+		t.Fatal(err)
+	}
+	// seed.sql 的最小子集：預設使用者與 Master Duel
+	if _, err := db.Exec(`
+		INSERT INTO users (id, email, password_hash, created_at, updated_at)
+		VALUES ('user-001', 'demo@duellog.com', 'placeholder_hash', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+		INSERT INTO games (id, key, name) VALUES ('game-md', 'master_duel', 'Yu-Gi-Oh! Master Duel');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	return newServer(t, sqlrepo.New(db), db)
+}
+
+// newMemServer 使用 memrepo（預設資料與上面相同），不需要資料庫；沒有 /stats
+func newMemServer(t *testing.T) *testServer {
+	t.Helper()
+	return newServer(t, memrepo.New(), nil)
+}
+
+// eachStore 分別以 memrepo 與 sqlrepo 執行 fn，確認兩種實作的行為一致
+func eachStore(t *testing.T, fn func(t *testing.T, s *testServer)) {
+	t.Run("memrepo", func(t *testing.T) { fn(t, newMemServer(t)) })
+	t.Run("sqlrepo", func(t *testing.T) { fn(t, newTestServer(t)) })
+}
+
+func newServer(t *testing.T, store repo.Store, db *storage.DB) *testServer {
+	t.Helper()
+	tokens, err := auth.NewTokens("test-secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := fiber.New()
+	handlers.Register(app, handlers.Deps{
+		Store:        store,
+		DB:           db,
+		Tokens:       tokens,
+		AuthRequired: true,
+		AdminEmails:  []string{"admin@example.com"},
+	})
+	return &testServer{t: t, app: app}
+}
+
+// do 送出請求並解析 JSON 回應
+func (s *testServer) do(method, path, token string, body interface{}) (int, map[string]interface{}) {
+	s.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := s.app.Test(req, -1)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && err != io.EOF {
+		s.t.Fatalf("%s %s: decode response: %v", method, path, err)
+	}
+	return resp.StatusCode, out
+}
+
+// register 註冊使用者並回傳 token
+func (s *testServer) register(email string) string {
+	s.t.Helper()
+	status, body := s.do(http.MethodPost, "/auth/register", "", map[string]string{"email": email, "password": "password123"})
+	if status != http.StatusCreated {
+		s.t.Fatalf("register %s: status %d: %v", email, status, body)
+	}
+	return body["token"].(string)
+}
+
+// createMatch 以 token 的使用者新增一場 Ranked 對局並回傳 ID
+func (s *testServer) createMatch(token string, extra map[string]interface{}) string {
+	s.t.Helper()
+	status, body := s.do(http.MethodPost, "/matches", token, matchRequest(extra))
+	if status != http.StatusCreated {
+		s.t.Fatalf("create match: status %d: %v", status, body)
+	}
+	return body["id"].(string)
+}
+
+// listLen 回應中 key 對應的陣列長度
+func listLen(t *testing.T, body map[string]interface{}, key string) int {
+	t.Helper()
+	list, ok := body[key].([]interface{})
+	if !ok {
+		t.Fatalf("response has no %q list: %v", key, body)
+	}
+	return len(list)
+}
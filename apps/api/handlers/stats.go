@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/harvc/duellog/apps/api/ladder"
+	"github.com/harvc/duellog/apps/api/stats"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// StatsHandler 處理 /stats 相關請求（口徑見 docs/spec.md §5.3）
+//
+// 所有統計端點都可加上 ci=wilson|beta 取得勝率的區間估計與收縮估計：
+//   - level: 信賴水準（預設 0.95）
+//   - prior: 收縮強度，以虛擬場數表示（預設 10）
+//   - maxWidth: 區間寬度超過此百分點即標記 lowConfidence（預設 30）
+type StatsHandler struct {
+	db *storage.DB
+}
+
+// NewStatsHandler 建立新的 stats handler
+func NewStatsHandler(db *storage.DB) *StatsHandler {
+	return &StatsHandler{db: db}
+}
+
+// GetSummary KPI 統計 (GET /stats/summary)
+// 篩選參數與 GET /matches 相同：seasonCode, mode, myDeckMain, oppDeckMain, dateFrom, dateTo ...
+func (h *StatsHandler) GetSummary(c *fiber.Ctx) error {
+	est, err := parseEstimator(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	summary, err := stats.GetSummary(h.db, parseMatchFilter(c), est)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "統計失敗", "details": err.Error()})
+	}
+	return c.JSON(summary)
+}
+
+// GetDaily 每日統計 (GET /stats/daily)
+// 指定 dateFrom+dateTo 或 seasonCode 時，區間內沒有對局的日子會補 0（區間最多 stats.MaxDailyDays 天）。
+func (h *StatsHandler) GetDaily(c *fiber.Ctx) error {
+	est, err := parseEstimator(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	daily, err := stats.GetDaily(h.db, parseMatchFilter(c), est)
+	if errors.Is(err, stats.ErrDailyRangeTooLong) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "統計失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"daily": daily,
+		"total": len(daily),
+	})
+}
+
+// GetOpponents 對手牌組分布 (GET /stats/opponents)
+// groupBy=sub 時以「大軸 + 小軸」分組，預設只看大軸。
+func (h *StatsHandler) GetOpponents(c *fiber.Ctx) error {
+	return h.getDecks(c, stats.SideOpponent)
+}
+
+// GetMyDecks 我方牌組統計 (GET /stats/my-decks)
+// groupBy=sub 時以「大軸 + 小軸」分組，預設只看大軸。
+func (h *StatsHandler) GetMyDecks(c *fiber.Ctx) error {
+	return h.getDecks(c, stats.SideMine)
+}
+
+func (h *StatsHandler) getDecks(c *fiber.Ctx, side stats.DeckSide) error {
+	bySub, err := parseGroupBy(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	est, err := parseEstimator(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	decks, err := stats.GetDecks(h.db, parseMatchFilter(c), side, bySub, est)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "統計失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"decks": decks,
+		"total": len(decks),
+	})
+}
+
+// GetMatchups 對戰矩陣：我方牌組 × 對手牌組 (GET /stats/matchups)
+// minGames 為每格最少場數（預設 1）；groupBy=sub 時以「大軸 + 小軸」為單位。
+func (h *StatsHandler) GetMatchups(c *fiber.Ctx) error {
+	bySub, err := parseGroupBy(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	minGames := c.QueryInt("minGames", 1)
+	if minGames < 1 {
+		return c.Status(400).JSON(fiber.Map{"error": "minGames 必須是正整數"})
+	}
+
+	est, err := parseEstimator(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	matchups, err := stats.GetMatchups(h.db, parseMatchFilter(c), minGames, bySub, est)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "統計失敗", "details": err.Error()})
+	}
+	return c.JSON(matchups)
+}
+
+// GetRankTimeline 各賽季的階級變化、升降階與各 tier 勝率 (GET /stats/rank-timeline)
+// 只統計有記錄階級的對局；可搭配 seasonCode 等篩選條件。
+func (h *StatsHandler) GetRankTimeline(c *fiber.Ctx) error {
+	est, err := parseEstimator(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	seasons, err := stats.GetRankTimeline(h.db, parseMatchFilter(c), est)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "統計失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"seasons": seasons,
+		"total":   len(seasons),
+	})
+}
+
+// GetRatingHistory 各活動（模式 + 賽季）的積分曲線與最高 / 最低積分 (GET /stats/rating)
+// 只統計有記錄積分的對局；可搭配 mode、seasonCode 等篩選條件。
+func (h *StatsHandler) GetRatingHistory(c *fiber.Ctx) error {
+	est, err := parseEstimator(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	events, err := stats.GetRatingHistory(h.db, parseMatchFilter(c), est)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "統計失敗", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{
+		"events": events,
+		"total":  len(events),
+	})
+}
+
+// SimulateLadder 天梯模擬：到達目標階級需要的場數分布 (GET /stats/ladder-sim)
+// 目前階級與先後攻勝率預設取自最近 recent 場有記錄階級的對局，可用 from / firstRate / firstWinRate / secondWinRate 覆蓋；
+// target 預設為大師 V，接受代碼或顯示名稱。runs / maxGames / seed 控制模擬次數、單次場數上限與亂數種子。
+// 由對局推算的比率場數少於 minSample（預設 30）時，回傳 lowConfidence 與 warning。
+// runs × maxGames 不可超過 ladder.MaxSteps；模擬超過 ladderSimTimeout 時回傳 503。
+func (h *StatsHandler) SimulateLadder(c *fiber.Ctx) error {
+	opts, err := parseLadderOptions(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	f := parseMatchFilter(c)
+	if opts, err = opts.Validate(f.GameKey); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// fasthttp 不會在連線中斷時取消 context，因此另外設定模擬時間上限
+	ctx, cancel := context.WithTimeout(c.UserContext(), ladderSimTimeout)
+	defer cancel()
+	sim, err := stats.SimulateLadder(ctx, h.db, f, opts)
+	if errors.Is(err, stats.ErrNoRankedMatches) || errors.Is(err, ladder.ErrTargetReached) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return c.Status(503).JSON(fiber.Map{"error": "模擬逾時，請減少 runs 或 maxGames"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "模擬失敗", "details": err.Error()})
+	}
+	return c.JSON(sim)
+}
+
+// ladderSimTimeout 單次天梯模擬的時間上限
+const ladderSimTimeout = 10 * time.Second
+
+// parseLadderOptions 讀取天梯模擬的查詢參數（未提供的使用預設值）
+func parseLadderOptions(c *fiber.Ctx) (stats.LadderOptions, error) {
+	opts := stats.NewLadderOptions()
+	opts.From = c.Query("from")
+	opts.Target = c.Query("target", opts.Target)
+	opts.Recent = c.QueryInt("recent", opts.Recent)
+	opts.MinSample = c.QueryInt("minSample", opts.MinSample)
+	opts.Runs = c.QueryInt("runs", opts.Runs)
+	opts.MaxGames = c.QueryInt("maxGames", opts.MaxGames)
+	if raw := c.Query("seed"); raw != "" {
+		seed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return opts, errors.New("seed 必須是整數")
+		}
+		opts.Seed = seed
+	}
+
+	var err error
+	if opts.FirstRate, err = queryOptionalFloat(c, "firstRate"); err != nil {
+		return opts, err
+	}
+	if opts.FirstWinRate, err = queryOptionalFloat(c, "firstWinRate"); err != nil {
+		return opts, err
+	}
+	if opts.SecondWinRate, err = queryOptionalFloat(c, "secondWinRate"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// parseGroupBy 解析 groupBy 參數："main"（預設）或 "sub"
+func parseGroupBy(c *fiber.Ctx) (bool, error) {
+	switch c.Query("groupBy", "main") {
+	case "main":
+		return false, nil
+	case "sub":
+		return true, nil
+	default:
+		return false, errors.New("groupBy 只能是 main 或 sub")
+	}
+}
+
+// parseEstimator 解析 ci / level / prior / maxWidth 參數；未指定 ci 時回傳 nil（不計算區間）
+func parseEstimator(c *fiber.Ctx) (*stats.Estimator, error) {
+	method := c.Query("ci")
+	if method == "" {
+		return nil, nil
+	}
+	level, err := queryFloat(c, "level", stats.DefaultLevel)
+	if err != nil {
+		return nil, err
+	}
+	prior, err := queryFloat(c, "prior", stats.DefaultPriorGames)
+	if err != nil {
+		return nil, err
+	}
+	maxWidth, err := queryFloat(c, "maxWidth", stats.DefaultMaxWidth)
+	if err != nil {
+		return nil, err
+	}
+	return stats.NewEstimator(stats.IntervalMethod(method), level, prior, maxWidth)
+}
+
+// queryFloat 讀取浮點數查詢參數；未提供時回傳 def
+func queryFloat(c *fiber.Ctx, key string, def float64) (float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return def, nil
+	}
+	// ParseFloat 接受 "NaN" 與 "Inf"：這兩種值會讓範圍檢查失效，也無法輸出成 JSON
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%s 必須是數字", key)
+	}
+	return v, nil
+}
+
+// queryOptionalFloat 讀取浮點數查詢參數；未提供時回傳 nil
+func queryOptionalFloat(c *fiber.Ctx, key string) (*float64, error) {
+	if c.Query(key) == "" {
+		return nil, nil
+	}
+	v, err := queryFloat(c, key, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,62 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDailyZeroFillRangeIsCapped(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.register("alice@example.com")
+
+	tests := []struct {
+		query string
+		want  int
+		days  int
+	}{
+		{"dateFrom=2024-01-01&dateTo=2024-12-31", http.StatusOK, 366},
+		{"dateFrom=2024-01-01&dateTo=2025-01-01", http.StatusBadRequest, 0},
+		{"dateFrom=1900-01-01&dateTo=2100-12-31", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		status, body := s.do(http.MethodGet, "/stats/daily?"+tt.query, alice, nil)
+		if status != tt.want {
+			t.Errorf("GET /stats/daily?%s = %d %v, want %d", tt.query, status, body, tt.want)
+			continue
+		}
+		if tt.want == http.StatusOK && listLen(t, body, "daily") != tt.days {
+			t.Errorf("GET /stats/daily?%s returned %d days, want %d", tt.query, listLen(t, body, "daily"), tt.days)
+		}
+	}
+}
+
+func TestEstimatorParamsRejectNonFinite(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.register("alice@example.com")
+	s.createMatch(alice, nil)
+
+	for _, query := range []string{"level=NaN", "prior=NaN", "prior=Inf", "maxWidth=-Inf", "level=0.9&prior=nan"} {
+		if status, body := s.do(http.MethodGet, "/stats/summary?ci=wilson&"+query, alice, nil); status != http.StatusBadRequest {
+			t.Errorf("GET /stats/summary?ci=wilson&%s = %d %v, want 400", query, status, body)
+		}
+	}
+	if status, body := s.do(http.MethodGet, "/stats/summary?ci=beta&level=0.9&prior=5", alice, nil); status != http.StatusOK || body["winRateCI"] == nil {
+		t.Errorf("GET /stats/summary?ci=beta = %d %v, want 200 with winRateCI", status, body)
+	}
+}
+
+func TestLadderSimRejectsOversizedAndNaNParams(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.register("alice@example.com")
+	s.createMatch(alice, nil)
+
+	for _, query := range []string{"runs=100000&maxGames=20000", "runs=10000&maxGames=5001", "firstWinRate=NaN", "secondWinRate=nan"} {
+		if status, body := s.do(http.MethodGet, "/stats/ladder-sim?"+query, alice, nil); status != http.StatusBadRequest {
+			t.Errorf("GET /stats/ladder-sim?%s = %d %v, want 400", query, status, body)
+		}
+	}
+	status, body := s.do(http.MethodGet, "/stats/ladder-sim?runs=200&seed=7&firstWinRate=60&secondWinRate=50", alice, nil)
+	if status != http.StatusOK || body["seed"] != float64(7) || body["runs"] != float64(200) {
+		t.Errorf("GET /stats/ladder-sim = %d %v, want 200 with seed 7", status, body)
+	}
+}
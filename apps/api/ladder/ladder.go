@@ -0,0 +1,240 @@
+// Package ladder 以蒙地卡羅模擬天梯：依目前階級、遊戲的升降規則（rules.Ladder）與先後攻的勝率，
+// 反覆模擬到達目標階級需要的場數，回傳場數的分布。
+//
+// 每場先依先攻機率決定先後攻，再依該方的勝率 / 平手率決定結果；模擬從目前階級的 0 分開始。
+package ladder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/harvc/duellog/apps/api/rules"
+)
+
+// 模擬次數與單次場數上限
+const (
+	DefaultRuns     = 10000
+	MaxRuns         = 100000
+	DefaultMaxGames = 5000 // 超過仍未到達目標即視為未到達
+	MaxMaxGames     = 20000
+	// MaxSteps 一次模擬最多模擬的場數（Runs × MaxGames），約 0.5 秒
+	MaxSteps = 50_000_000
+)
+
+// histogramBuckets 分布圖最多切成幾格
+const histogramBuckets = 20
+
+// percentilePoints 回傳的百分位數
+var percentilePoints = []int{10, 25, 50, 75, 90}
+
+// ErrTargetReached 目前階級已經不低於目標階級
+var ErrTargetReached = errors.New("目前階級已達目標階級")
+
+// Side 先攻或後攻時的機率（0-1）
+type Side struct {
+	Win  float64
+	Draw float64
+}
+
+// Rates 模擬使用的機率（0-1）
+type Rates struct {
+	FirstRate float64 // 先攻的機率
+	First     Side
+	Second    Side
+}
+
+// Config 一次模擬的設定
+type Config struct {
+	Ranks    []string     // 階級表（由低到高）
+	Rules    rules.Ladder // 各階的升降規則
+	From     string       // 目前階級（代碼）
+	Target   string       // 目標階級（代碼）
+	Rates    Rates
+	Runs     int
+	MaxGames int
+	Seed     int64
+}
+
+// Percentile 第 P 百分位需要的場數；Games 為 nil 表示該比例的模擬在場數上限內未到達
+type Percentile struct {
+	P     int  `json:"p"`
+	Games *int `json:"games"`
+}
+
+// Bucket 分布圖的一格：到達時的場數介於 From ～ To（含）的模擬次數
+type Bucket struct {
+	From          int     `json:"from"`
+	To            int     `json:"to"`
+	Count         int     `json:"count"`
+	Pct           float64 `json:"pct"`           // 佔全部模擬的比例（0-100）
+	CumulativePct float64 `json:"cumulativePct"` // 在 To 場以內到達的比例（0-100）
+}
+
+// Result 模擬結果（場數皆只計到達目標的模擬）
+type Result struct {
+	Runs        int          `json:"runs"`
+	MaxGames    int          `json:"maxGames"`
+	Reached     int          `json:"reached"`
+	ReachedRate float64      `json:"reachedRate"` // 0-100
+	Mean        *float64     `json:"mean"`
+	Min         *int         `json:"min"`
+	Max         *int         `json:"max"`
+	Percentiles []Percentile `json:"percentiles"`
+	Histogram   []Bucket     `json:"histogram"`
+}
+
+// Simulate 執行 cfg.Runs 次模擬；ctx 取消時中止並回傳 ctx.Err()
+func Simulate(ctx context.Context, cfg Config) (Result, error) {
+	from, target, levels, err := cfg.resolve()
+	if err != nil {
+		return Result{}, err
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	games := make([]int, 0, cfg.Runs)
+	for i := 0; i < cfg.Runs; i++ {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		if n, ok := climb(rng, cfg.Rates, levels, from, target, cfg.MaxGames); ok {
+			games = append(games, n)
+		}
+	}
+	sort.Ints(games)
+	return summarize(games, cfg.Runs, cfg.MaxGames), nil
+}
+
+// resolve 驗證設定並取出目前 / 目標階級的位置與目標以下各階的規則
+func (cfg Config) resolve() (from, target int, levels []rules.LevelRule, err error) {
+	if cfg.Runs < 1 || cfg.Runs > MaxRuns {
+		return 0, 0, nil, fmt.Errorf("runs 必須介於 1 與 %d 之間", MaxRuns)
+	}
+	if cfg.MaxGames < 1 || cfg.MaxGames > MaxMaxGames {
+		return 0, 0, nil, fmt.Errorf("maxGames 必須介於 1 與 %d 之間", MaxMaxGames)
+	}
+	if cfg.Runs*cfg.MaxGames > MaxSteps {
+		return 0, 0, nil, fmt.Errorf("runs × maxGames 不可超過 %d", MaxSteps)
+	}
+	for _, side := range []Side{cfg.Rates.First, cfg.Rates.Second} {
+		if math.IsNaN(side.Win) || math.IsNaN(side.Draw) || side.Win < 0 || side.Draw < 0 || side.Win+side.Draw > 1 {
+			return 0, 0, nil, errors.New("勝率與平手率必須介於 0 與 1 之間，且合計不超過 1")
+		}
+	}
+	if math.IsNaN(cfg.Rates.FirstRate) || cfg.Rates.FirstRate < 0 || cfg.Rates.FirstRate > 1 {
+		return 0, 0, nil, errors.New("先攻機率必須介於 0 與 1 之間")
+	}
+
+	from, target = indexOf(cfg.Ranks, cfg.From), indexOf(cfg.Ranks, cfg.Target)
+	if from < 0 || target < 0 {
+		return 0, 0, nil, fmt.Errorf("階級不在階級表中: %s → %s", cfg.From, cfg.Target)
+	}
+	if from >= target {
+		return 0, 0, nil, ErrTargetReached
+	}
+
+	// 降階可能落到目前階級以下，因此需要目標以下所有階的規則
+	levels = make([]rules.LevelRule, target)
+	for i := range levels {
+		rule, ok := cfg.Rules.LevelRule(cfg.Ranks[i])
+		if !ok || rule.WinsToPromote < 1 {
+			return 0, 0, nil, fmt.Errorf("%s 沒有升降規則", cfg.Ranks[i])
+		}
+		levels[i] = rule
+	}
+	return from, target, levels, nil
+}
+
+// climb 模擬一次：回傳到達 target 時的場數；maxGames 場內未到達時 ok 為 false
+func climb(rng *rand.Rand, rates Rates, levels []rules.LevelRule, pos, target, maxGames int) (int, bool) {
+	points, zeroLosses := 0, 0
+	for game := 1; game <= maxGames; game++ {
+		rule := levels[pos]
+		side := rates.Second
+		if rng.Float64() < rates.FirstRate {
+			side = rates.First
+		}
+
+		x := rng.Float64()
+		switch {
+		case x < side.Win:
+			zeroLosses = 0
+			if points++; points >= rule.WinsToPromote {
+				pos, points = pos+1, 0
+				if pos >= target {
+					return game, true
+				}
+			}
+		case x < side.Win+side.Draw:
+			// 平手：積分不變
+		case points > 0:
+			if rule.LossPenalty {
+				points--
+			}
+		case rule.DemoteAfter > 0:
+			if zeroLosses++; zeroLosses >= rule.DemoteAfter && pos > 0 {
+				pos, zeroLosses = pos-1, 0
+			}
+		}
+	}
+	return 0, false
+}
+
+// summarize 由到達時的場數（已排序）算出平均、百分位數與分布圖
+func summarize(games []int, runs, maxGames int) Result {
+	r := Result{
+		Runs:        runs,
+		MaxGames:    maxGames,
+		Reached:     len(games),
+		ReachedRate: float64(len(games)) / float64(runs) * 100,
+		Percentiles: make([]Percentile, 0, len(percentilePoints)),
+		Histogram:   []Bucket{},
+	}
+	for _, p := range percentilePoints {
+		pc := Percentile{P: p}
+		// 第 p 百分位為第 ceil(runs*p/100) 次模擬（未到達的模擬視為無限多場）
+		if i := int(math.Ceil(float64(runs)*float64(p)/100)) - 1; i < len(games) {
+			n := games[max(i, 0)]
+			pc.Games = &n
+		}
+		r.Percentiles = append(r.Percentiles, pc)
+	}
+	if len(games) == 0 {
+		return r
+	}
+
+	sum := 0
+	for _, n := range games {
+		sum += n
+	}
+	mean := float64(sum) / float64(len(games))
+	lo, hi := games[0], games[len(games)-1]
+	r.Mean, r.Min, r.Max = &mean, &lo, &hi
+
+	width := (hi-lo)/histogramBuckets + 1
+	cumulative := 0
+	for i := 0; i < len(games); {
+		b := Bucket{From: lo + len(r.Histogram)*width}
+		b.To = b.From + width - 1
+		for ; i < len(games) && games[i] <= b.To; i++ {
+			b.Count++
+		}
+		cumulative += b.Count
+		b.Pct = float64(b.Count) / float64(runs) * 100
+		b.CumulativePct = float64(cumulative) / float64(runs) * 100
+		r.Histogram = append(r.Histogram, b)
+	}
+	return r
+}
+
+func indexOf(list []string, v string) int {
+	for i, s := range list {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}
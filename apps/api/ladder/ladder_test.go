@@ -0,0 +1,214 @@
+package ladder
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/harvc/duellog/apps/api/rules"
+)
+
+// testLadder 以 map 提供各階的升降規則
+type testLadder map[string]rules.LevelRule
+
+func (l testLadder) LevelRule(rank string) (rules.LevelRule, bool) {
+	r, ok := l[rank]
+	return r, ok
+}
+
+// r1 → r4 全勝需要 2 + 3 + 4 = 9 場
+var (
+	testRanks = []string{"r1", "r2", "r3", "r4"}
+	testRules = testLadder{
+		"r1": {WinsToPromote: 2},
+		"r2": {WinsToPromote: 3, LossPenalty: true, DemoteAfter: 2},
+		"r3": {WinsToPromote: 4, LossPenalty: true, DemoteAfter: 3},
+	}
+)
+
+// testConfig 先後攻勝率皆為 win 的設定（seed 固定）
+func testConfig(from string, win float64) Config {
+	side := Side{Win: win}
+	return Config{
+		Ranks:    testRanks,
+		Rules:    testRules,
+		From:     from,
+		Target:   "r4",
+		Rates:    Rates{FirstRate: 0.5, First: side, Second: side},
+		Runs:     1000,
+		MaxGames: 500,
+		Seed:     42,
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestSimulateAlwaysWinning(t *testing.T) {
+	tests := []struct {
+		from  string
+		games int
+	}{
+		{"r1", 9},
+		{"r2", 7},
+		{"r3", 4},
+	}
+	for _, tt := range tests {
+		cfg := testConfig(tt.from, 1)
+		r, err := Simulate(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.from, err)
+		}
+		if r.Reached != cfg.Runs || r.ReachedRate != 100 || *r.Min != tt.games || *r.Max != tt.games || *r.Mean != float64(tt.games) {
+			t.Errorf("%s: reached %d (%.1f%%), min %d, max %d, mean %.1f; want every run in %d games",
+				tt.from, r.Reached, r.ReachedRate, *r.Min, *r.Max, *r.Mean, tt.games)
+		}
+		for _, p := range r.Percentiles {
+			if p.Games == nil || *p.Games != tt.games {
+				t.Errorf("%s: P%d = %v, want %d", tt.from, p.P, p.Games, tt.games)
+			}
+		}
+		want := []Bucket{{From: tt.games, To: tt.games, Count: cfg.Runs, Pct: 100, CumulativePct: 100}}
+		if !reflect.DeepEqual(r.Histogram, want) {
+			t.Errorf("%s: histogram = %+v, want %+v", tt.from, r.Histogram, want)
+		}
+	}
+}
+
+func TestSimulateNeverWinning(t *testing.T) {
+	r, err := Simulate(context.Background(), testConfig("r1", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Reached != 0 || r.ReachedRate != 0 || r.Mean != nil || r.Min != nil || r.Max != nil || len(r.Histogram) != 0 {
+		t.Errorf("no wins: %+v, want nothing reached", r)
+	}
+	for _, p := range r.Percentiles {
+		if p.Games != nil {
+			t.Errorf("P%d = %d, want nil (not reached)", p.P, *p.Games)
+		}
+	}
+}
+
+func TestSimulateIsDeterministicPerSeed(t *testing.T) {
+	cfg := testConfig("r1", 0.55)
+	first, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, again) {
+		t.Errorf("same seed gave different results:\n%+v\n%+v", first, again)
+	}
+
+	cfg.Seed = 43
+	other, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Error("different seeds gave identical results")
+	}
+
+	// 55% 勝率仍會輸，場數至少 9 場，百分位數不遞減
+	if first.Reached == 0 || *first.Min < 9 || *first.Mean <= 9 {
+		t.Fatalf("55%% win rate: %+v", first)
+	}
+	prev := 0
+	for _, p := range first.Percentiles {
+		if p.Games == nil || *p.Games < prev {
+			t.Fatalf("percentiles not increasing: %+v", first.Percentiles)
+		}
+		prev = *p.Games
+	}
+}
+
+func TestSimulateHonoursContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Simulate(ctx, testConfig("r1", 0.5)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Simulate with a cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestSimulateRejectsInvalidConfig(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"NaN win rate", func(c *Config) { c.Rates.First.Win = nan }},
+		{"NaN draw rate", func(c *Config) { c.Rates.Second.Draw = nan }},
+		{"NaN first rate", func(c *Config) { c.Rates.FirstRate = nan }},
+		{"negative win rate", func(c *Config) { c.Rates.Second.Win = -0.1 }},
+		{"win + draw above 1", func(c *Config) { c.Rates.First = Side{Win: 0.8, Draw: 0.3} }},
+		{"first rate above 1", func(c *Config) { c.Rates.FirstRate = 1.5 }},
+		{"no runs", func(c *Config) { c.Runs = 0 }},
+		{"too many runs", func(c *Config) { c.Runs = MaxRuns + 1 }},
+		{"too many games", func(c *Config) { c.MaxGames = MaxMaxGames + 1 }},
+		{"over the step budget", func(c *Config) { c.Runs, c.MaxGames = MaxRuns, MaxMaxGames }},
+		{"unknown rank", func(c *Config) { c.From = "r0" }},
+		{"missing level rule", func(c *Config) { c.Rules = testLadder{"r1": {WinsToPromote: 2}} }},
+	}
+	for _, tt := range tests {
+		cfg := testConfig("r1", 0.5)
+		tt.modify(&cfg)
+		if _, err := Simulate(context.Background(), cfg); err == nil {
+			t.Errorf("%s: Simulate accepted %+v", tt.name, cfg)
+		}
+	}
+
+	cfg := testConfig("r4", 0.5)
+	if _, err := Simulate(context.Background(), cfg); !errors.Is(err, ErrTargetReached) {
+		t.Errorf("from the target rank = %v, want ErrTargetReached", err)
+	}
+	cfg = testConfig("r1", 0.5)
+	cfg.Runs, cfg.MaxGames = MaxRuns, MaxSteps/MaxRuns
+	if _, err := Simulate(context.Background(), cfg); err != nil {
+		t.Errorf("runs × maxGames at the budget: %v", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	// 20 次模擬中 10 次到達：P75 以上落在未到達的模擬
+	games := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	r := summarize(games, 20, 100)
+	if r.Reached != 10 || r.ReachedRate != 50 || *r.Mean != 5.5 || *r.Min != 1 || *r.Max != 10 {
+		t.Errorf("half reached: %+v", r)
+	}
+	wantPercentiles := []Percentile{{10, intPtr(2)}, {25, intPtr(5)}, {50, intPtr(10)}, {75, nil}, {90, nil}}
+	if !reflect.DeepEqual(r.Percentiles, wantPercentiles) {
+		t.Errorf("percentiles = %v, want %v", r.Percentiles, wantPercentiles)
+	}
+	if len(r.Histogram) != 10 || r.Histogram[0] != (Bucket{1, 1, 1, 5, 5}) || r.Histogram[9] != (Bucket{10, 10, 1, 5, 50}) {
+		t.Errorf("histogram = %+v, want 10 one-game buckets of 5%%", r.Histogram)
+	}
+
+	// 全部到達：百分位數取第 ceil(runs*p/100) 次，空格也列出
+	r = summarize([]int{3, 5, 5, 8}, 4, 10)
+	wantPercentiles = []Percentile{{10, intPtr(3)}, {25, intPtr(3)}, {50, intPtr(5)}, {75, intPtr(5)}, {90, intPtr(8)}}
+	if !reflect.DeepEqual(r.Percentiles, wantPercentiles) || *r.Mean != 5.25 {
+		t.Errorf("percentiles = %v, mean = %v; want %v, mean 5.25", r.Percentiles, *r.Mean, wantPercentiles)
+	}
+	wantHistogram := []Bucket{
+		{3, 3, 1, 25, 25},
+		{4, 4, 0, 0, 25},
+		{5, 5, 2, 50, 75},
+		{6, 6, 0, 0, 75},
+		{7, 7, 0, 0, 75},
+		{8, 8, 1, 25, 100},
+	}
+	if !reflect.DeepEqual(r.Histogram, wantHistogram) {
+		t.Errorf("histogram = %+v, want %+v", r.Histogram, wantHistogram)
+	}
+
+	// 跨度大於 20 格時每格涵蓋多個場數
+	r = summarize([]int{10, 50, 109}, 3, 200)
+	if len(r.Histogram) != 20 || r.Histogram[0].To != 14 || r.Histogram[19].From != 105 || r.Histogram[19].CumulativePct != 100 {
+		t.Errorf("wide histogram = %+v, want 20 buckets of 5 games", r.Histogram)
+	}
+}
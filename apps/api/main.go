@@ -1,33 +1,45 @@
 package main
 
 import (
-	"database/sql"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	_ "time/tzdata" // 使用者 / 遊戲的時區；容器映像可能沒有 zoneinfo
 
-	_ "github.com/glebarez/go-sqlite"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/harvc/duellog/apps/api/auth"
 	"github.com/harvc/duellog/apps/api/handlers"
+	"github.com/harvc/duellog/apps/api/migrate"
+	"github.com/harvc/duellog/apps/api/migrations"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo/sqlrepo"
+	"github.com/harvc/duellog/apps/api/storage"
+	"github.com/harvc/duellog/apps/api/web"
 	"github.com/joho/godotenv"
 )
 
-var db *sql.DB
+var db *storage.DB
 
 func main() {
+	webDir := flag.String("web-dir", os.Getenv("WEB_DIR"), "serve the frontend from this directory instead of the embedded build (development)")
+	flag.Parse()
+
 	// 載入 .env 檔案
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	// 初始化 SQLite 資料庫
+	// 初始化資料庫：設定 DATABASE_URL（postgres://...）時使用 PostgreSQL，否則使用 DB_PATH 的 SQLite 檔案
 	var err error
-	dbPath := getEnv("DB_PATH", "./duellog.db")
-	db, err = sql.Open("sqlite", dbPath)
+	db, err = storage.Open(getEnv("DATABASE_URL", ""), getEnv("DB_PATH", "./duellog.db"))
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -37,9 +49,9 @@ func main() {
 	if err := db.Ping(); err != nil {
 		log.Fatal("Failed to ping database:", err)
 	}
-	log.Println("✓ Database connected successfully")
+	log.Printf("✓ Database connected successfully (%s)", db.Dialect)
 
-	// Ensure schema is up-to-date for local SQLite files.
+	// Ensure schema is up-to-date.
 	if err := ensureSchema(db); err != nil {
 		log.Fatal("Failed to ensure schema:", err)
 	}
@@ -66,33 +78,36 @@ func main() {
 	app.Use(logger.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: getEnv("CORS_ORIGINS", "http://localhost:5173"),
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
 	}))
 
 	// Routes
 	app.Get("/health", healthHandler)
 
-	// Matches API
-	matchesHandler := handlers.NewMatchesHandler(db)
-	app.Get("/matches", matchesHandler.GetMatches)
-	app.Post("/matches", matchesHandler.CreateMatch)
-	app.Patch("/matches/:id", matchesHandler.UpdateMatch)
-	app.Delete("/matches/:id", matchesHandler.DeleteMatch)
-
-	// Deck Templates API
-	app.Get("/deck-templates", func(c *fiber.Ctx) error { return handlers.GetDeckTemplates(c, db) })
-	app.Post("/deck-templates", func(c *fiber.Ctx) error { return handlers.CreateDeckTemplate(c, db) })
-	app.Patch("/deck-templates/:id", func(c *fiber.Ctx) error { return handlers.UpdateDeckTemplate(c, db) })
-	app.Delete("/deck-templates/:id", func(c *fiber.Ctx) error { return handlers.DeleteDeckTemplate(c, db) })
+	// Auth：JWT_SECRET 未設定時每次啟動產生隨機密鑰（重新啟動後需重新登入）；
+	// AUTH_REQUIRED=true 時未登入的請求一律 401，否則視為預設使用者（MVP 單人模式）
+	tokens, err := newTokens()
+	if err != nil {
+		log.Fatal("Failed to initialise auth:", err)
+	}
+	// 遊戲、賽季等共用資料只有預設使用者或 ADMIN_EMAILS（逗號分隔）中的使用者可以修改
+	handlers.Register(app, handlers.Deps{
+		Store:        sqlrepo.New(db),
+		DB:           db,
+		Tokens:       tokens,
+		AuthRequired: envBool("AUTH_REQUIRED", false),
+		AdminEmails:  strings.Split(os.Getenv("ADMIN_EMAILS"), ","),
+	})
 
 	// Serve Static Files (Frontend)
-	// 假設前端構建後的檔案在 ../web/dist
-	app.Static("/", "../web/dist")
-
 	// SPA Fallback: 任何未匹配的路由都導向 index.html
-	app.Get("/*", func(c *fiber.Ctx) error {
-		return c.SendFile("./web/dist/index.html")
-	})
+	if root, ok := frontendFS(*webDir); ok {
+		app.Use("/", filesystem.New(filesystem.Config{
+			Root:         root,
+			Index:        "index.html",
+			NotFoundFile: "index.html",
+		}))
+	}
 
 	// 啟動伺服器
 	port := getEnv("PORT", "8080")
@@ -118,11 +133,35 @@ func getEnv(key, fallback string) string {
 }
 
 func shouldAutoSeed() bool {
-	val := strings.TrimSpace(strings.ToLower(getEnv("AUTO_SEED", "true")))
-	return !(val == "0" || val == "false" || val == "no" || val == "off")
+	return envBool("AUTO_SEED", true)
 }
 
-func needsSeed(db *sql.DB) (bool, error) {
+// envBool 讀取布林環境變數（0/false/no/off 為 false，1/true/yes/on 為 true），未設定或無法辨識時回傳 fallback
+func envBool(key string, fallback bool) bool {
+	switch strings.TrimSpace(strings.ToLower(os.Getenv(key))) {
+	case "0", "false", "no", "off":
+		return false
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return fallback
+	}
+}
+
+// newTokens 依 JWT_SECRET / JWT_TTL（Go duration，預設 168h）建立 token 簽發器
+func newTokens() (*auth.Tokens, error) {
+	ttl, err := time.ParseDuration(getEnv("JWT_TTL", "168h"))
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid JWT_TTL %q", os.Getenv("JWT_TTL"))
+	}
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		log.Println("⚠️  JWT_SECRET not set; using a random secret (tokens are invalidated on restart)")
+	}
+	return auth.NewTokens(secret, ttl)
+}
+
+func needsSeed(db *storage.DB) (bool, error) {
 	// Seed is considered needed if any of the essential base data is missing.
 	// We use these markers:
 	// - default user exists
@@ -133,7 +172,7 @@ func needsSeed(db *sql.DB) (bool, error) {
 		return false, err
 	}
 	var gameCount int
-	if err := db.QueryRow("SELECT COUNT(*) FROM games WHERE key = ?", "master_duel").Scan(&gameCount); err != nil {
+	if err := db.QueryRow("SELECT COUNT(*) FROM games WHERE key = ?", models.DefaultGameKey).Scan(&gameCount); err != nil {
 		return false, err
 	}
 	var tplCount int
@@ -144,11 +183,7 @@ func needsSeed(db *sql.DB) (bool, error) {
 	return userCount == 0 || gameCount == 0 || tplCount == 0, nil
 }
 
-func applySeed(db *sql.DB) error {
-	seedSQL, err := readSeedFile("seed.sql")
-	if err != nil {
-		return err
-	}
+func applySeed(db *storage.DB) error {
 	if _, err := db.Exec(seedSQL); err != nil {
 		return err
 	}
@@ -156,173 +191,62 @@ func applySeed(db *sql.DB) error {
 	return nil
 }
 
-func readSeedFile(filename string) (string, error) {
-	// Try common working directories:
-	// - when running from apps/api: ./seed.sql
-	// - when running from repo root: ./apps/api/seed.sql
-	candidates := []string{
-		filename,
-		filepath.Join("apps", "api", filename),
-	}
-	var lastErr error
-	for _, p := range candidates {
-		b, err := os.ReadFile(p)
-		if err == nil {
-			return string(b), nil
-		}
-		lastErr = err
-	}
-	return "", fmt.Errorf("read seed %s: %w", filename, lastErr)
-}
-
-func ensureSchema(db *sql.DB) error {
-	// Ensure base tables exist for a fresh DB.
-	exists, err := tableExists(db, "matches")
+func ensureSchema(db *storage.DB) error {
+	set, err := loadMigrations(db.Dialect)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		log.Println("ℹ️  Database is empty (no tables yet); applying base schema migrations...")
-		if err := applyBaseMigrations(db); err != nil {
-			return fmt.Errorf("apply base migrations: %w", err)
-		}
-		log.Println("✓ Base schema is ready")
-	}
+	m := migrate.New(db, set)
 
-	// Add matches.mode if missing (older DBs).
-	cols, err := getTableColumns(db, "matches")
+	// Databases created before schema_migrations existed: record what is already there.
+	adopted, err := m.AdoptLegacy()
 	if err != nil {
-		return err
+		return fmt.Errorf("adopt legacy schema: %w", err)
 	}
-	if _, ok := cols["mode"]; !ok {
-		if _, err := db.Exec("ALTER TABLE matches ADD COLUMN mode TEXT NOT NULL DEFAULT 'Ranked' CHECK (mode IN ('Ranked','Rating','DC'))"); err != nil {
-			return fmt.Errorf("add matches.mode: %w", err)
-		}
-		if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_matches_mode ON matches(mode)"); err != nil {
-			return fmt.Errorf("create idx_matches_mode: %w", err)
-		}
-		log.Println("✓ Applied runtime migration: matches.mode")
+	if adopted {
+		log.Println("ℹ️  Existing database detected; recorded its schema version in schema_migrations")
 	}
 
-	return nil
-}
-
-func tableExists(db *sql.DB, table string) (bool, error) {
-	var name string
-	err := db.QueryRow(
-		"SELECT name FROM sqlite_master WHERE type='table' AND name = ? LIMIT 1",
-		table,
-	).Scan(&name)
-	if err == sql.ErrNoRows {
-		return false, nil
-	}
-	if err != nil {
-		return false, err
-	}
-	return strings.EqualFold(name, table), nil
-}
-
-func applyBaseMigrations(db *sql.DB) error {
-	// These migrations create the initial schema + deck_templates.
-	// We keep this lightweight so a new user can simply run `go run .`.
-	migrationFiles := []string{
-		"001_create_schema.sql",
-		"002_add_deck_theme.sql",
-		"003_add_match_mode.sql",
-	}
-
-	tx, err := db.Begin()
+	applied, err := m.Up()
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
-
-	for _, f := range migrationFiles {
-		contents, err := readMigrationFile(f)
-		if err != nil {
-			return err
-		}
-		upSQL := extractGooseUpSQL(contents)
-		if strings.TrimSpace(upSQL) == "" {
-			continue
-		}
-		if _, err := tx.Exec(upSQL); err != nil {
-			return fmt.Errorf("exec %s: %w", f, err)
-		}
+	for _, mig := range applied {
+		log.Printf("✓ Applied migration %s_%s", mig.Version, mig.Name)
 	}
 
-	return tx.Commit()
-}
-
-func extractGooseUpSQL(fileContents string) string {
-	// If this is a Goose migration file, execute ONLY the Up section.
-	// Running the whole file would also execute Down statements, which can drop tables.
-	if !strings.Contains(fileContents, "+goose") {
-		return fileContents
+	// Ranks that 010_structured_rank could not convert are kept aside until someone maps them.
+	var unmapped int
+	if err := db.QueryRow("SELECT COUNT(*) FROM unmapped_ranks").Scan(&unmapped); err == nil && unmapped > 0 {
+		log.Printf("⚠️  %d matches have a rank that could not be converted; review them with `go run ./cmd/fix-ranks`", unmapped)
 	}
-
-	lines := strings.Split(fileContents, "\n")
-	inUp := false
-	out := make([]string, 0, len(lines))
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "-- +goose Up") {
-			inUp = true
-			continue
-		}
-		if strings.HasPrefix(trimmed, "-- +goose Down") {
-			break
-		}
-		if !inUp {
-			continue
-		}
-		// Skip Goose directives; keep everything else (including SQL and comments).
-		if strings.HasPrefix(trimmed, "-- +goose") {
-			continue
-		}
-		out = append(out, line)
-	}
-	return strings.Join(out, "\n")
-}
-
-func readMigrationFile(filename string) (string, error) {
-	// Try common working directories:
-	// - when running from apps/api: ./migrations/<file>
-	// - when running from repo root: ./apps/api/migrations/<file>
-	candidates := []string{
-		filepath.Join("migrations", filename),
-		filepath.Join("apps", "api", "migrations", filename),
-	}
-	var lastErr error
-	for _, p := range candidates {
-		b, err := os.ReadFile(p)
-		if err == nil {
-			return string(b), nil
-		}
-		lastErr = err
-	}
-	return "", fmt.Errorf("read migration %s: %w", filename, lastErr)
+	return nil
 }
 
-func getTableColumns(db *sql.DB, table string) (map[string]struct{}, error) {
-	rows, err := db.Query("PRAGMA table_info(" + table + ")")
+// loadMigrations returns the embedded migrations written for the given dialect.
+func loadMigrations(dialect storage.Dialect) ([]migrate.Migration, error) {
+	source, err := migrations.For(dialect)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("load embedded migrations: %w", err)
 	}
-	defer rows.Close()
+	return migrate.Load(source)
+}
 
-	cols := map[string]struct{}{}
-	for rows.Next() {
-		var cid int
-		var name string
-		var ctype string
-		var notnull int
-		var dflt sql.NullString
-		var pk int
-		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &pk); err != nil {
-			return nil, err
+// frontendFS returns the SPA files to serve: webDir on disk when set (development),
+// otherwise the build embedded into the binary.
+func frontendFS(webDir string) (http.FileSystem, bool) {
+	if webDir != "" {
+		if _, err := os.Stat(filepath.Join(webDir, "index.html")); err != nil {
+			log.Printf("⚠️  -web-dir %s has no index.html; frontend disabled", webDir)
+			return nil, false
 		}
-		cols[name] = struct{}{}
+		log.Printf("ℹ️  Serving frontend from disk: %s", webDir)
+		return http.Dir(webDir), true
+	}
+	dist, ok := web.Dist()
+	if !ok {
+		log.Println("ℹ️  No embedded frontend (run `npm run build` in apps/web before `go build`); serving API only")
+		return nil, false
 	}
-	return cols, rows.Err()
+	return http.FS(dist), true
 }
@@ -0,0 +1,43 @@
+package migrate
+
+import "github.com/harvc/duellog/apps/api/storage"
+
+// legacyMarkers 在 schema_migrations 出現之前，各版本是否已套用的判斷依據
+var legacyMarkers = []struct {
+	version string
+	applied func(db *storage.DB) (bool, error)
+}{
+	{"001", func(db *storage.DB) (bool, error) { return db.TableExists("matches") }},
+	{"002", func(db *storage.DB) (bool, error) { return db.TableExists("deck_templates") }},
+	{"003", func(db *storage.DB) (bool, error) { return db.ColumnExists("matches", "mode") }},
+}
+
+// AdoptLegacy 接手 schema_migrations 出現前建立的資料庫：
+// 若尚未有任何版本記錄但 matches 已存在，依現有 schema 推斷哪些版本已套用並記錄下來，
+// 其餘版本留給 Up() 補上。回傳是否有接手動作。
+func (m *Migrator) AdoptLegacy() (bool, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return false, err
+	}
+	if len(applied) > 0 {
+		return false, nil
+	}
+	if exists, err := m.db.TableExists("matches"); err != nil || !exists {
+		return false, err
+	}
+
+	for _, marker := range legacyMarkers {
+		ok, err := marker.applied(m.db)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+		if err := m.MarkApplied(marker.version); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,276 @@
+// Package migrate 版本化的 schema migration 引擎。
+//
+// Migration 檔案放在 apps/api/migrations/<dialect>，檔名格式為 NNN_description.sql，
+// NNN 即版本號。檔案沿用 Goose 的 `-- +goose Up` / `-- +goose Down` 標記；
+// 已套用的版本記錄在 schema_migrations 表中。
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// Migration 單一版本的 migration
+type Migration struct {
+	Version string // e.g. "003"
+	Name    string // e.g. "add_match_mode"
+	Up      string
+	Down    string
+}
+
+// Status 單一版本的套用狀態
+type Status struct {
+	Version   string
+	Name      string
+	Applied   bool
+	AppliedAt string // 未套用時為空字串
+	Missing   bool   // 資料庫記錄已套用，但找不到對應的 migration 檔案
+}
+
+const createVersionTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)
+`
+
+// Load 從 fsys 讀取所有 *.sql migration，依版本號排序
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	seen := map[string]string{}
+	for _, file := range files {
+		version, name, ok := parseFilename(path.Base(file))
+		if !ok {
+			return nil, fmt.Errorf("migration 檔名格式錯誤（需為 NNN_name.sql）: %s", file)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migration 版本重複: %s 與 %s", other, file)
+		}
+		seen[version] = file
+
+		contents, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", file, err)
+		}
+		up, down := ParseGoose(string(contents))
+		migrations = append(migrations, Migration{Version: version, Name: name, Up: up, Down: down})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseFilename 將 "003_add_match_mode.sql" 拆成 ("003", "add_match_mode")
+func parseFilename(filename string) (string, string, bool) {
+	base := strings.TrimSuffix(filename, ".sql")
+	version, name, ok := strings.Cut(base, "_")
+	if !ok || version == "" {
+		return "", "", false
+	}
+	for _, r := range version {
+		if r < '0' || r > '9' {
+			return "", "", false
+		}
+	}
+	return version, name, true
+}
+
+// ParseGoose 拆出 Goose migration 的 Up 與 Down 區段。
+// 非 Goose 格式的檔案整份視為 Up，Down 為空。
+func ParseGoose(fileContents string) (up string, down string) {
+	if !strings.Contains(fileContents, "+goose") {
+		return fileContents, ""
+	}
+
+	var upLines, downLines []string
+	var current *[]string
+	for _, line := range strings.Split(fileContents, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +goose Up"):
+			current = &upLines
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose Down"):
+			current = &downLines
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose"):
+			// Skip other Goose directives (StatementBegin/End ...); keep SQL and comments.
+			continue
+		}
+		if current != nil {
+			*current = append(*current, line)
+		}
+	}
+	return strings.Join(upLines, "\n"), strings.Join(downLines, "\n")
+}
+
+// Migrator 對單一資料庫套用 / 回滾 migration
+type Migrator struct {
+	db         *storage.DB
+	migrations []Migration
+}
+
+// New 建立 Migrator；migrations 需已依版本排序（Load 的回傳值即可）
+func New(db *storage.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Status 列出每個版本的套用狀態（含資料庫中有記錄但檔案已不存在的版本）
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := map[string]bool{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if rec, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = rec.appliedAt
+		}
+		statuses = append(statuses, s)
+	}
+	for version, rec := range applied {
+		if !known[version] {
+			statuses = append(statuses, Status{
+				Version: version, Name: rec.name, Applied: true, AppliedAt: rec.appliedAt, Missing: true,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Up 依序套用所有尚未套用的 migration，回傳本次套用的版本
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		if err := m.run(mig, mig.Up, true); err != nil {
+			return done, fmt.Errorf("apply %s_%s: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down 回滾最近套用的 steps 個 migration，回傳本次回滾的版本
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if err := m.run(mig, mig.Down, false); err != nil {
+			return done, fmt.Errorf("rollback %s_%s: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// MarkApplied 只記錄版本為已套用，不執行 SQL（用於接手既有資料庫）
+func (m *Migrator) MarkApplied(version string) error {
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			_, err := m.db.Exec(
+				"INSERT INTO schema_migrations (version, name) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				mig.Version, mig.Name,
+			)
+			return err
+		}
+	}
+	return fmt.Errorf("找不到 migration 版本 %s", version)
+}
+
+// run 在同一個 transaction 內執行 SQL 並更新 schema_migrations
+func (m *Migrator) run(mig Migration, statements string, up bool) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(statements) != "" {
+		if _, err := tx.Exec(statements); err != nil {
+			return err
+		}
+	}
+
+	if up {
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.Version, mig.Name)
+	} else {
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = ?", mig.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type appliedRecord struct {
+	name      string
+	appliedAt string
+}
+
+// applied 讀取已套用的版本
+func (m *Migrator) applied() (map[string]appliedRecord, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, name, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]appliedRecord{}
+	for rows.Next() {
+		var version, name string
+		var appliedAt sql.NullString
+		if err := rows.Scan(&version, &name, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedRecord{name: name, appliedAt: appliedAt.String}
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) ensureVersionTable() error {
+	_, err := m.db.Exec(createVersionTable)
+	return err
+}
@@ -0,0 +1,190 @@
+package migrate
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/harvc/duellog/apps/api/migrations"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+func openTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+	db, err := storage.Open("", filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func versions(migs []Migration) []string {
+	v := []string{}
+	for _, m := range migs {
+		v = append(v, m.Version)
+	}
+	return v
+}
+
+func TestParseGoose(t *testing.T) {
+	up, down := ParseGoose(`-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE t (id TEXT);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE t;
+`)
+	if up != "CREATE TABLE t (id TEXT);\n" || down != "DROP TABLE t;\n" {
+		t.Errorf("ParseGoose = %q / %q", up, down)
+	}
+
+	plain := "CREATE TABLE t (id TEXT);"
+	if up, down := ParseGoose(plain); up != plain || down != "" {
+		t.Errorf("plain SQL = %q / %q, want the whole file as up", up, down)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	migs, err := Load(fstest.MapFS{
+		"010_add_b.sql":   {Data: []byte("-- +goose Up\nSELECT 10;\n-- +goose Down\nSELECT -10;")},
+		"002_add_a.sql":   {Data: []byte("SELECT 2;")},
+		"notes.txt":       {Data: []byte("ignored")},
+		"001_init_db.sql": {Data: []byte("SELECT 1;")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(migs); !reflect.DeepEqual(got, []string{"001", "002", "010"}) {
+		t.Fatalf("versions = %v, want sorted 001, 002, 010", got)
+	}
+	if migs[0].Name != "init_db" || migs[2].Up != "SELECT 10;" || migs[2].Down != "SELECT -10;" {
+		t.Errorf("migrations = %+v", migs)
+	}
+
+	bad := []fstest.MapFS{
+		{"init.sql": {Data: []byte("SELECT 1;")}},
+		{"v1_init.sql": {Data: []byte("SELECT 1;")}},
+		{"001_a.sql": {Data: []byte("SELECT 1;")}, "001_b.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for _, fsys := range bad {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("Load accepted %v", fsys)
+		}
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	db := openTestDB(t)
+	migs, err := Load(fstest.MapFS{
+		"001_create_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id TEXT);\n-- +goose Down\nDROP TABLE a;")},
+		"002_create_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id TEXT);\n-- +goose Down\nDROP TABLE b;")},
+		"003_bad.sql":      {Data: []byte("-- +goose Up\nCREATE TABLE c (id TEXT);\nNOT SQL;\n-- +goose Down\nDROP TABLE c;")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(db, migs)
+
+	// 003 失敗：前兩個仍套用，003 整批回滾
+	done, err := m.Up()
+	if err == nil || !reflect.DeepEqual(versions(done), []string{"001", "002"}) {
+		t.Fatalf("Up = %v, %v; want 001, 002 then an error", versions(done), err)
+	}
+	if exists, _ := db.TableExists("c"); exists {
+		t.Error("failed migration 003 left table c behind")
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := map[string]bool{}
+	for _, s := range status {
+		applied[s.Version] = s.Applied
+	}
+	if !applied["001"] || !applied["002"] || applied["003"] {
+		t.Errorf("status = %+v, want 001 and 002 applied", status)
+	}
+
+	// 修正 003 後重新執行只套用 003；再執行一次不做任何事
+	migs[2].Up = "CREATE TABLE c (id TEXT);"
+	if done, err := m.Up(); err != nil || !reflect.DeepEqual(versions(done), []string{"003"}) {
+		t.Fatalf("second Up = %v, %v; want 003", versions(done), err)
+	}
+	if done, err := m.Up(); err != nil || len(done) != 0 {
+		t.Errorf("Up with nothing pending = %v, %v", versions(done), err)
+	}
+
+	// Down 由新到舊回滾
+	if done, err := m.Down(2); err != nil || !reflect.DeepEqual(versions(done), []string{"003", "002"}) {
+		t.Fatalf("Down(2) = %v, %v; want 003, 002", versions(done), err)
+	}
+	for table, want := range map[string]bool{"a": true, "b": false, "c": false} {
+		if exists, _ := db.TableExists(table); exists != want {
+			t.Errorf("after Down(2) table %s exists = %v, want %v", table, exists, want)
+		}
+	}
+
+	// 檔案已刪除的版本仍列在 status，標記 Missing
+	if status, err := New(db, migs[1:]).Status(); err != nil || !status[0].Missing || status[0].Version != "001" {
+		t.Errorf("status without 001 = %+v, %v; want 001 missing", status, err)
+	}
+}
+
+func TestRealMigrationsRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	source, err := migrations.For(db.Dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migs, err := Load(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(db, migs)
+
+	if done, err := m.Up(); err != nil || len(done) != len(migs) {
+		t.Fatalf("Up = %d migrations, %v; want %d", len(done), err, len(migs))
+	}
+	if done, err := m.Down(len(migs)); err != nil || len(done) != len(migs) {
+		t.Fatalf("Down = %d migrations, %v; want %d", len(done), err, len(migs))
+	}
+	if exists, _ := db.TableExists("matches"); exists {
+		t.Error("matches still exists after rolling back every migration")
+	}
+	if done, err := m.Up(); err != nil || len(done) != len(migs) {
+		t.Fatalf("Up after Down = %d migrations, %v", len(done), err)
+	}
+}
+
+func TestAdoptLegacy(t *testing.T) {
+	db := openTestDB(t)
+	source, err := migrations.For(db.Dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migs, err := Load(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// schema_migrations 出現前的資料庫：只執行過 001 與 002
+	for _, mig := range migs[:2] {
+		if _, err := db.Exec(mig.Up); err != nil {
+			t.Fatalf("%s: %v", mig.Version, err)
+		}
+	}
+	m := New(db, migs)
+	if adopted, err := m.AdoptLegacy(); err != nil || !adopted {
+		t.Fatalf("AdoptLegacy = %v, %v; want adopted", adopted, err)
+	}
+	if done, err := m.Up(); err != nil || len(done) != len(migs)-2 || done[0].Version != "003" {
+		t.Fatalf("Up after adopting = %v, %v; want 003 onwards", versions(done), err)
+	}
+	if adopted, err := m.AdoptLegacy(); err != nil || adopted {
+		t.Errorf("second AdoptLegacy = %v, %v; want no-op", adopted, err)
+	}
+}
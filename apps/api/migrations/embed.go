@@ -0,0 +1,19 @@
+// Package migrations 內嵌 schema migration 檔案，讓編譯後的執行檔不依賴工作目錄。
+//
+// 每種資料庫各有一組 migration（sqlite/、postgres/），版本號需保持一致。
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+//go:embed sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+// For 回傳指定 Dialect 的 migration 檔案
+func For(dialect storage.Dialect) (fs.FS, error) {
+	return fs.Sub(files, string(dialect))
+}
@@ -14,7 +14,7 @@ CREATE INDEX IF NOT EXISTS idx_matches_mode ON matches(mode);
 -- +goose Down
 -- +goose StatementBegin
 
--- SQLite can't DROP COLUMN easily; keep as no-op.
 DROP INDEX IF EXISTS idx_matches_mode;
+ALTER TABLE matches DROP COLUMN mode;
 
 -- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 遊戲帳號：同一個使用者可以有多個遊戲帳號（例如多個 Master Duel 帳號）
+CREATE TABLE accounts (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    UNIQUE(user_id, game_id, name)
+);
+
+-- 對局所屬的帳號（可選；舊資料為 NULL）
+ALTER TABLE matches ADD COLUMN account_id TEXT REFERENCES accounts(id);
+
+CREATE INDEX IF NOT EXISTS idx_matches_account_id ON matches(account_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_matches_account_id;
+ALTER TABLE matches DROP COLUMN account_id;
+DROP TABLE IF EXISTS accounts;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- mode / play_order 的可用值改由 Go 的 rules 套件依遊戲決定（見 GET /games/:key/rules），
+-- 移除寫死 Master Duel 值的 CHECK。
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_mode_check;
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_play_order_check;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- 若已有其他遊戲的對局（mode / play_order 不在下列值中），需先處理後才能回滾。
+ALTER TABLE matches ADD CONSTRAINT matches_mode_check CHECK (mode IN ('Ranked', 'Rating', 'DC'));
+ALTER TABLE matches ADD CONSTRAINT matches_play_order_check CHECK (play_order IN ('先攻', '後攻'));
+
+-- +goose StatementEnd
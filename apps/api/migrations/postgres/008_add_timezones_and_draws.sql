@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 使用者時區：對局的 date 以使用者當地的日期記錄，每日統計依此分組
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- 各遊戲的賽季切換時間（在 season_timezone 當地的每月 1 日 HH:MM 切換）
+ALTER TABLE games ADD COLUMN season_reset_time TEXT NOT NULL DEFAULT '00:00';
+ALTER TABLE games ADD COLUMN season_timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- 對局時間（可選；舊資料為 NULL）
+ALTER TABLE matches ADD COLUMN played_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_matches_played_at ON matches(played_at);
+
+-- result 新增 "D"（平手）
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_result_check;
+ALTER TABLE matches ADD CONSTRAINT matches_result_check CHECK (result IN ('W', 'L', 'D'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- 若已有平手（result = 'D'）的對局，需先處理後才能回滾。
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_result_check;
+ALTER TABLE matches ADD CONSTRAINT matches_result_check CHECK (result IN ('W', 'L'));
+
+DROP INDEX IF EXISTS idx_matches_played_at;
+ALTER TABLE matches DROP COLUMN played_at;
+ALTER TABLE games DROP COLUMN season_timezone;
+ALTER TABLE games DROP COLUMN season_reset_time;
+ALTER TABLE users DROP COLUMN timezone;
+
+-- +goose StatementEnd
@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 先後攻、階級改存與語系無關的代碼（顯示名稱由 rules 套件依語系提供）
+UPDATE matches SET play_order = 'first' WHERE play_order = '先攻';
+UPDATE matches SET play_order = 'second' WHERE play_order = '後攻';
+
+-- Master Duel 的階級："金 IV" / "金IV" → "gold-4"
+UPDATE matches SET rank = 'bronze-5' WHERE REPLACE(rank, ' ', '') = '銅V' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'bronze-4' WHERE REPLACE(rank, ' ', '') = '銅IV' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'bronze-3' WHERE REPLACE(rank, ' ', '') = '銅III' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'bronze-2' WHERE REPLACE(rank, ' ', '') = '銅II' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'bronze-1' WHERE REPLACE(rank, ' ', '') = '銅I' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'silver-5' WHERE REPLACE(rank, ' ', '') = '銀V' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'silver-4' WHERE REPLACE(rank, ' ', '') = '銀IV' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'silver-3' WHERE REPLACE(rank, ' ', '') = '銀III' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'silver-2' WHERE REPLACE(rank, ' ', '') = '銀II' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'silver-1' WHERE REPLACE(rank, ' ', '') = '銀I' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'gold-5' WHERE REPLACE(rank, ' ', '') = '金V' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'gold-4' WHERE REPLACE(rank, ' ', '') = '金IV' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'gold-3' WHERE REPLACE(rank, ' ', '') = '金III' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'gold-2' WHERE REPLACE(rank, ' ', '') = '金II' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'gold-1' WHERE REPLACE(rank, ' ', '') = '金I' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'platinum-5' WHERE REPLACE(rank, ' ', '') = '白金V' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'platinum-4' WHERE REPLACE(rank, ' ', '') = '白金IV' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'platinum-3' WHERE REPLACE(rank, ' ', '') = '白金III' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'platinum-2' WHERE REPLACE(rank, ' ', '') = '白金II' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'platinum-1' WHERE REPLACE(rank, ' ', '') = '白金I' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'diamond-5' WHERE REPLACE(rank, ' ', '') = '鑽石V' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'diamond-4' WHERE REPLACE(rank, ' ', '') = '鑽石IV' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'diamond-3' WHERE REPLACE(rank, ' ', '') = '鑽石III' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'diamond-2' WHERE REPLACE(rank, ' ', '') = '鑽石II' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'diamond-1' WHERE REPLACE(rank, ' ', '') = '鑽石I' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'master-5' WHERE REPLACE(rank, ' ', '') = '大師V' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'master-4' WHERE REPLACE(rank, ' ', '') = '大師IV' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'master-3' WHERE REPLACE(rank, ' ', '') = '大師III' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'master-2' WHERE REPLACE(rank, ' ', '') = '大師II' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = 'master-1' WHERE REPLACE(rank, ' ', '') = '大師I' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE matches SET play_order = '先攻' WHERE play_order = 'first';
+UPDATE matches SET play_order = '後攻' WHERE play_order = 'second';
+
+UPDATE matches SET rank = '銅 V' WHERE rank = 'bronze-5' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銅 IV' WHERE rank = 'bronze-4' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銅 III' WHERE rank = 'bronze-3' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銅 II' WHERE rank = 'bronze-2' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銅 I' WHERE rank = 'bronze-1' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銀 V' WHERE rank = 'silver-5' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銀 IV' WHERE rank = 'silver-4' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銀 III' WHERE rank = 'silver-3' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銀 II' WHERE rank = 'silver-2' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '銀 I' WHERE rank = 'silver-1' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '金 V' WHERE rank = 'gold-5' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '金 IV' WHERE rank = 'gold-4' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '金 III' WHERE rank = 'gold-3' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '金 II' WHERE rank = 'gold-2' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '金 I' WHERE rank = 'gold-1' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '白金 V' WHERE rank = 'platinum-5' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '白金 IV' WHERE rank = 'platinum-4' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '白金 III' WHERE rank = 'platinum-3' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '白金 II' WHERE rank = 'platinum-2' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '白金 I' WHERE rank = 'platinum-1' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '鑽石 V' WHERE rank = 'diamond-5' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '鑽石 IV' WHERE rank = 'diamond-4' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '鑽石 III' WHERE rank = 'diamond-3' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '鑽石 II' WHERE rank = 'diamond-2' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '鑽石 I' WHERE rank = 'diamond-1' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '大師 V' WHERE rank = 'master-5' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '大師 IV' WHERE rank = 'master-4' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '大師 III' WHERE rank = 'master-3' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '大師 II' WHERE rank = 'master-2' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank = '大師 I' WHERE rank = 'master-1' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- UNIQUE(game_id, main, sub) 不會擋下 sub 為 NULL 的重複牌組（NULL 彼此不相等），
+-- 同時新增對局時可能各自建立一筆。先把重複的牌組合併到 id 最小的那筆，再加上部分唯一索引。
+UPDATE matches SET my_deck_id = (
+    SELECT MIN(keep.id)
+    FROM decks d
+    JOIN decks keep ON keep.game_id = d.game_id AND keep.main = d.main AND keep.sub IS NULL
+    WHERE d.id = matches.my_deck_id
+)
+WHERE my_deck_id IN (SELECT id FROM decks WHERE sub IS NULL);
+
+UPDATE matches SET opp_deck_id = (
+    SELECT MIN(keep.id)
+    FROM decks d
+    JOIN decks keep ON keep.game_id = d.game_id AND keep.main = d.main AND keep.sub IS NULL
+    WHERE d.id = matches.opp_deck_id
+)
+WHERE opp_deck_id IN (SELECT id FROM decks WHERE sub IS NULL);
+
+DELETE FROM decks
+WHERE sub IS NULL
+  AND id NOT IN (SELECT MIN(id) FROM decks WHERE sub IS NULL GROUP BY game_id, main);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_decks_main_without_sub ON decks(game_id, main) WHERE sub IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_decks_main_without_sub;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 個人 API key（給腳本 / bot 使用）。只保存 SHA-256 雜湊，明碼只在建立時回傳一次。
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,               -- 明碼的前幾碼，讓使用者辨識是哪一把
+    key_hash TEXT UNIQUE NOT NULL,
+    scope TEXT NOT NULL DEFAULT 'read', -- read：只能查詢；write：可新增 / 修改 / 刪除
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CHECK (scope IN ('read', 'write'))
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS api_keys;
+
+-- +goose StatementEnd
@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 遊戲帳號：同一個使用者可以有多個遊戲帳號（例如多個 Master Duel 帳號）
+CREATE TABLE accounts (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    UNIQUE(user_id, game_id, name)
+);
+
+-- 對局所屬的帳號（可選；舊資料為 NULL）
+ALTER TABLE matches ADD COLUMN account_id TEXT REFERENCES accounts(id);
+
+CREATE INDEX IF NOT EXISTS idx_matches_account_id ON matches(account_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- account_id 有 REFERENCES，SQLite 無法 DROP COLUMN，因此重建 matches 表（與 007 / 008 相同做法，索引一併重建）。
+CREATE TABLE matches_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    season_id TEXT NOT NULL,
+    date DATE NOT NULL,
+    mode TEXT NOT NULL DEFAULT 'Ranked' CHECK (mode IN ('Ranked', 'Rating', 'DC')), -- 欄位層級的 CHECK：003 回滾時才能 DROP COLUMN
+    rank TEXT NOT NULL,
+    my_deck_id TEXT NOT NULL,
+    opp_deck_id TEXT NOT NULL,
+    play_order TEXT NOT NULL,
+    result TEXT NOT NULL,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (my_deck_id) REFERENCES decks(id),
+    FOREIGN KEY (opp_deck_id) REFERENCES decks(id),
+    CHECK (result IN ('W', 'L')),
+    CHECK (play_order IN ('先攻', '後攻'))
+);
+
+INSERT INTO matches_new (id, user_id, game_id, season_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at)
+SELECT id, user_id, game_id, season_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at
+FROM matches;
+
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+CREATE INDEX idx_matches_user_id ON matches(user_id);
+CREATE INDEX idx_matches_season_id ON matches(season_id);
+CREATE INDEX idx_matches_date ON matches(date);
+CREATE INDEX idx_matches_my_deck_id ON matches(my_deck_id);
+CREATE INDEX idx_matches_mode ON matches(mode);
+
+DROP TABLE IF EXISTS accounts;
+
+-- +goose StatementEnd
@@ -0,0 +1,96 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- mode / play_order 的可用值改由 Go 的 rules 套件依遊戲決定（見 GET /games/:key/rules），
+-- 移除寫死 Master Duel 值的 CHECK。SQLite 無法單獨移除 CHECK，因此重建 matches 表（索引一併重建）。
+CREATE TABLE matches_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    season_id TEXT NOT NULL,
+    account_id TEXT,
+    date DATE NOT NULL,
+    mode TEXT NOT NULL DEFAULT 'Ranked', -- 對局模式（可用值見 rules 套件）
+    rank TEXT NOT NULL,
+    my_deck_id TEXT NOT NULL,
+    opp_deck_id TEXT NOT NULL,
+    play_order TEXT NOT NULL,            -- 先後攻（可用值見 rules 套件）
+    result TEXT NOT NULL,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (account_id) REFERENCES accounts(id),
+    FOREIGN KEY (my_deck_id) REFERENCES decks(id),
+    FOREIGN KEY (opp_deck_id) REFERENCES decks(id),
+    CHECK (result IN ('W', 'L'))
+);
+
+INSERT INTO matches_new (id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at)
+SELECT id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at
+FROM matches;
+
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+CREATE INDEX idx_matches_user_id ON matches(user_id);
+CREATE INDEX idx_matches_season_id ON matches(season_id);
+CREATE INDEX idx_matches_date ON matches(date);
+CREATE INDEX idx_matches_my_deck_id ON matches(my_deck_id);
+CREATE INDEX idx_matches_mode ON matches(mode);
+CREATE INDEX idx_matches_account_id ON matches(account_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- 若已有其他遊戲的對局（mode / play_order 不在下列值中），需先處理後才能回滾。
+CREATE TABLE matches_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    season_id TEXT NOT NULL,
+    account_id TEXT,
+    date DATE NOT NULL,
+    mode TEXT NOT NULL DEFAULT 'Ranked',
+    rank TEXT NOT NULL,
+    my_deck_id TEXT NOT NULL,
+    opp_deck_id TEXT NOT NULL,
+    play_order TEXT NOT NULL,
+    result TEXT NOT NULL,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (account_id) REFERENCES accounts(id),
+    FOREIGN KEY (my_deck_id) REFERENCES decks(id),
+    FOREIGN KEY (opp_deck_id) REFERENCES decks(id),
+    CHECK (result IN ('W', 'L')),
+    CHECK (mode IN ('Ranked', 'Rating', 'DC')),
+    CHECK (play_order IN ('先攻', '後攻'))
+);
+
+INSERT INTO matches_new (id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at)
+SELECT id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at
+FROM matches;
+
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+CREATE INDEX idx_matches_user_id ON matches(user_id);
+CREATE INDEX idx_matches_season_id ON matches(season_id);
+CREATE INDEX idx_matches_date ON matches(date);
+CREATE INDEX idx_matches_my_deck_id ON matches(my_deck_id);
+CREATE INDEX idx_matches_mode ON matches(mode);
+CREATE INDEX idx_matches_account_id ON matches(account_id);
+
+-- +goose StatementEnd
@@ -0,0 +1,106 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 使用者時區：對局的 date 以使用者當地的日期記錄，每日統計依此分組
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- 各遊戲的賽季切換時間（在 season_timezone 當地的每月 1 日 HH:MM 切換）
+ALTER TABLE games ADD COLUMN season_reset_time TEXT NOT NULL DEFAULT '00:00';
+ALTER TABLE games ADD COLUMN season_timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- matches 新增 played_at，result 新增 "D"（平手）。SQLite 無法修改 CHECK，因此重建 matches 表（索引一併重建）。
+CREATE TABLE matches_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    season_id TEXT NOT NULL,
+    account_id TEXT,
+    date DATE NOT NULL,                  -- 使用者當地的對局日期
+    played_at DATETIME,                  -- 對局時間（可選；舊資料為 NULL）
+    mode TEXT NOT NULL DEFAULT 'Ranked', -- 對局模式（可用值見 rules 套件）
+    rank TEXT NOT NULL,
+    my_deck_id TEXT NOT NULL,
+    opp_deck_id TEXT NOT NULL,
+    play_order TEXT NOT NULL,            -- 先後攻（可用值見 rules 套件）
+    result TEXT NOT NULL,                -- "W" | "L" | "D"（平手）
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (account_id) REFERENCES accounts(id),
+    FOREIGN KEY (my_deck_id) REFERENCES decks(id),
+    FOREIGN KEY (opp_deck_id) REFERENCES decks(id),
+    CHECK (result IN ('W', 'L', 'D'))
+);
+
+INSERT INTO matches_new (id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at)
+SELECT id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at
+FROM matches;
+
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+CREATE INDEX idx_matches_user_id ON matches(user_id);
+CREATE INDEX idx_matches_season_id ON matches(season_id);
+CREATE INDEX idx_matches_date ON matches(date);
+CREATE INDEX idx_matches_my_deck_id ON matches(my_deck_id);
+CREATE INDEX idx_matches_mode ON matches(mode);
+CREATE INDEX idx_matches_account_id ON matches(account_id);
+CREATE INDEX idx_matches_played_at ON matches(played_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- 若已有平手（result = 'D'）的對局，需先處理後才能回滾。
+CREATE TABLE matches_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    season_id TEXT NOT NULL,
+    account_id TEXT,
+    date DATE NOT NULL,
+    mode TEXT NOT NULL DEFAULT 'Ranked', -- 對局模式（可用值見 rules 套件）
+    rank TEXT NOT NULL,
+    my_deck_id TEXT NOT NULL,
+    opp_deck_id TEXT NOT NULL,
+    play_order TEXT NOT NULL,            -- 先後攻（可用值見 rules 套件）
+    result TEXT NOT NULL,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (game_id) REFERENCES games(id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id),
+    FOREIGN KEY (account_id) REFERENCES accounts(id),
+    FOREIGN KEY (my_deck_id) REFERENCES decks(id),
+    FOREIGN KEY (opp_deck_id) REFERENCES decks(id),
+    CHECK (result IN ('W', 'L'))
+);
+
+INSERT INTO matches_new (id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at)
+SELECT id, user_id, game_id, season_id, account_id, date, mode, rank,
+    my_deck_id, opp_deck_id, play_order, result, note, created_at, updated_at
+FROM matches;
+
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+CREATE INDEX idx_matches_user_id ON matches(user_id);
+CREATE INDEX idx_matches_season_id ON matches(season_id);
+CREATE INDEX idx_matches_date ON matches(date);
+CREATE INDEX idx_matches_my_deck_id ON matches(my_deck_id);
+CREATE INDEX idx_matches_mode ON matches(mode);
+CREATE INDEX idx_matches_account_id ON matches(account_id);
+
+ALTER TABLE games DROP COLUMN season_timezone;
+ALTER TABLE games DROP COLUMN season_reset_time;
+ALTER TABLE users DROP COLUMN timezone;
+
+-- +goose StatementEnd
@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 階級改為結構化的 rank_tier / rank_level，不記錄階級的模式兩者皆為 NULL（取代佔位值 "—"）
+ALTER TABLE matches ADD COLUMN rank_tier TEXT;
+ALTER TABLE matches ADD COLUMN rank_level INTEGER;
+
+-- Master Duel：代碼 "gold-4" → (gold, 4)；009 之前的寫法（"金 IV" / "金IV"）一併轉換
+UPDATE matches SET rank_tier = 'bronze', rank_level = 5 WHERE (rank = 'bronze-5' OR REPLACE(rank, ' ', '') = '銅V') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'bronze', rank_level = 4 WHERE (rank = 'bronze-4' OR REPLACE(rank, ' ', '') = '銅IV') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'bronze', rank_level = 3 WHERE (rank = 'bronze-3' OR REPLACE(rank, ' ', '') = '銅III') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'bronze', rank_level = 2 WHERE (rank = 'bronze-2' OR REPLACE(rank, ' ', '') = '銅II') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'bronze', rank_level = 1 WHERE (rank = 'bronze-1' OR REPLACE(rank, ' ', '') = '銅I') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'silver', rank_level = 5 WHERE (rank = 'silver-5' OR REPLACE(rank, ' ', '') = '銀V') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'silver', rank_level = 4 WHERE (rank = 'silver-4' OR REPLACE(rank, ' ', '') = '銀IV') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'silver', rank_level = 3 WHERE (rank = 'silver-3' OR REPLACE(rank, ' ', '') = '銀III') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'silver', rank_level = 2 WHERE (rank = 'silver-2' OR REPLACE(rank, ' ', '') = '銀II') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'silver', rank_level = 1 WHERE (rank = 'silver-1' OR REPLACE(rank, ' ', '') = '銀I') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'gold', rank_level = 5 WHERE (rank = 'gold-5' OR REPLACE(rank, ' ', '') = '金V') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'gold', rank_level = 4 WHERE (rank = 'gold-4' OR REPLACE(rank, ' ', '') = '金IV') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'gold', rank_level = 3 WHERE (rank = 'gold-3' OR REPLACE(rank, ' ', '') = '金III') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'gold', rank_level = 2 WHERE (rank = 'gold-2' OR REPLACE(rank, ' ', '') = '金II') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'gold', rank_level = 1 WHERE (rank = 'gold-1' OR REPLACE(rank, ' ', '') = '金I') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'platinum', rank_level = 5 WHERE (rank = 'platinum-5' OR REPLACE(rank, ' ', '') = '白金V') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'platinum', rank_level = 4 WHERE (rank = 'platinum-4' OR REPLACE(rank, ' ', '') = '白金IV') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'platinum', rank_level = 3 WHERE (rank = 'platinum-3' OR REPLACE(rank, ' ', '') = '白金III') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'platinum', rank_level = 2 WHERE (rank = 'platinum-2' OR REPLACE(rank, ' ', '') = '白金II') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'platinum', rank_level = 1 WHERE (rank = 'platinum-1' OR REPLACE(rank, ' ', '') = '白金I') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'diamond', rank_level = 5 WHERE (rank = 'diamond-5' OR REPLACE(rank, ' ', '') = '鑽石V') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'diamond', rank_level = 4 WHERE (rank = 'diamond-4' OR REPLACE(rank, ' ', '') = '鑽石IV') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'diamond', rank_level = 3 WHERE (rank = 'diamond-3' OR REPLACE(rank, ' ', '') = '鑽石III') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'diamond', rank_level = 2 WHERE (rank = 'diamond-2' OR REPLACE(rank, ' ', '') = '鑽石II') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'diamond', rank_level = 1 WHERE (rank = 'diamond-1' OR REPLACE(rank, ' ', '') = '鑽石I') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'master', rank_level = 5 WHERE (rank = 'master-5' OR REPLACE(rank, ' ', '') = '大師V') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'master', rank_level = 4 WHERE (rank = 'master-4' OR REPLACE(rank, ' ', '') = '大師IV') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'master', rank_level = 3 WHERE (rank = 'master-3' OR REPLACE(rank, ' ', '') = '大師III') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'master', rank_level = 2 WHERE (rank = 'master-2' OR REPLACE(rank, ' ', '') = '大師II') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+UPDATE matches SET rank_tier = 'master', rank_level = 1 WHERE (rank = 'master-1' OR REPLACE(rank, ' ', '') = '大師I') AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+
+-- 其他遊戲沒有階級制度：照原樣記錄在 rank_tier
+UPDATE matches SET rank_tier = rank WHERE rank NOT IN ('', '—') AND game_id NOT IN (SELECT id FROM games WHERE key = 'master_duel');
+
+-- 無法轉換的階級保留在 unmapped_ranks（啟動時會提示筆數），由 cmd/fix-ranks 列出與補上
+CREATE TABLE unmapped_ranks (
+    match_id TEXT PRIMARY KEY,
+    rank TEXT NOT NULL
+);
+INSERT INTO unmapped_ranks (match_id, rank)
+SELECT id, rank FROM matches
+WHERE rank_tier IS NULL AND rank NOT IN ('', '—') AND mode = 'Ranked' AND game_id IN (SELECT id FROM games WHERE key = 'master_duel');
+
+ALTER TABLE matches DROP COLUMN rank;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE matches ADD COLUMN rank TEXT NOT NULL DEFAULT '—';
+UPDATE matches SET rank = rank_tier || '-' || rank_level WHERE rank_tier IS NOT NULL AND rank_level IS NOT NULL;
+UPDATE matches SET rank = rank_tier WHERE rank_tier IS NOT NULL AND rank_level IS NULL;
+UPDATE matches SET rank = (SELECT u.rank FROM unmapped_ranks u WHERE u.match_id = matches.id)
+WHERE id IN (SELECT match_id FROM unmapped_ranks);
+DROP TABLE unmapped_ranks;
+ALTER TABLE matches DROP COLUMN rank_level;
+ALTER TABLE matches DROP COLUMN rank_tier;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 積分（Rating / DC 等記錄積分的模式）：對局前後的積分與增減，皆為可選；不記錄積分的模式三者皆為 NULL
+ALTER TABLE matches ADD COLUMN rating_before INTEGER;
+ALTER TABLE matches ADD COLUMN rating_after INTEGER;
+ALTER TABLE matches ADD COLUMN rating_delta INTEGER;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE matches DROP COLUMN rating_delta;
+ALTER TABLE matches DROP COLUMN rating_after;
+ALTER TABLE matches DROP COLUMN rating_before;
+
+-- +goose StatementEnd
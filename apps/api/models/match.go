@@ -1,40 +1,127 @@
 package models
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultGameKey 請求沒有指定 gameKey 時使用的遊戲
+const DefaultGameKey = "master_duel"
+
+// DefaultTimezone 使用者 / 遊戲沒有設定時區時使用的時區
+const DefaultTimezone = "UTC"
+
+// DefaultSeasonResetTime 遊戲沒有設定時，賽季在每月 1 日 00:00 切換
+const DefaultSeasonResetTime = "00:00"
 
 // Match 對局記錄
 type Match struct {
-	ID         string    `json:"id"`
-	UserID     string    `json:"userId"`
-	GameID     string    `json:"gameId"`
-	SeasonID   string    `json:"seasonId"`
-	Date       string    `json:"date"`        // ISO format: YYYY-MM-DD
-	Mode       string    `json:"mode"`        // "Ranked" | "Rating" | "DC"
-	Rank       string    `json:"rank"`        // e.g. "金IV", "鑽石I"
-	MyDeckID   string    `json:"myDeckId"`    // 我的牌組 ID
-	OppDeckID  string    `json:"oppDeckId"`   // 對手牌組 ID
-	PlayOrder  string    `json:"playOrder"`   // "先攻" 或 "後攻"
-	Result     string    `json:"result"`      // "W" 或 "L"
-	Note       *string   `json:"note"`        // 備註（可選）
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID        string     `json:"id"`
+	UserID    string     `json:"userId"`
+	GameID    string     `json:"gameId"`
+	SeasonID  string     `json:"seasonId"`
+	AccountID *string    `json:"accountId"` // 遊戲帳號 ID（可選）
+	Date      string     `json:"date"`      // 使用者當地的日期，ISO format: YYYY-MM-DD
+	PlayedAt  *time.Time `json:"playedAt"`  // 對局時間（可選；舊資料為 null）
+	Mode      string     `json:"mode"`      // 依遊戲規則，e.g. "Ranked" | "Rating" | "DC"
+	Rank      *Rank      `json:"rank"`      // 階級；不記錄階級的模式為 nil
+	Rating    *Rating    `json:"rating"`    // 積分；不記錄積分或沒有填時為 nil
+	MyDeckID  string     `json:"myDeckId"`  // 我的牌組 ID
+	OppDeckID string     `json:"oppDeckId"` // 對手牌組 ID
+	PlayOrder string     `json:"playOrder"` // "first" 或 "second"
+	Result    string     `json:"result"`    // "W" | "L" | "D"
+	Note      *string    `json:"note"`      // 備註（可選）
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
 }
 
 // MatchWithDetails 對局記錄（含完整資訊）
 // 用於 GET /matches，包含 deck 名稱等關聯資料
 type MatchWithDetails struct {
-	ID         string    `json:"id"`
-	Date       string    `json:"date"`
-	Mode       string    `json:"mode"`
-	Rank       string    `json:"rank"`
-	MyDeck     DeckInfo  `json:"myDeck"`      // 我的牌組詳細資訊
-	OppDeck    DeckInfo  `json:"oppDeck"`     // 對手牌組詳細資訊
-	PlayOrder  string    `json:"playOrder"`   // "先攻" 或 "後攻"
-	Result     string    `json:"result"`      // "W" 或 "L"
-	Note       *string   `json:"note"`
-	SeasonCode string    `json:"seasonCode"`  // e.g. "S48"
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID         string       `json:"id"`
+	GameID     string       `json:"-"`
+	GameKey    string       `json:"-"`
+	Date       string       `json:"date"`
+	PlayedAt   *time.Time   `json:"playedAt"`
+	Mode       string       `json:"mode"`
+	Rank       *string      `json:"rank"`      // 階級代碼，e.g. "gold-4"；不記錄階級的模式為 null
+	RankTier   *string      `json:"rankTier"`  // e.g. "gold"
+	RankLevel  *int         `json:"rankLevel"` // e.g. 4（= IV）
+	Rating     *Rating      `json:"rating"`    // 積分（Rating / DC 等模式）；沒有記錄時為 null
+	MyDeck     DeckInfo     `json:"myDeck"`    // 我的牌組詳細資訊
+	OppDeck    DeckInfo     `json:"oppDeck"`   // 對手牌組詳細資訊
+	PlayOrder  string       `json:"playOrder"` // "first" 或 "second"
+	Result     string       `json:"result"`    // "W" | "L" | "D"
+	Note       *string      `json:"note"`
+	SeasonCode string       `json:"seasonCode"` // e.g. "S48"
+	Account    *AccountInfo `json:"account"`    // 遊戲帳號（可能為 null）
+	Labels     MatchLabels  `json:"labels"`     // mode / rank / playOrder / result 的顯示名稱（依請求的語系）
+	CreatedAt  time.Time    `json:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
+}
+
+// MatchLabels 對局代碼欄位的顯示名稱，e.g. rank "gold-4" → "金 IV"
+type MatchLabels struct {
+	Mode      string  `json:"mode"`
+	Rank      *string `json:"rank"` // 沒有階級時為 null
+	PlayOrder string  `json:"playOrder"`
+	Result    string  `json:"result"`
+}
+
+// Rank 結構化的階級（matches.rank_tier / rank_level）
+type Rank struct {
+	Tier  string `json:"tier"`  // 階級代碼，e.g. "gold"；沒有階級制度的遊戲為原樣記錄的文字
+	Level *int   `json:"level"` // 階數，e.g. 4（= IV）；沒有階數時為 nil
+}
+
+// Code 階級代碼："<tier>-<level>"，沒有階數時為 tier 本身
+func (r Rank) Code() string {
+	if r.Level == nil {
+		return r.Tier
+	}
+	return fmt.Sprintf("%s-%d", r.Tier, *r.Level)
+}
+
+// ParseRankCode 將 "<tier>-<level>" 形式的代碼拆成 Rank；最後一段不是數字時整段視為 tier
+func ParseRankCode(code string) Rank {
+	if i := strings.LastIndex(code, "-"); i > 0 {
+		if level, err := strconv.Atoi(code[i+1:]); err == nil {
+			return Rank{Tier: code[:i], Level: &level}
+		}
+	}
+	return Rank{Tier: code}
+}
+
+// RankCode 由 rank_tier / rank_level 欄位組出階級代碼；沒有階級時為 nil
+func RankCode(tier *string, level *int) *string {
+	if tier == nil {
+		return nil
+	}
+	code := Rank{Tier: *tier, Level: level}.Code()
+	return &code
+}
+
+// Rating 對局的積分（matches.rating_before / rating_after / rating_delta），各欄位皆可省略。
+// 寫入時由 rules.Rating 驗證並補齊可推算的欄位（e.g. 有 before 與 delta 時補上 after）。
+type Rating struct {
+	Before *int `json:"before"` // 對局前的積分
+	After  *int `json:"after"`  // 對局後的積分
+	Delta  *int `json:"delta"`  // 積分增減（after - before）
+}
+
+// Empty 三個欄位都沒有值
+func (r Rating) Empty() bool {
+	return r.Before == nil && r.After == nil && r.Delta == nil
+}
+
+// AccountInfo 遊戲帳號資訊
+type AccountInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 // DeckInfo 牌組資訊
@@ -46,28 +133,155 @@ type DeckInfo struct {
 
 // CreateMatchRequest 新增對局的請求結構
 type CreateMatchRequest struct {
-	GameKey    string   `json:"gameKey"`    // e.g. "master_duel"
-	SeasonCode string   `json:"seasonCode"` // e.g. "S48"
-	Date       string   `json:"date"`       // ISO format: YYYY-MM-DD
-	Mode       string   `json:"mode"`       // "Ranked" | "Rating" | "DC" (default Ranked)
-	Rank       string   `json:"rank"`       // e.g. "金IV"
-	MyDeck     DeckForm `json:"myDeck"`
-	OppDeck    DeckForm `json:"oppDeck"`
-	PlayOrder  string   `json:"playOrder"` // "先攻" 或 "後攻"
-	Result     string   `json:"result"`    // "W" 或 "L"
-	Note       *string  `json:"note"`      // 備註（可選）
+	GameKey    string     `json:"gameKey"`    // e.g. "master_duel"
+	SeasonCode string     `json:"seasonCode"` // e.g. "S48"（可省略，依日期推算；有指定時必須與日期相符）
+	AccountID  *string    `json:"accountId"`  // 遊戲帳號 ID（可選）
+	Date       string     `json:"date"`       // 使用者當地的日期 YYYY-MM-DD（有 playedAt 時可省略）
+	PlayedAt   *time.Time `json:"playedAt"`   // 對局時間 RFC 3339（可選）
+	Mode       string     `json:"mode"`       // 依遊戲規則（預設為規則的第一個模式）
+	Rank       RankInput  `json:"rank"`       // 階級代碼、顯示名稱或 {"tier","level"}，e.g. "gold-4" / "金 IV"
+	Rating     *Rating    `json:"rating"`     // 積分 {"before","after","delta"}（可選，只限記錄積分的模式）
+	MyDeck     DeckForm   `json:"myDeck"`
+	OppDeck    DeckForm   `json:"oppDeck"`
+	PlayOrder  string     `json:"playOrder"` // "first" / "second"（也接受 "先攻" / "後攻"）
+	Result     string     `json:"result"`    // "W" | "L" | "D"
+	Note       *string    `json:"note"`      // 備註（可選）
 }
 
 // UpdateMatchRequest 更新對局的請求結構
 type UpdateMatchRequest struct {
-	Date      *string   `json:"date"`
-	Mode      *string   `json:"mode"`
-	Rank      *string   `json:"rank"`
-	MyDeck    *DeckForm `json:"myDeck"`
-	OppDeck   *DeckForm `json:"oppDeck"`
-	PlayOrder *string   `json:"playOrder"`
-	Result    *string   `json:"result"`
-	Note      *string   `json:"note"`
+	SeasonCode *string    `json:"seasonCode"` // 改到其他賽季（必須與日期相符，不存在時自動建立）
+	Date       *string    `json:"date"`
+	PlayedAt   *time.Time `json:"playedAt"` // 修改時 date 依使用者時區重新計算
+	Mode       *string    `json:"mode"`
+	Rank       *RankInput `json:"rank"`
+	Rating     *Rating    `json:"rating"` // 取代原本的積分；{} 為清除
+	MyDeck     *DeckForm  `json:"myDeck"`
+	OppDeck    *DeckForm  `json:"oppDeck"`
+	PlayOrder  *string    `json:"playOrder"`
+	Result     *string    `json:"result"`
+	Note       *string    `json:"note"`
+}
+
+// RankInput 請求中的階級：字串（代碼或顯示名稱）或 {"tier": "gold", "level": 4} 物件，
+// 物件會轉成 "<tier>-<level>" 形式的代碼（level 也可以是羅馬數字，e.g. "IV"）
+type RankInput string
+
+// romanLevels 羅馬數字的階級
+var romanLevels = map[string]int{"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5}
+
+// UnmarshalJSON 接受字串或 {"tier","level"} 物件
+func (r *RankInput) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var obj struct {
+			Tier  string          `json:"tier"`
+			Level json.RawMessage `json:"level"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		level, err := parseRankLevel(obj.Level)
+		if err != nil {
+			return err
+		}
+		*r = RankInput(fmt.Sprintf("%s-%d", strings.ToLower(strings.TrimSpace(obj.Tier)), level))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("rank 必須是字串或 {\"tier\", \"level\"}: %w", err)
+	}
+	*r = RankInput(s)
+	return nil
+}
+
+// parseRankLevel 解析數字（4 / "4"）或羅馬數字（"IV"）的 level
+func parseRankLevel(raw json.RawMessage) (int, error) {
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, fmt.Errorf("rank.level 格式錯誤: %s", raw)
+	}
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if n, ok := romanLevels[s]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("rank.level 格式錯誤: %q", s)
+	}
+	return n, nil
+}
+
+// MatchFilter 對局篩選條件（GET /matches 與 /stats/* 共用）
+//
+// UserID 一定會套用：空字串不會放寬成「所有使用者」，而是查不到任何對局。
+type MatchFilter struct {
+	UserID      string
+	GameKey     string
+	AccountID   string
+	SeasonCode  string
+	Mode        string
+	MyDeckMain  string
+	OppDeckMain string
+	Result      string
+	PlayOrder   string
+	DateFrom    string
+	DateTo      string
+}
+
+// Conditions 將篩選條件轉為 SQL 片段（以 " AND ..." 串接）與對應參數。
+// 假設查詢使用 m / s / my_deck / opp_deck 這組別名（matches JOIN seasons JOIN decks x2）。
+func (f MatchFilter) Conditions() (string, []interface{}) {
+	// 資料一律限定在呼叫者自己的對局
+	clause := " AND m.user_id = ?"
+	args := []interface{}{f.UserID}
+
+	if f.GameKey != "" {
+		clause += " AND m.game_id IN (SELECT id FROM games WHERE key = ?)"
+		args = append(args, f.GameKey)
+	}
+	if f.AccountID != "" {
+		clause += " AND m.account_id = ?"
+		args = append(args, f.AccountID)
+	}
+	if f.SeasonCode != "" {
+		clause += " AND s.code = ?"
+		args = append(args, f.SeasonCode)
+	}
+	if f.Mode != "" {
+		clause += " AND m.mode = ?"
+		args = append(args, f.Mode)
+	}
+	if f.MyDeckMain != "" {
+		clause += " AND my_deck.main = ?"
+		args = append(args, f.MyDeckMain)
+	}
+	if f.OppDeckMain != "" {
+		clause += " AND opp_deck.main = ?"
+		args = append(args, f.OppDeckMain)
+	}
+	if f.Result != "" {
+		clause += " AND m.result = ?"
+		args = append(args, f.Result)
+	}
+	if f.PlayOrder != "" {
+		clause += " AND m.play_order = ?"
+		args = append(args, f.PlayOrder)
+	}
+	if f.DateFrom != "" {
+		clause += " AND m.date >= ?"
+		args = append(args, f.DateFrom)
+	}
+	if f.DateTo != "" {
+		clause += " AND m.date <= ?"
+		args = append(args, f.DateTo)
+	}
+
+	return clause, args
 }
 
 // DeckForm 牌組表單（用於新增/更新）
@@ -84,6 +298,16 @@ type Deck struct {
 	Sub    *string `json:"sub"`
 }
 
+// DeckTemplate 牌組模板（前端選項用）
+type DeckTemplate struct {
+	ID        string    `json:"id"`
+	GameID    string    `json:"-"`
+	Name      string    `json:"name"`
+	Theme     string    `json:"theme"`
+	DeckType  string    `json:"deckType"` // "main" or "sub"
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 // Season 賽季
 type Season struct {
 	ID        string  `json:"id"`
@@ -95,16 +319,41 @@ type Season struct {
 
 // Game 遊戲
 type Game struct {
-	ID   string `json:"id"`
-	Key  string `json:"key"`  // e.g. "master_duel"
-	Name string `json:"name"` // e.g. "Yu-Gi-Oh! Master Duel"
+	ID              string `json:"id"`
+	Key             string `json:"key"`             // e.g. "master_duel"
+	Name            string `json:"name"`            // e.g. "Yu-Gi-Oh! Master Duel"
+	SeasonResetTime string `json:"seasonResetTime"` // 每月 1 日賽季切換的時間（HH:MM）
+	SeasonTimezone  string `json:"seasonTimezone"`  // 賽季切換時間的時區（IANA，e.g. "Asia/Tokyo"）
+}
+
+// Account 遊戲帳號（同一個使用者在同一款遊戲可以有多個帳號）
+type Account struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	GameID    string    `json:"gameId"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 // User 使用者
 type User struct {
 	ID           string    `json:"id"`
 	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // 不輸出到 JSON
+	PasswordHash string    `json:"-"`        // 不輸出到 JSON
+	Timezone     string    `json:"timezone"` // IANA 時區，決定對局的當地日期
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
+
+// APIKey 個人 API key（只保存雜湊，明碼只在建立時回傳一次）
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // 明碼的前幾碼，用來辨識
+	KeyHash    string     `json:"-"`
+	Scope      string     `json:"scope"` // "read" | "write"
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
@@ -0,0 +1,878 @@
+// Package memrepo 以記憶體實作 repo 介面，供 handler 測試使用（不需資料庫）。
+package memrepo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/season"
+)
+
+var _ repo.Store = (*Store)(nil)
+
+// Store 實作 repo.Store；可安全地同時使用
+type Store struct {
+	mu   *sync.Mutex // transaction 內為 nil（外層已持有鎖）
+	data *data
+}
+
+type data struct {
+	users     []models.User
+	games     map[string]models.Game // key: id
+	seasons   map[string]models.Season
+	decks     map[string]models.Deck
+	templates map[string]models.DeckTemplate
+	matches   map[string]models.Match
+	apiKeys   map[string]models.APIKey
+	accounts  map[string]models.Account
+}
+
+// New 建立 Store，預設資料與 seed.sql 相同：一個使用者與 master_duel 遊戲
+func New() *Store {
+	s := &Store{
+		mu: &sync.Mutex{},
+		data: &data{
+			games:     map[string]models.Game{},
+			seasons:   map[string]models.Season{},
+			decks:     map[string]models.Deck{},
+			templates: map[string]models.DeckTemplate{},
+			matches:   map[string]models.Match{},
+			apiKeys:   map[string]models.APIKey{},
+			accounts:  map[string]models.Account{},
+		},
+	}
+	s.AddUser(models.User{ID: "user-001", Email: "demo@duellog.com"})
+	s.AddGame(models.Game{ID: "game-md", Key: "master_duel", Name: "Yu-Gi-Oh! Master Duel"})
+	return s
+}
+
+// AddUser 新增使用者（測試用）
+func (s *Store) AddUser(u models.User) {
+	defer s.lock()()
+	s.data.users = append(s.data.users, withUserDefaults(u))
+}
+
+// AddGame 新增遊戲（測試用）
+func (s *Store) AddGame(g models.Game) {
+	defer s.lock()()
+	s.data.games[g.ID] = withGameDefaults(g)
+}
+
+func (s *Store) Matches() repo.MatchRepository      { return matchRepo{s} }
+func (s *Store) Decks() repo.DeckRepository         { return deckRepo{s} }
+func (s *Store) Seasons() repo.SeasonRepository     { return seasonRepo{s} }
+func (s *Store) Templates() repo.TemplateRepository { return templateRepo{s} }
+func (s *Store) Games() repo.GameRepository         { return gameRepo{s} }
+func (s *Store) Users() repo.UserRepository         { return userRepo{s} }
+func (s *Store) APIKeys() repo.APIKeyRepository     { return apiKeyRepo{s} }
+func (s *Store) Accounts() repo.AccountRepository   { return accountRepo{s} }
+
+// InTx 在資料副本上執行 fn，成功才寫回；執行期間其他呼叫會等待
+func (s *Store) InTx(fn func(tx repo.Store) error) error {
+	if s.mu == nil {
+		return fn(s)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.data.clone()
+	if err := fn(&Store{data: snapshot}); err != nil {
+		return err
+	}
+	s.data = snapshot
+	return nil
+}
+
+// lock 取得鎖並回傳解鎖函式；transaction 內不需再上鎖
+func (s *Store) lock() func() {
+	if s.mu == nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (d *data) clone() *data {
+	c := &data{
+		users:     append([]models.User(nil), d.users...),
+		games:     make(map[string]models.Game, len(d.games)),
+		seasons:   make(map[string]models.Season, len(d.seasons)),
+		decks:     make(map[string]models.Deck, len(d.decks)),
+		templates: make(map[string]models.DeckTemplate, len(d.templates)),
+		matches:   make(map[string]models.Match, len(d.matches)),
+		apiKeys:   make(map[string]models.APIKey, len(d.apiKeys)),
+		accounts:  make(map[string]models.Account, len(d.accounts)),
+	}
+	for k, v := range d.games {
+		c.games[k] = v
+	}
+	for k, v := range d.seasons {
+		c.seasons[k] = v
+	}
+	for k, v := range d.decks {
+		c.decks[k] = v
+	}
+	for k, v := range d.templates {
+		c.templates[k] = v
+	}
+	for k, v := range d.matches {
+		c.matches[k] = v
+	}
+	for k, v := range d.apiKeys {
+		c.apiKeys[k] = v
+	}
+	for k, v := range d.accounts {
+		c.accounts[k] = v
+	}
+	return c
+}
+
+// ===== matches =====
+
+type matchRepo struct{ s *Store }
+
+func (r matchRepo) List(f models.MatchFilter) ([]models.MatchWithDetails, error) {
+	defer r.s.lock()()
+	d := r.s.data
+
+	matches := []models.MatchWithDetails{}
+	for _, m := range d.matches {
+		details := d.details(m)
+		if d.matchesFilter(m, details, f) {
+			matches = append(matches, details)
+		}
+	}
+
+	// 按日期排序（最新在前）
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Date != matches[j].Date {
+			return matches[i].Date > matches[j].Date
+		}
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+	return matches, nil
+}
+
+func (r matchRepo) Get(userID, id string) (models.MatchWithDetails, error) {
+	defer r.s.lock()()
+	m, ok := r.s.data.matches[id]
+	if !ok || m.UserID != userID {
+		return models.MatchWithDetails{}, repo.ErrNotFound
+	}
+	return r.s.data.details(m), nil
+}
+
+func (r matchRepo) Create(m models.Match) error {
+	defer r.s.lock()()
+	now := time.Now()
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = now
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = now
+	}
+	if m.PlayedAt != nil {
+		playedAt := m.PlayedAt.UTC()
+		m.PlayedAt = &playedAt
+	}
+	m.Rank = cloneRank(m.Rank)
+	m.Rating = cloneRating(m.Rating)
+	r.s.data.matches[m.ID] = m
+	return nil
+}
+
+func (r matchRepo) Update(userID, id string, patch repo.MatchPatch) error {
+	defer r.s.lock()()
+	m, ok := r.s.data.matches[id]
+	if !ok || m.UserID != userID {
+		return repo.ErrNotFound
+	}
+	set := func(field *string, value *string) {
+		if value != nil {
+			*field = *value
+		}
+	}
+	set(&m.SeasonID, patch.SeasonID)
+	set(&m.MyDeckID, patch.MyDeckID)
+	set(&m.OppDeckID, patch.OppDeckID)
+	set(&m.Date, patch.Date)
+	if patch.PlayedAt != nil {
+		playedAt := patch.PlayedAt.UTC()
+		m.PlayedAt = &playedAt
+	}
+	set(&m.Mode, patch.Mode)
+	if patch.Rank != nil {
+		m.Rank = cloneRank(patch.Rank)
+	}
+	if patch.Rating != nil {
+		m.Rating = cloneRating(patch.Rating)
+	}
+	set(&m.PlayOrder, patch.PlayOrder)
+	set(&m.Result, patch.Result)
+	if patch.Note != nil {
+		note := *patch.Note
+		m.Note = &note
+	}
+	m.UpdatedAt = time.Now()
+	r.s.data.matches[id] = m
+	return nil
+}
+
+func (r matchRepo) Delete(userID, id string) error {
+	defer r.s.lock()()
+	if m, ok := r.s.data.matches[id]; !ok || m.UserID != userID {
+		return repo.ErrNotFound
+	}
+	delete(r.s.data.matches, id)
+	return nil
+}
+
+func (r matchRepo) Relocalize(userID string, loc *time.Location) (int64, error) {
+	defer r.s.lock()()
+	var n int64
+	for id, m := range r.s.data.matches {
+		if m.UserID != userID || m.PlayedAt == nil {
+			continue
+		}
+		if local := season.LocalDate(*m.PlayedAt, loc); local != m.Date {
+			m.Date = local
+			r.s.data.matches[id] = m
+			n++
+		}
+	}
+	return n, nil
+}
+
+// cloneRank 複製階級（不與呼叫端共用指標）；沒有階級（nil 或 Tier 為空）時為 nil
+func cloneRank(rank *models.Rank) *models.Rank {
+	if rank == nil || rank.Tier == "" {
+		return nil
+	}
+	c := models.Rank{Tier: rank.Tier}
+	if rank.Level != nil {
+		level := *rank.Level
+		c.Level = &level
+	}
+	return &c
+}
+
+// cloneRating 複製積分（不與呼叫端共用指標）；沒有積分（nil 或三個欄位皆為 nil）時為 nil
+func cloneRating(rating *models.Rating) *models.Rating {
+	if rating == nil || rating.Empty() {
+		return nil
+	}
+	clone := func(v *int) *int {
+		if v == nil {
+			return nil
+		}
+		n := *v
+		return &n
+	}
+	return &models.Rating{Before: clone(rating.Before), After: clone(rating.After), Delta: clone(rating.Delta)}
+}
+
+// details 組出與 sqlrepo 相同形狀的 MatchWithDetails
+func (d *data) details(m models.Match) models.MatchWithDetails {
+	var rankTier *string
+	var rankLevel *int
+	if m.Rank != nil {
+		tier := m.Rank.Tier
+		rankTier, rankLevel = &tier, cloneRank(m.Rank).Level
+	}
+	rankCode := models.RankCode(rankTier, rankLevel)
+	myDeck := d.decks[m.MyDeckID]
+	oppDeck := d.decks[m.OppDeckID]
+	var account *models.AccountInfo
+	if m.AccountID != nil {
+		if a, ok := d.accounts[*m.AccountID]; ok {
+			account = &models.AccountInfo{ID: a.ID, Name: a.Name}
+		}
+	}
+	return models.MatchWithDetails{
+		ID:         m.ID,
+		GameID:     m.GameID,
+		GameKey:    d.games[m.GameID].Key,
+		Date:       m.Date,
+		PlayedAt:   m.PlayedAt,
+		Mode:       m.Mode,
+		Rank:       rankCode,
+		RankTier:   rankTier,
+		RankLevel:  rankLevel,
+		Rating:     cloneRating(m.Rating),
+		MyDeck:     models.DeckInfo{ID: myDeck.ID, Main: myDeck.Main, Sub: myDeck.Sub},
+		OppDeck:    models.DeckInfo{ID: oppDeck.ID, Main: oppDeck.Main, Sub: oppDeck.Sub},
+		PlayOrder:  m.PlayOrder,
+		Result:     m.Result,
+		Note:       m.Note,
+		SeasonCode: d.seasons[m.SeasonID].Code,
+		Account:    account,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+// matchesFilter 與 MatchFilter.Conditions 相同的篩選規則
+func (d *data) matchesFilter(m models.Match, details models.MatchWithDetails, f models.MatchFilter) bool {
+	switch {
+	case m.UserID != f.UserID,
+		f.GameKey != "" && d.games[m.GameID].Key != f.GameKey,
+		f.AccountID != "" && (m.AccountID == nil || *m.AccountID != f.AccountID),
+		f.SeasonCode != "" && details.SeasonCode != f.SeasonCode,
+		f.Mode != "" && m.Mode != f.Mode,
+		f.MyDeckMain != "" && details.MyDeck.Main != f.MyDeckMain,
+		f.OppDeckMain != "" && details.OppDeck.Main != f.OppDeckMain,
+		f.Result != "" && m.Result != f.Result,
+		f.PlayOrder != "" && m.PlayOrder != f.PlayOrder,
+		f.DateFrom != "" && m.Date < f.DateFrom,
+		f.DateTo != "" && m.Date > f.DateTo:
+		return false
+	}
+	return true
+}
+
+// ===== decks =====
+
+type deckRepo struct{ s *Store }
+
+func (r deckRepo) FindOrCreate(gameID, main string, sub *string) (string, error) {
+	defer r.s.lock()()
+	d := r.s.data
+
+	for _, deck := range d.decks {
+		if deck.GameID == gameID && deck.Main == main && equalSub(deck.Sub, sub) {
+			return deck.ID, nil
+		}
+	}
+
+	deck := models.Deck{ID: uuid.New().String(), GameID: gameID, Main: main}
+	if sub != nil {
+		value := *sub
+		deck.Sub = &value
+	}
+	d.decks[deck.ID] = deck
+
+	// 同時確保 deck_templates 中有這個牌組（用於顏色顯示）
+	d.ensureTemplate(gameID, main)
+	if sub != nil && *sub != "" && *sub != "無" {
+		d.ensureTemplate(gameID, *sub)
+	}
+	return deck.ID, nil
+}
+
+func (r deckRepo) Rename(oldName, newName string) (int64, int64, error) {
+	defer r.s.lock()()
+	var mainRows, subRows int64
+	for id, deck := range r.s.data.decks {
+		if deck.Main == oldName {
+			deck.Main = newName
+			mainRows++
+		}
+		if deck.Sub != nil && *deck.Sub == oldName {
+			renamed := newName
+			deck.Sub = &renamed
+			subRows++
+		}
+		r.s.data.decks[id] = deck
+	}
+	return mainRows, subRows, nil
+}
+
+func (r deckRepo) CountByName(name string) (int, error) {
+	defer r.s.lock()()
+	count := 0
+	for _, deck := range r.s.data.decks {
+		if deck.Main == name || (deck.Sub != nil && *deck.Sub == name) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// errDuplicate 對應資料庫 UNIQUE 限制的錯誤
+func errDuplicate(table, key string) error {
+	return fmt.Errorf("%w: %s: duplicate %q", repo.ErrConflict, table, key)
+}
+
+func (r deckRepo) DeleteUnused(ids ...string) (int64, error) {
+	defer r.s.lock()()
+	var n int64
+	for _, id := range ids {
+		if _, ok := r.s.data.decks[id]; !ok || r.s.data.deckInUse(id) {
+			continue
+		}
+		delete(r.s.data.decks, id)
+		n++
+	}
+	return n, nil
+}
+
+func (d *data) deckInUse(id string) bool {
+	for _, m := range d.matches {
+		if m.MyDeckID == id || m.OppDeckID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func equalSub(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// ===== seasons =====
+
+type seasonRepo struct{ s *Store }
+
+func (r seasonRepo) List(gameID string) ([]models.Season, error) {
+	defer r.s.lock()()
+	seasons := []models.Season{}
+	for _, s := range r.s.data.seasons {
+		if s.GameID == gameID {
+			seasons = append(seasons, s)
+		}
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		a, b := seasons[i], seasons[j]
+		if (a.StartDate == nil) != (b.StartDate == nil) {
+			return b.StartDate == nil
+		}
+		if a.StartDate != nil && *a.StartDate != *b.StartDate {
+			return *a.StartDate > *b.StartDate
+		}
+		return a.Code > b.Code
+	})
+	return seasons, nil
+}
+
+func (r seasonRepo) GetByCode(gameID, code string) (models.Season, error) {
+	defer r.s.lock()()
+	return r.s.data.seasonByCode(gameID, code)
+}
+
+func (r seasonRepo) GetByDate(gameID, date string) (models.Season, error) {
+	defer r.s.lock()()
+	var found *models.Season
+	for _, s := range r.s.data.seasons {
+		if s.GameID != gameID || s.StartDate == nil || s.EndDate == nil {
+			continue
+		}
+		if *s.StartDate <= date && date <= *s.EndDate && (found == nil || *s.StartDate > *found.StartDate) {
+			s := s
+			found = &s
+		}
+	}
+	if found == nil {
+		return models.Season{}, repo.ErrNotFound
+	}
+	return *found, nil
+}
+
+func (r seasonRepo) Create(s models.Season) error {
+	defer r.s.lock()()
+	if _, err := r.s.data.seasonByCode(s.GameID, s.Code); err == nil {
+		return errDuplicate("seasons", s.Code)
+	}
+	r.s.data.seasons[s.ID] = s
+	return nil
+}
+
+func (r seasonRepo) Update(s models.Season) error {
+	defer r.s.lock()()
+	existing, err := r.s.data.seasonByCode(s.GameID, s.Code)
+	if err != nil {
+		return err
+	}
+	existing.StartDate, existing.EndDate = s.StartDate, s.EndDate
+	r.s.data.seasons[existing.ID] = existing
+	return nil
+}
+
+func (r seasonRepo) GetOrCreate(s models.Season) (string, error) {
+	defer r.s.lock()()
+	if existing, err := r.s.data.seasonByCode(s.GameID, s.Code); err == nil {
+		return existing.ID, nil
+	}
+
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	r.s.data.seasons[s.ID] = s
+	return s.ID, nil
+}
+
+func (d *data) seasonByCode(gameID, code string) (models.Season, error) {
+	for _, s := range d.seasons {
+		if s.GameID == gameID && s.Code == code {
+			return s, nil
+		}
+	}
+	return models.Season{}, repo.ErrNotFound
+}
+
+// ===== deck templates =====
+
+type templateRepo struct{ s *Store }
+
+func (r templateRepo) List(gameID, deckType string) ([]models.DeckTemplate, error) {
+	defer r.s.lock()()
+	templates := []models.DeckTemplate{}
+	for _, t := range r.s.data.templates {
+		if (gameID == "" || t.GameID == gameID) && (deckType == "" || t.DeckType == deckType) {
+			templates = append(templates, t)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].DeckType != templates[j].DeckType {
+			return templates[i].DeckType < templates[j].DeckType
+		}
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r templateRepo) Exists(gameID, name, deckType string) (bool, error) {
+	defer r.s.lock()()
+	for _, t := range r.s.data.templates {
+		if (gameID == "" || t.GameID == gameID) && t.Name == name && t.DeckType == deckType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r templateRepo) Create(t models.DeckTemplate) error {
+	defer r.s.lock()()
+	for _, existing := range r.s.data.templates {
+		if existing.GameID == t.GameID && existing.Name == t.Name && existing.DeckType == t.DeckType {
+			return errDuplicate("deck_templates", t.Name)
+		}
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	r.s.data.templates[t.ID] = t
+	return nil
+}
+
+func (r templateRepo) Update(gameID, id, name, theme string) error {
+	defer r.s.lock()()
+	t, ok := r.s.data.templates[id]
+	if !ok || t.GameID != gameID {
+		return repo.ErrNotFound
+	}
+	if name != "" {
+		t.Name = name
+	}
+	if theme != "" {
+		t.Theme = theme
+	}
+	r.s.data.templates[id] = t
+	return nil
+}
+
+func (r templateRepo) Delete(gameID, id string) error {
+	defer r.s.lock()()
+	if t, ok := r.s.data.templates[id]; !ok || t.GameID != gameID {
+		return repo.ErrNotFound
+	}
+	delete(r.s.data.templates, id)
+	return nil
+}
+
+func (r templateRepo) Ensure(gameID, name string) (bool, error) {
+	defer r.s.lock()()
+	return r.s.data.ensureTemplate(gameID, name), nil
+}
+
+func (r templateRepo) Rename(oldName, newName string) (int64, error) {
+	defer r.s.lock()()
+	var n int64
+	for id, t := range r.s.data.templates {
+		if t.Name == oldName {
+			t.Name = newName
+			r.s.data.templates[id] = t
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (d *data) ensureTemplate(gameID, name string) bool {
+	for _, t := range d.templates {
+		if t.GameID == gameID && t.Name == name && t.DeckType == "main" {
+			return false
+		}
+	}
+	id := "tpl-auto-" + uuid.New().String()[:8]
+	d.templates[id] = models.DeckTemplate{
+		ID: id, GameID: gameID, Name: name, Theme: "無", DeckType: "main", CreatedAt: time.Now(),
+	}
+	return true
+}
+
+// ===== games / users =====
+
+type gameRepo struct{ s *Store }
+
+func (r gameRepo) List() ([]models.Game, error) {
+	defer r.s.lock()()
+	games := []models.Game{}
+	for _, g := range r.s.data.games {
+		games = append(games, g)
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].Name < games[j].Name })
+	return games, nil
+}
+
+func (r gameRepo) GetByKey(key string) (models.Game, error) {
+	defer r.s.lock()()
+	for _, g := range r.s.data.games {
+		if g.Key == key {
+			return g, nil
+		}
+	}
+	return models.Game{}, repo.ErrNotFound
+}
+
+func (r gameRepo) Create(g models.Game) error {
+	defer r.s.lock()()
+	for _, existing := range r.s.data.games {
+		if existing.ID == g.ID || existing.Key == g.Key {
+			return errDuplicate("games", g.Key)
+		}
+	}
+	r.s.data.games[g.ID] = withGameDefaults(g)
+	return nil
+}
+
+func (r gameRepo) Update(g models.Game) error {
+	defer r.s.lock()()
+	for id, existing := range r.s.data.games {
+		if existing.Key == g.Key {
+			existing.Name = g.Name
+			existing.SeasonResetTime = g.SeasonResetTime
+			existing.SeasonTimezone = g.SeasonTimezone
+			r.s.data.games[id] = existing
+			return nil
+		}
+	}
+	return repo.ErrNotFound
+}
+
+// withGameDefaults 與 schema 的預設值相同
+func withGameDefaults(g models.Game) models.Game {
+	if g.SeasonResetTime == "" {
+		g.SeasonResetTime = models.DefaultSeasonResetTime
+	}
+	if g.SeasonTimezone == "" {
+		g.SeasonTimezone = models.DefaultTimezone
+	}
+	return g
+}
+
+type userRepo struct{ s *Store }
+
+func (r userRepo) DefaultID() (string, error) {
+	defer r.s.lock()()
+	if len(r.s.data.users) == 0 {
+		return "", repo.ErrNotFound
+	}
+	return r.s.data.users[0].ID, nil
+}
+
+func (r userRepo) GetByID(id string) (models.User, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return models.User{}, repo.ErrNotFound
+}
+
+func (r userRepo) GetByEmail(email string) (models.User, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if strings.EqualFold(u.Email, email) {
+			return u, nil
+		}
+	}
+	return models.User{}, repo.ErrNotFound
+}
+
+func (r userRepo) Create(u models.User) error {
+	defer r.s.lock()()
+	for _, existing := range r.s.data.users {
+		if existing.ID == u.ID || strings.EqualFold(existing.Email, u.Email) {
+			return errDuplicate("users", u.Email)
+		}
+	}
+	now := time.Now()
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	if u.UpdatedAt.IsZero() {
+		u.UpdatedAt = now
+	}
+	r.s.data.users = append(r.s.data.users, withUserDefaults(u))
+	return nil
+}
+
+func (r userRepo) UpdateTimezone(id, timezone string) error {
+	defer r.s.lock()()
+	for i, u := range r.s.data.users {
+		if u.ID == id {
+			r.s.data.users[i].Timezone = timezone
+			r.s.data.users[i].UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return repo.ErrNotFound
+}
+
+// withUserDefaults 與 schema 的預設值相同
+func withUserDefaults(u models.User) models.User {
+	if u.Timezone == "" {
+		u.Timezone = models.DefaultTimezone
+	}
+	return u
+}
+
+// ===== accounts =====
+
+type accountRepo struct{ s *Store }
+
+func (r accountRepo) List(userID, gameID string) ([]models.Account, error) {
+	defer r.s.lock()()
+	accounts := []models.Account{}
+	for _, a := range r.s.data.accounts {
+		if a.UserID == userID && (gameID == "" || a.GameID == gameID) {
+			accounts = append(accounts, a)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts, nil
+}
+
+func (r accountRepo) Get(userID, id string) (models.Account, error) {
+	defer r.s.lock()()
+	a, ok := r.s.data.accounts[id]
+	if !ok || a.UserID != userID {
+		return models.Account{}, repo.ErrNotFound
+	}
+	return a, nil
+}
+
+func (r accountRepo) Create(a models.Account) error {
+	defer r.s.lock()()
+	return r.s.data.createAccount(a)
+}
+
+func (r accountRepo) GetOrCreate(userID, gameID, name string) (string, error) {
+	defer r.s.lock()()
+	for _, a := range r.s.data.accounts {
+		if a.UserID == userID && a.GameID == gameID && a.Name == name {
+			return a.ID, nil
+		}
+	}
+	a := models.Account{ID: uuid.New().String(), UserID: userID, GameID: gameID, Name: name}
+	return a.ID, r.s.data.createAccount(a)
+}
+
+func (d *data) createAccount(a models.Account) error {
+	for _, existing := range d.accounts {
+		if existing.ID == a.ID || (existing.UserID == a.UserID && existing.GameID == a.GameID && existing.Name == a.Name) {
+			return errDuplicate("accounts", a.Name)
+		}
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	d.accounts[a.ID] = a
+	return nil
+}
+
+func (r accountRepo) Delete(userID, id string) error {
+	defer r.s.lock()()
+	a, ok := r.s.data.accounts[id]
+	if !ok || a.UserID != userID {
+		return repo.ErrNotFound
+	}
+	for _, m := range r.s.data.matches {
+		if m.AccountID != nil && *m.AccountID == id {
+			return fmt.Errorf("%w: account %s is used by matches", repo.ErrConflict, id)
+		}
+	}
+	delete(r.s.data.accounts, id)
+	return nil
+}
+
+// ===== api keys =====
+
+type apiKeyRepo struct{ s *Store }
+
+func (r apiKeyRepo) ListByUser(userID string) ([]models.APIKey, error) {
+	defer r.s.lock()()
+	keys := []models.APIKey{}
+	for _, k := range r.s.data.apiKeys {
+		if k.UserID == userID {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r apiKeyRepo) GetByHash(keyHash string) (models.APIKey, error) {
+	defer r.s.lock()()
+	for _, k := range r.s.data.apiKeys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return models.APIKey{}, repo.ErrNotFound
+}
+
+func (r apiKeyRepo) Create(k models.APIKey) error {
+	defer r.s.lock()()
+	for _, existing := range r.s.data.apiKeys {
+		if existing.ID == k.ID || existing.KeyHash == k.KeyHash {
+			return errDuplicate("api_keys", k.ID)
+		}
+	}
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now()
+	}
+	r.s.data.apiKeys[k.ID] = k
+	return nil
+}
+
+func (r apiKeyRepo) Revoke(userID, id string) error {
+	defer r.s.lock()()
+	k, ok := r.s.data.apiKeys[id]
+	if !ok || k.UserID != userID || k.RevokedAt != nil {
+		return repo.ErrNotFound
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	r.s.data.apiKeys[id] = k
+	return nil
+}
+
+func (r apiKeyRepo) Touch(id string, at time.Time) error {
+	defer r.s.lock()()
+	if k, ok := r.s.data.apiKeys[id]; ok {
+		k.LastUsedAt = &at
+		r.s.data.apiKeys[id] = k
+	}
+	return nil
+}
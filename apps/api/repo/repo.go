@@ -0,0 +1,173 @@
+// Package repo 定義 handler / cmd 工具與資料庫之間的資料存取介面。
+//
+// 實作：
+//   - repo/sqlrepo：database/sql（SQLite 與 PostgreSQL，經由 storage 套件）
+//   - repo/memrepo：記憶體實作，供 handler 測試使用
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+)
+
+// ErrNotFound 找不到指定的資料
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict 寫入違反唯一性限制（通常是同時有另一個請求建立了同一筆資料，重試即可）
+var ErrConflict = errors.New("conflict")
+
+// Store 一組共用同一個連線（或 transaction）的 repository
+type Store interface {
+	Matches() MatchRepository
+	Decks() DeckRepository
+	Seasons() SeasonRepository
+	Templates() TemplateRepository
+	Games() GameRepository
+	Users() UserRepository
+	APIKeys() APIKeyRepository
+	Accounts() AccountRepository
+
+	// InTx 在單一 transaction 內執行 fn；fn 回傳錯誤時整批回滾
+	InTx(fn func(tx Store) error) error
+}
+
+// MatchPatch 部分更新對局；nil 欄位代表不變更
+type MatchPatch struct {
+	SeasonID  *string
+	MyDeckID  *string
+	OppDeckID *string
+	Date      *string
+	PlayedAt  *time.Time
+	Mode      *string
+	Rank      *models.Rank   // Tier 為空字串時清除階級（改成不記錄階級的模式）
+	Rating    *models.Rating // 三個欄位都是 nil 時清除積分
+	PlayOrder *string
+	Result    *string
+	Note      *string
+}
+
+// Empty 是否沒有任何要更新的欄位
+func (p MatchPatch) Empty() bool {
+	return p.SeasonID == nil && p.MyDeckID == nil && p.OppDeckID == nil &&
+		p.Date == nil && p.PlayedAt == nil && p.Mode == nil && p.Rank == nil && p.Rating == nil &&
+		p.PlayOrder == nil && p.Result == nil && p.Note == nil
+}
+
+// MatchRepository 對局記錄
+//
+// 讀取與修改一律限定在 userID 自己的對局；其他使用者的對局視同不存在（ErrNotFound）。
+type MatchRepository interface {
+	// List 依篩選條件列出 f.UserID 的對局（日期新到舊）
+	List(f models.MatchFilter) ([]models.MatchWithDetails, error)
+	// Get 取得單筆對局；不存在時回傳 ErrNotFound
+	Get(userID, id string) (models.MatchWithDetails, error)
+	// Create 以 m.UserID 作為擁有者建立對局
+	Create(m models.Match) error
+	// Update 套用 patch 並更新 updated_at；不存在時回傳 ErrNotFound
+	Update(userID, id string, patch MatchPatch) error
+	// Delete 刪除對局；不存在時回傳 ErrNotFound
+	Delete(userID, id string) error
+	// Relocalize 依 played_at 以 loc 重新計算使用者所有對局的當地日期（沒有 played_at 的不變），回傳更新筆數
+	Relocalize(userID string, loc *time.Location) (int64, error)
+}
+
+// DeckRepository 牌組（大軸 / 小軸組合）
+//
+// 牌組只是名稱組合、不含對局資料，所有使用者共用；使用者看得到的牌組一律經由自己的對局取得。
+type DeckRepository interface {
+	// FindOrCreate 尋找或建立牌組，回傳 deck ID；新建時一併確保 deck_templates 有對應模板
+	FindOrCreate(gameID, main string, sub *string) (string, error)
+	// Rename 將 decks.main / decks.sub 中的 oldName 改為 newName，回傳各自更新的筆數
+	Rename(oldName, newName string) (mainRows, subRows int64, err error)
+	// CountByName 大軸或小軸為 name 的牌組數
+	CountByName(name string) (int, error)
+	// DeleteUnused 刪除 ids 中已沒有任何對局引用的牌組，回傳刪除筆數
+	DeleteUnused(ids ...string) (int64, error)
+}
+
+// SeasonRepository 賽季（起訖日由呼叫端依 season 套件的賽季曆填入）
+type SeasonRepository interface {
+	// List 列出 gameID 的賽季（開始日新到舊，沒有起訖日的排在最後）
+	List(gameID string) ([]models.Season, error)
+	// GetByCode 不存在時回傳 ErrNotFound
+	GetByCode(gameID, code string) (models.Season, error)
+	// GetByDate 取得起訖日涵蓋 date（YYYY-MM-DD）的賽季；沒有時回傳 ErrNotFound
+	GetByDate(gameID, date string) (models.Season, error)
+	// Create 同一款遊戲的 code 重複時回傳 ErrConflict
+	Create(s models.Season) error
+	// Update 更新 s.GameID / s.Code 的起訖日；不存在時回傳 ErrNotFound
+	Update(s models.Season) error
+	// GetOrCreate 取得 s.GameID / s.Code 的賽季 ID；不存在時以 s 建立（ID 為空時自動產生）
+	GetOrCreate(s models.Season) (string, error)
+}
+
+// TemplateRepository 牌組模板（前端下拉選項與顏色）
+type TemplateRepository interface {
+	// List 列出模板；gameID / deckType 為空字串時不限
+	List(gameID, deckType string) ([]models.DeckTemplate, error)
+	// Exists gameID 為空字串時不限遊戲
+	Exists(gameID, name, deckType string) (bool, error)
+	Create(t models.DeckTemplate) error
+	// Update 更新 gameID 這款遊戲的模板名稱 / 主題（空字串代表不變）；不存在或屬於其他遊戲時回傳 ErrNotFound
+	Update(gameID, id, name, theme string) error
+	// Delete 刪除 gameID 這款遊戲的模板；不存在或屬於其他遊戲時回傳 ErrNotFound
+	Delete(gameID, id string) error
+	// Ensure 確保主軸模板存在，不存在則以主題「無」建立；回傳是否新建
+	Ensure(gameID, name string) (bool, error)
+	// Rename 將模板名稱 oldName 改為 newName，回傳更新筆數
+	Rename(oldName, newName string) (int64, error)
+}
+
+// GameRepository 遊戲
+type GameRepository interface {
+	List() ([]models.Game, error)
+	// GetByKey 不存在時回傳 ErrNotFound
+	GetByKey(key string) (models.Game, error)
+	// Create key 已存在時回傳 ErrConflict
+	Create(g models.Game) error
+	// Update 以 g.Key 更新名稱與賽季切換時間；不存在時回傳 ErrNotFound
+	Update(g models.Game) error
+}
+
+// UserRepository 使用者
+type UserRepository interface {
+	// DefaultID MVP 單人模式的預設使用者；沒有使用者時回傳 ErrNotFound
+	DefaultID() (string, error)
+	// GetByID 不存在時回傳 ErrNotFound
+	GetByID(id string) (models.User, error)
+	// GetByEmail email 不分大小寫；不存在時回傳 ErrNotFound
+	GetByEmail(email string) (models.User, error)
+	// Create email 已被使用時回傳 ErrConflict
+	Create(u models.User) error
+	// UpdateTimezone 不存在時回傳 ErrNotFound
+	UpdateTimezone(id, timezone string) error
+}
+
+// AccountRepository 遊戲帳號；一律限定在 userID 自己的帳號
+type AccountRepository interface {
+	// List 列出使用者的帳號；gameID 為空字串時列出所有遊戲
+	List(userID, gameID string) ([]models.Account, error)
+	// Get 不存在或不屬於 userID 時回傳 ErrNotFound
+	Get(userID, id string) (models.Account, error)
+	// Create 同一遊戲已有同名帳號時回傳 ErrConflict
+	Create(a models.Account) error
+	// GetOrCreate 以名稱取得帳號 ID；不存在時自動建立
+	GetOrCreate(userID, gameID, name string) (string, error)
+	// Delete 不存在時回傳 ErrNotFound；仍有對局使用時回傳 ErrConflict
+	Delete(userID, id string) error
+}
+
+// APIKeyRepository 個人 API key
+type APIKeyRepository interface {
+	// ListByUser 列出使用者的 API key（含已撤銷的，新到舊）
+	ListByUser(userID string) ([]models.APIKey, error)
+	// GetByHash 以明碼的雜湊查詢；不存在時回傳 ErrNotFound
+	GetByHash(keyHash string) (models.APIKey, error)
+	Create(k models.APIKey) error
+	// Revoke 撤銷使用者自己的 API key；不存在或已撤銷時回傳 ErrNotFound
+	Revoke(userID, id string) error
+	// Touch 記錄最後使用時間
+	Touch(id string, at time.Time) error
+}
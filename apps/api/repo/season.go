@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/season"
+)
+
+// ErrNoSeasonForDate 沒有指定賽季代碼，資料庫中沒有涵蓋該日期的賽季，遊戲也沒有賽季曆
+var ErrNoSeasonForDate = errors.New("無法由日期推算賽季，請指定 seasonCode")
+
+// ResolveSeason 決定對局的賽季代碼（POST / PATCH /matches 與 cmd/import 共用）。
+// 資料庫中的起訖日優先（管理者可能以 PATCH /seasons 調整過），沒有時才依賽季曆（season.Resolve）：
+//   - code 空白時取起訖日涵蓋 date 的賽季；都推算不出來時回傳 ErrNoSeasonForDate
+//   - code 有值時 date 必須落在該賽季內，否則回傳 *season.MismatchError
+//
+// 賽季曆認得的代碼會正規化（e.g. "s49" → "S49"）。
+func ResolveSeason(seasons SeasonRepository, game models.Game, code string, date time.Time) (string, error) {
+	day := date.Format(season.DateLayout)
+	code = strings.TrimSpace(code)
+	if code == "" {
+		existing, err := seasons.GetByDate(game.ID, day)
+		if !errors.Is(err, ErrNotFound) {
+			return existing.Code, err
+		}
+		if resolved, _ := season.Resolve(game.Key, "", date); resolved != "" {
+			return resolved, nil
+		}
+		return "", ErrNoSeasonForDate
+	}
+
+	if cal, ok := season.For(game.Key); ok {
+		if info, ok := cal.Parse(code); ok {
+			code = info.Code
+		}
+	}
+	existing, err := seasons.GetByCode(game.ID, code)
+	switch {
+	case errors.Is(err, ErrNotFound), err == nil && (existing.StartDate == nil || existing.EndDate == nil):
+		resolved, err := season.Resolve(game.Key, code, date)
+		var mismatch *season.MismatchError
+		if errors.As(err, &mismatch) {
+			mismatch.Expected = expectedSeason(seasons, game, date)
+		}
+		return resolved, err
+	case err != nil:
+		return "", err
+	}
+	if day < *existing.StartDate || day > *existing.EndDate {
+		return "", &season.MismatchError{Code: code, Date: day, Expected: expectedSeason(seasons, game, date)}
+	}
+	return code, nil
+}
+
+// expectedSeason date 所在的賽季代碼（同 ResolveSeason 的推算方式）；推算不出來時為空字串
+func expectedSeason(seasons SeasonRepository, game models.Game, date time.Time) string {
+	code, err := ResolveSeason(seasons, game, "", date)
+	if err != nil {
+		return ""
+	}
+	return code
+}
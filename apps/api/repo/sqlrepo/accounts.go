@@ -0,0 +1,95 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type accountRepo struct {
+	q storage.Querier
+}
+
+const accountSelect = "SELECT id, user_id, game_id, name, created_at FROM accounts"
+
+func (r accountRepo) List(userID, gameID string) ([]models.Account, error) {
+	query := accountSelect + " WHERE user_id = ?"
+	args := []interface{}{userID}
+	if gameID != "" {
+		query += " AND game_id = ?"
+		args = append(args, gameID)
+	}
+
+	rows, err := r.q.Query(query+" ORDER BY name ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+	for rows.Next() {
+		var a models.Account
+		if err := rows.Scan(&a.ID, &a.UserID, &a.GameID, &a.Name, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+func (r accountRepo) Get(userID, id string) (models.Account, error) {
+	return r.get("id = ? AND user_id = ?", id, userID)
+}
+
+func (r accountRepo) get(where string, args ...interface{}) (models.Account, error) {
+	var a models.Account
+	err := r.q.QueryRow(accountSelect+" WHERE "+where, args...).Scan(&a.ID, &a.UserID, &a.GameID, &a.Name, &a.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, repo.ErrNotFound
+	}
+	return a, err
+}
+
+func (r accountRepo) Create(a models.Account) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	_, err := r.q.Exec(
+		"INSERT INTO accounts (id, user_id, game_id, name, created_at) VALUES (?, ?, ?, ?, ?)",
+		a.ID, a.UserID, a.GameID, a.Name, a.CreatedAt,
+	)
+	return conflict(err)
+}
+
+func (r accountRepo) GetOrCreate(userID, gameID, name string) (string, error) {
+	existing, err := r.get("user_id = ? AND game_id = ? AND name = ?", userID, gameID, name)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return "", err
+	}
+
+	a := models.Account{ID: uuid.New().String(), UserID: userID, GameID: gameID, Name: name}
+	if err := r.Create(a); err != nil {
+		return "", err
+	}
+	return a.ID, nil
+}
+
+func (r accountRepo) Delete(userID, id string) error {
+	var inUse int
+	if err := r.q.QueryRow("SELECT COUNT(*) FROM matches WHERE account_id = ? AND user_id = ?", id, userID).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return fmt.Errorf("%w: account %s is used by %d matches", repo.ErrConflict, id, inUse)
+	}
+	return mustAffect(r.q.Exec("DELETE FROM accounts WHERE id = ? AND user_id = ?", id, userID))
+}
@@ -0,0 +1,85 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type apiKeyRepo struct {
+	q storage.Querier
+}
+
+const apiKeySelect = `
+	SELECT id, user_id, name, prefix, key_hash, scope, created_at, last_used_at, revoked_at
+	FROM api_keys
+`
+
+func (r apiKeyRepo) ListByUser(userID string) ([]models.APIKey, error) {
+	rows, err := r.q.Query(apiKeySelect+" WHERE user_id = ? ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r apiKeyRepo) GetByHash(keyHash string) (models.APIKey, error) {
+	k, err := scanAPIKey(r.q.QueryRow(apiKeySelect+" WHERE key_hash = ?", keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return k, repo.ErrNotFound
+	}
+	return k, err
+}
+
+func (r apiKeyRepo) Create(k models.APIKey) error {
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now()
+	}
+	_, err := r.q.Exec(
+		"INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scope, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		k.ID, k.UserID, k.Name, k.Prefix, k.KeyHash, k.Scope, k.CreatedAt,
+	)
+	return conflict(err)
+}
+
+func (r apiKeyRepo) Revoke(userID, id string) error {
+	return mustAffect(r.q.Exec(
+		"UPDATE api_keys SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		time.Now(), id, userID,
+	))
+}
+
+func (r apiKeyRepo) Touch(id string, at time.Time) error {
+	_, err := r.q.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", at, id)
+	return err
+}
+
+func scanAPIKey(row scanner) (models.APIKey, error) {
+	var k models.APIKey
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scope, &k.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return k, err
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return k, nil
+}
@@ -0,0 +1,101 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type deckRepo struct {
+	q storage.Querier
+}
+
+func (r deckRepo) FindOrCreate(gameID, main string, sub *string) (string, error) {
+	var deckID string
+
+	// 先嘗試尋找
+	var err error
+	if sub == nil {
+		err = r.q.QueryRow("SELECT id FROM decks WHERE game_id = ? AND main = ? AND sub IS NULL", gameID, main).Scan(&deckID)
+	} else {
+		err = r.q.QueryRow("SELECT id FROM decks WHERE game_id = ? AND main = ? AND sub = ?", gameID, main, *sub).Scan(&deckID)
+	}
+	if err == nil {
+		return deckID, nil // 找到了
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	// 沒找到，建立新的
+	var subValue sql.NullString
+	if sub != nil {
+		subValue = sql.NullString{String: *sub, Valid: true}
+	}
+	deckID = uuid.New().String()
+	_, err = r.q.Exec(
+		"INSERT INTO decks (id, game_id, main, sub) VALUES (?, ?, ?, ?)",
+		deckID, gameID, main, subValue,
+	)
+	if err != nil {
+		return "", conflict(err)
+	}
+
+	// 同時確保 deck_templates 中有這個牌組（用於顏色顯示）
+	templates := templateRepo{r.q}
+	if _, err := templates.Ensure(gameID, main); err != nil {
+		return "", err
+	}
+	if sub != nil && *sub != "" && *sub != "無" {
+		if _, err := templates.Ensure(gameID, *sub); err != nil {
+			return "", err
+		}
+	}
+
+	return deckID, nil
+}
+
+func (r deckRepo) Rename(oldName, newName string) (int64, int64, error) {
+	result, err := r.q.Exec("UPDATE decks SET main = ? WHERE main = ?", newName, oldName)
+	if err != nil {
+		return 0, 0, err
+	}
+	mainRows, _ := result.RowsAffected()
+
+	result, err = r.q.Exec("UPDATE decks SET sub = ? WHERE sub = ?", newName, oldName)
+	if err != nil {
+		return mainRows, 0, err
+	}
+	subRows, _ := result.RowsAffected()
+	return mainRows, subRows, nil
+}
+
+func (r deckRepo) CountByName(name string) (int, error) {
+	var count int
+	err := r.q.QueryRow("SELECT COUNT(*) FROM decks WHERE main = ? OR sub = ?", name, name).Scan(&count)
+	return count, err
+}
+
+func (r deckRepo) DeleteUnused(ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	result, err := r.q.Exec(`
+		DELETE FROM decks
+		WHERE id IN (`+placeholders+`)
+		  AND NOT EXISTS (SELECT 1 FROM matches WHERE my_deck_id = decks.id OR opp_deck_id = decks.id)
+	`, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
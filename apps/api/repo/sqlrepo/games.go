@@ -0,0 +1,132 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type gameRepo struct {
+	q storage.Querier
+}
+
+const gameColumns = "id, key, name, season_reset_time, season_timezone"
+
+func scanGame(row scanner) (models.Game, error) {
+	var g models.Game
+	err := row.Scan(&g.ID, &g.Key, &g.Name, &g.SeasonResetTime, &g.SeasonTimezone)
+	return g, err
+}
+
+func (r gameRepo) List() ([]models.Game, error) {
+	rows, err := r.q.Query("SELECT " + gameColumns + " FROM games ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := []models.Game{}
+	for rows.Next() {
+		g, err := scanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
+func (r gameRepo) GetByKey(key string) (models.Game, error) {
+	g, err := scanGame(r.q.QueryRow("SELECT "+gameColumns+" FROM games WHERE key = ?", key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return g, repo.ErrNotFound
+	}
+	return g, err
+}
+
+func (r gameRepo) Create(g models.Game) error {
+	g = withGameDefaults(g)
+	_, err := r.q.Exec(
+		"INSERT INTO games ("+gameColumns+") VALUES (?, ?, ?, ?, ?)",
+		g.ID, g.Key, g.Name, g.SeasonResetTime, g.SeasonTimezone,
+	)
+	return conflict(err)
+}
+
+func (r gameRepo) Update(g models.Game) error {
+	return mustAffect(r.q.Exec(
+		"UPDATE games SET name = ?, season_reset_time = ?, season_timezone = ? WHERE key = ?",
+		g.Name, g.SeasonResetTime, g.SeasonTimezone, g.Key,
+	))
+}
+
+// withGameDefaults 沒有設定賽季切換時間 / 時區時使用 00:00 UTC（與 schema 的預設值相同）
+func withGameDefaults(g models.Game) models.Game {
+	if g.SeasonResetTime == "" {
+		g.SeasonResetTime = models.DefaultSeasonResetTime
+	}
+	if g.SeasonTimezone == "" {
+		g.SeasonTimezone = models.DefaultTimezone
+	}
+	return g
+}
+
+type userRepo struct {
+	q storage.Querier
+}
+
+func (r userRepo) DefaultID() (string, error) {
+	var id string
+	err := r.q.QueryRow("SELECT id FROM users ORDER BY created_at, id LIMIT 1").Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", repo.ErrNotFound
+	}
+	return id, err
+}
+
+func (r userRepo) GetByID(id string) (models.User, error) {
+	return r.get("id = ?", id)
+}
+
+func (r userRepo) GetByEmail(email string) (models.User, error) {
+	return r.get("LOWER(email) = LOWER(?)", email)
+}
+
+func (r userRepo) get(where string, arg interface{}) (models.User, error) {
+	var u models.User
+	err := r.q.QueryRow(
+		"SELECT id, email, password_hash, timezone, created_at, updated_at FROM users WHERE "+where, arg,
+	).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Timezone, &u.CreatedAt, &u.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return u, repo.ErrNotFound
+	}
+	return u, err
+}
+
+func (r userRepo) Create(u models.User) error {
+	now := time.Now()
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	if u.UpdatedAt.IsZero() {
+		u.UpdatedAt = now
+	}
+	if u.Timezone == "" {
+		u.Timezone = models.DefaultTimezone
+	}
+	_, err := r.q.Exec(
+		"INSERT INTO users (id, email, password_hash, timezone, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		u.ID, u.Email, u.PasswordHash, u.Timezone, u.CreatedAt, u.UpdatedAt,
+	)
+	return conflict(err)
+}
+
+func (r userRepo) UpdateTimezone(id, timezone string) error {
+	return mustAffect(r.q.Exec(
+		"UPDATE users SET timezone = ?, updated_at = ? WHERE id = ?", timezone, time.Now(), id,
+	))
+}
@@ -0,0 +1,295 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/season"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type matchRepo struct {
+	q storage.Querier
+}
+
+// matchSelect 對局列表的基礎查詢（JOIN 取得完整資訊），別名與 MatchFilter.Conditions 一致
+const matchSelect = `
+	SELECT
+		m.id,
+		m.game_id,
+		g.key as game_key,
+		m.date,
+		m.played_at,
+		m.mode,
+		m.rank_tier,
+		m.rank_level,
+		m.rating_before,
+		m.rating_after,
+		m.rating_delta,
+		m.play_order,
+		m.result,
+		m.note,
+		m.created_at,
+		m.updated_at,
+		s.code as season_code,
+		my_deck.id as my_deck_id,
+		my_deck.main as my_deck_main,
+		my_deck.sub as my_deck_sub,
+		opp_deck.id as opp_deck_id,
+		opp_deck.main as opp_deck_main,
+		opp_deck.sub as opp_deck_sub,
+		acc.id as account_id,
+		acc.name as account_name
+	FROM matches m
+	JOIN games g ON m.game_id = g.id
+	JOIN seasons s ON m.season_id = s.id
+	JOIN decks my_deck ON m.my_deck_id = my_deck.id
+	JOIN decks opp_deck ON m.opp_deck_id = opp_deck.id
+	LEFT JOIN accounts acc ON m.account_id = acc.id
+	WHERE 1=1
+`
+
+func (r matchRepo) List(f models.MatchFilter) ([]models.MatchWithDetails, error) {
+	conditions, args := f.Conditions()
+
+	// 按日期排序（最新在前）
+	rows, err := r.q.Query(matchSelect+conditions+" ORDER BY m.date DESC, m.created_at DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []models.MatchWithDetails{}
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+func (r matchRepo) Get(userID, id string) (models.MatchWithDetails, error) {
+	m, err := scanMatch(r.q.QueryRow(matchSelect+" AND m.id = ? AND m.user_id = ?", id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return m, repo.ErrNotFound
+	}
+	return m, err
+}
+
+func (r matchRepo) Create(m models.Match) error {
+	now := time.Now()
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = now
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = now
+	}
+	rankTier, rankLevel := rankColumns(m.Rank)
+	before, after, delta := ratingColumns(m.Rating)
+
+	_, err := r.q.Exec(`
+		INSERT INTO matches (
+			id, user_id, game_id, season_id, account_id, date, played_at, mode, rank_tier, rank_level,
+			rating_before, rating_after, rating_delta,
+			my_deck_id, opp_deck_id, play_order, result, note,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		m.ID, m.UserID, m.GameID, m.SeasonID, m.AccountID, m.Date, utcTime(m.PlayedAt), m.Mode, rankTier, rankLevel,
+		before, after, delta,
+		m.MyDeckID, m.OppDeckID, m.PlayOrder, m.Result, m.Note,
+		m.CreatedAt, m.UpdatedAt,
+	)
+	return conflict(err)
+}
+
+func (r matchRepo) Update(userID, id string, patch repo.MatchPatch) error {
+	// 動態建立更新語句
+	updates := []string{}
+	args := []interface{}{}
+	set := func(column string, value *string) {
+		if value != nil {
+			updates = append(updates, column+" = ?")
+			args = append(args, *value)
+		}
+	}
+	set("season_id", patch.SeasonID)
+	set("my_deck_id", patch.MyDeckID)
+	set("opp_deck_id", patch.OppDeckID)
+	set("date", patch.Date)
+	if patch.PlayedAt != nil {
+		updates = append(updates, "played_at = ?")
+		args = append(args, *utcTime(patch.PlayedAt))
+	}
+	set("mode", patch.Mode)
+	if patch.Rank != nil {
+		tier, level := rankColumns(patch.Rank)
+		updates = append(updates, "rank_tier = ?", "rank_level = ?")
+		args = append(args, tier, level)
+	}
+	if patch.Rating != nil {
+		before, after, delta := ratingColumns(patch.Rating)
+		updates = append(updates, "rating_before = ?", "rating_after = ?", "rating_delta = ?")
+		args = append(args, before, after, delta)
+	}
+	set("play_order", patch.PlayOrder)
+	set("result", patch.Result)
+	set("note", patch.Note)
+
+	updates = append(updates, "updated_at = ?")
+	args = append(args, time.Now(), id, userID)
+
+	return mustAffect(r.q.Exec("UPDATE matches SET "+strings.Join(updates, ", ")+" WHERE id = ? AND user_id = ?", args...))
+}
+
+func (r matchRepo) Delete(userID, id string) error {
+	return mustAffect(r.q.Exec("DELETE FROM matches WHERE id = ? AND user_id = ?", id, userID))
+}
+
+func (r matchRepo) Relocalize(userID string, loc *time.Location) (int64, error) {
+	rows, err := r.q.Query("SELECT id, date, played_at FROM matches WHERE user_id = ? AND played_at IS NOT NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+	// 先讀完再更新（SQLite 的 transaction 只有一個連線）
+	changed := map[string]string{}
+	for rows.Next() {
+		var id, date string
+		var playedAt time.Time
+		if err := rows.Scan(&id, &date, &playedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if local := season.LocalDate(playedAt, loc); !strings.HasPrefix(date, local) {
+			changed[id] = local
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for id, date := range changed {
+		if _, err := r.q.Exec("UPDATE matches SET date = ? WHERE id = ? AND user_id = ?", date, id, userID); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(changed)), nil
+}
+
+// rankColumns rank_tier / rank_level 要寫入的值；沒有階級（nil 或 Tier 為空）時皆為 NULL
+func rankColumns(rank *models.Rank) (tier *string, level *int) {
+	if rank == nil || rank.Tier == "" {
+		return nil, nil
+	}
+	return &rank.Tier, rank.Level
+}
+
+// ratingColumns rating_before / rating_after / rating_delta 要寫入的值；沒有積分時皆為 NULL
+func ratingColumns(rating *models.Rating) (before, after, delta *int) {
+	if rating == nil {
+		return nil, nil, nil
+	}
+	return rating.Before, rating.After, rating.Delta
+}
+
+// utcTime 以 UTC 寫入時間，讓不同時區送來的 played_at 可以直接比較
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// scanner *sql.Row 與 *sql.Rows 共同的 Scan
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMatch 解析 matchSelect 的一列
+func scanMatch(row scanner) (models.MatchWithDetails, error) {
+	var m models.MatchWithDetails
+	var myDeckSub, oppDeckSub, note, accountID, accountName, rankTier sql.NullString
+	var rankLevel, ratingBefore, ratingAfter, ratingDelta sql.NullInt64
+	var playedAt sql.NullTime
+
+	err := row.Scan(
+		&m.ID,
+		&m.GameID,
+		&m.GameKey,
+		&m.Date,
+		&playedAt,
+		&m.Mode,
+		&rankTier,
+		&rankLevel,
+		&ratingBefore,
+		&ratingAfter,
+		&ratingDelta,
+		&m.PlayOrder,
+		&m.Result,
+		&note,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.SeasonCode,
+		&m.MyDeck.ID,
+		&m.MyDeck.Main,
+		&myDeckSub,
+		&m.OppDeck.ID,
+		&m.OppDeck.Main,
+		&oppDeckSub,
+		&accountID,
+		&accountName,
+	)
+	if err != nil {
+		return m, err
+	}
+	// DATE 欄位可能被 driver 讀成完整時間（與 seasons 的 dateOnly 相同），只保留 YYYY-MM-DD
+	if len(m.Date) > len(season.DateLayout) {
+		m.Date = m.Date[:len(season.DateLayout)]
+	}
+
+	// 處理 nullable 欄位
+	if myDeckSub.Valid {
+		m.MyDeck.Sub = &myDeckSub.String
+	}
+	if oppDeckSub.Valid {
+		m.OppDeck.Sub = &oppDeckSub.String
+	}
+	if note.Valid {
+		m.Note = &note.String
+	}
+	if playedAt.Valid {
+		m.PlayedAt = &playedAt.Time
+	}
+	if rankTier.Valid {
+		m.RankTier = &rankTier.String
+	}
+	if rankLevel.Valid {
+		level := int(rankLevel.Int64)
+		m.RankLevel = &level
+	}
+	m.Rank = models.RankCode(m.RankTier, m.RankLevel)
+	if rating := (models.Rating{Before: nullInt(ratingBefore), After: nullInt(ratingAfter), Delta: nullInt(ratingDelta)}); !rating.Empty() {
+		m.Rating = &rating
+	}
+	if accountID.Valid {
+		m.Account = &models.AccountInfo{ID: accountID.String, Name: accountName.String}
+	}
+	return m, nil
+}
+
+// nullInt sql.NullInt64 → *int
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
@@ -0,0 +1,118 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type seasonRepo struct {
+	q storage.Querier
+}
+
+const seasonColumns = "id, game_id, code, start_date, end_date"
+
+func (r seasonRepo) List(gameID string) ([]models.Season, error) {
+	rows, err := r.q.Query(
+		"SELECT "+seasonColumns+" FROM seasons WHERE game_id = ? ORDER BY start_date IS NULL, start_date DESC, code DESC",
+		gameID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := []models.Season{}
+	for rows.Next() {
+		s, err := scanSeason(rows)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, s)
+	}
+	return seasons, rows.Err()
+}
+
+func (r seasonRepo) GetByCode(gameID, code string) (models.Season, error) {
+	return r.getOne("SELECT "+seasonColumns+" FROM seasons WHERE code = ? AND game_id = ?", code, gameID)
+}
+
+func (r seasonRepo) GetByDate(gameID, date string) (models.Season, error) {
+	return r.getOne(
+		"SELECT "+seasonColumns+" FROM seasons WHERE game_id = ? AND start_date <= ? AND end_date >= ? ORDER BY start_date DESC LIMIT 1",
+		gameID, date, date,
+	)
+}
+
+func (r seasonRepo) getOne(query string, args ...interface{}) (models.Season, error) {
+	s, err := scanSeason(r.q.QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, repo.ErrNotFound
+	}
+	return s, err
+}
+
+func scanSeason(row scanner) (models.Season, error) {
+	var s models.Season
+	var startDate, endDate sql.NullString
+	if err := row.Scan(&s.ID, &s.GameID, &s.Code, &startDate, &endDate); err != nil {
+		return s, err
+	}
+	s.StartDate = dateOnly(startDate)
+	s.EndDate = dateOnly(endDate)
+	return s, nil
+}
+
+// dateOnly DATE 欄位可能被 driver 讀成完整時間（e.g. "2026-01-01T00:00:00Z"），只保留 YYYY-MM-DD
+func dateOnly(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	d := v.String
+	if len(d) > len("2006-01-02") {
+		d = d[:len("2006-01-02")]
+	}
+	return &d
+}
+
+func (r seasonRepo) Create(s models.Season) error {
+	_, err := r.q.Exec(
+		"INSERT INTO seasons (id, game_id, code, start_date, end_date) VALUES (?, ?, ?, ?, ?)",
+		s.ID, s.GameID, s.Code, s.StartDate, s.EndDate,
+	)
+	return conflict(err)
+}
+
+func (r seasonRepo) Update(s models.Season) error {
+	return mustAffect(r.q.Exec(
+		"UPDATE seasons SET start_date = ?, end_date = ? WHERE game_id = ? AND code = ?",
+		s.StartDate, s.EndDate, s.GameID, s.Code,
+	))
+}
+
+func (r seasonRepo) GetOrCreate(s models.Season) (string, error) {
+	existing, err := r.GetByCode(s.GameID, s.Code)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return "", err
+	}
+
+	// Not found: auto-create so users can start recording immediately.
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if err := r.Create(s); err != nil {
+		// If another request created it concurrently, just re-read.
+		if existing, readErr := r.GetByCode(s.GameID, s.Code); readErr == nil {
+			return existing.ID, nil
+		}
+		return "", err
+	}
+	return s.ID, nil
+}
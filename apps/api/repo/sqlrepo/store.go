@@ -0,0 +1,73 @@
+// Package sqlrepo 以 database/sql 實作 repo 介面（SQLite 與 PostgreSQL 共用，經由 storage 套件）。
+package sqlrepo
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/harvc/duellog/apps/api/repo"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+var _ repo.Store = (*Store)(nil)
+
+// Store 實作 repo.Store
+type Store struct {
+	db *storage.DB     // transaction 內為 nil
+	q  storage.Querier // db 本身或目前的 transaction
+}
+
+// New 建立以 db 為後端的 Store
+func New(db *storage.DB) *Store {
+	return &Store{db: db, q: db}
+}
+
+func (s *Store) Matches() repo.MatchRepository      { return matchRepo{s.q} }
+func (s *Store) Decks() repo.DeckRepository         { return deckRepo{s.q} }
+func (s *Store) Seasons() repo.SeasonRepository     { return seasonRepo{s.q} }
+func (s *Store) Templates() repo.TemplateRepository { return templateRepo{s.q} }
+func (s *Store) Games() repo.GameRepository         { return gameRepo{s.q} }
+func (s *Store) Users() repo.UserRepository         { return userRepo{s.q} }
+func (s *Store) APIKeys() repo.APIKeyRepository     { return apiKeyRepo{s.q} }
+func (s *Store) Accounts() repo.AccountRepository   { return accountRepo{s.q} }
+
+// InTx 在單一 transaction 內執行 fn；已在 transaction 內時直接沿用
+func (s *Store) InTx(fn func(tx repo.Store) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&Store{q: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// mustAffect 包裝 Exec 的結果：沒有任何資料列受影響時回傳 repo.ErrNotFound
+func mustAffect(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+// conflict 將 UNIQUE 衝突包裝成 repo.ErrConflict，其餘錯誤原樣回傳
+func conflict(err error) error {
+	if storage.IsUniqueViolation(err) {
+		return fmt.Errorf("%w: %v", repo.ErrConflict, err)
+	}
+	return err
+}
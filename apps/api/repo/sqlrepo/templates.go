@@ -0,0 +1,128 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+type templateRepo struct {
+	q storage.Querier
+}
+
+func (r templateRepo) List(gameID, deckType string) ([]models.DeckTemplate, error) {
+	query := `
+		SELECT id, game_id, main as name, theme, deck_type, created_at
+		FROM deck_templates
+		WHERE 1=1
+	`
+	var args []interface{}
+
+	if gameID != "" {
+		query += " AND game_id = ?"
+		args = append(args, gameID)
+	}
+	if deckType != "" {
+		query += " AND deck_type = ?"
+		args = append(args, deckType)
+	}
+
+	rows, err := r.q.Query(query+" ORDER BY deck_type ASC, name ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.DeckTemplate{}
+	for rows.Next() {
+		var t models.DeckTemplate
+		var createdAt sql.NullTime
+		if err := rows.Scan(&t.ID, &t.GameID, &t.Name, &t.Theme, &t.DeckType, &createdAt); err != nil {
+			return nil, err
+		}
+		if createdAt.Valid {
+			t.CreatedAt = createdAt.Time
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r templateRepo) Exists(gameID, name, deckType string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM deck_templates WHERE main = ? AND deck_type = ?"
+	args := []interface{}{name, deckType}
+	if gameID != "" {
+		query += " AND game_id = ?"
+		args = append(args, gameID)
+	}
+
+	var exists bool
+	err := r.q.QueryRow(query+")", args...).Scan(&exists)
+	return exists, err
+}
+
+func (r templateRepo) Create(t models.DeckTemplate) error {
+	_, err := r.q.Exec(`
+		INSERT INTO deck_templates (id, game_id, main, theme, deck_type, created_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, t.ID, t.GameID, t.Name, t.Theme, t.DeckType)
+	return conflict(err)
+}
+
+func (r templateRepo) Update(gameID, id, name, theme string) error {
+	// 建構動態更新語句
+	updates := []string{}
+	args := []interface{}{}
+
+	if name != "" {
+		updates = append(updates, "main = ?")
+		args = append(args, name)
+	}
+	if theme != "" {
+		updates = append(updates, "theme = ?")
+		args = append(args, theme)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	args = append(args, id, gameID)
+	return mustAffect(r.q.Exec("UPDATE deck_templates SET "+strings.Join(updates, ", ")+" WHERE id = ? AND game_id = ?", args...))
+}
+
+func (r templateRepo) Delete(gameID, id string) error {
+	return mustAffect(r.q.Exec("DELETE FROM deck_templates WHERE id = ? AND game_id = ?", id, gameID))
+}
+
+func (r templateRepo) Ensure(gameID, name string) (bool, error) {
+	// 檢查是否已存在
+	var exists bool
+	err := r.q.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM deck_templates WHERE game_id = ? AND main = ? AND deck_type = 'main')",
+		gameID, name,
+	).Scan(&exists)
+	if err != nil || exists {
+		return false, err
+	}
+
+	// 不存在，建立新的模板（預設主題為「無」= 灰色）
+	err = r.Create(models.DeckTemplate{
+		ID:       "tpl-auto-" + uuid.New().String()[:8],
+		GameID:   gameID,
+		Name:     name,
+		Theme:    "無",
+		DeckType: "main",
+	})
+	return err == nil, err
+}
+
+func (r templateRepo) Rename(oldName, newName string) (int64, error) {
+	result, err := r.q.Exec("UPDATE deck_templates SET main = ? WHERE main = ?", newName, oldName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,82 @@
+package rules
+
+import "strings"
+
+// 顯示名稱的語系
+const (
+	LangZhTW = "zh-TW" // 預設
+	LangEn   = "en"
+)
+
+// Langs 支援的語系（第一個為預設值）
+var Langs = []string{LangZhTW, LangEn}
+
+// Labeler 由規則提供代碼的顯示名稱（選用）；沒有實作或回傳 false 時改用共用的名稱
+type Labeler interface {
+	Label(field, code, lang string) (string, bool)
+}
+
+// commonLabels 各遊戲共用的顯示名稱：field → code → lang → label
+var commonLabels = map[string]map[string]map[string]string{
+	FieldPlayOrder: {
+		PlayOrderFirst:  {LangZhTW: "先攻", LangEn: "First"},
+		PlayOrderSecond: {LangZhTW: "後攻", LangEn: "Second"},
+	},
+	FieldResult: {
+		ResultWin:  {LangZhTW: "勝", LangEn: "Win"},
+		ResultLoss: {LangZhTW: "敗", LangEn: "Loss"},
+		ResultDraw: {LangZhTW: "平手", LangEn: "Draw"},
+	},
+}
+
+// Label code 在 lang 的顯示名稱；沒有對應的名稱時回傳 code 本身
+func Label(r GameRules, field, code, lang string) string {
+	if l, ok := label(r, field, code, lang); ok {
+		return l
+	}
+	return code
+}
+
+func label(r GameRules, field, code, lang string) (string, bool) {
+	if labeler, ok := r.(Labeler); ok {
+		if l, ok := labeler.Label(field, code, lang); ok {
+			return l, true
+		}
+	}
+	l, ok := commonLabels[field][code][lang]
+	return l, ok
+}
+
+// Labels 規則中所有代碼在 lang 的顯示名稱：field → code → label
+func Labels(r GameRules, lang string) map[string]map[string]string {
+	fields := map[string][]string{
+		FieldMode:      r.Modes(),
+		FieldRank:      r.Ranks(),
+		FieldPlayOrder: r.PlayOrders(),
+		FieldResult:    Results,
+	}
+	labels := make(map[string]map[string]string, len(fields))
+	for field, codes := range fields {
+		labels[field] = make(map[string]string, len(codes))
+		for _, code := range codes {
+			labels[field][code] = Label(r, field, code, lang)
+		}
+	}
+	return labels
+}
+
+// ParseLang 由 ?lang= 或 Accept-Language 決定語系："en" 開頭為英文，其餘為預設語系
+func ParseLang(value string) string {
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.ToLower(strings.TrimSpace(strings.SplitN(tag, ";", 2)[0]))
+		switch {
+		case tag == "":
+			continue
+		case strings.HasPrefix(tag, "en"):
+			return LangEn
+		default:
+			return LangZhTW
+		}
+	}
+	return LangZhTW
+}
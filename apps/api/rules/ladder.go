@@ -0,0 +1,33 @@
+package rules
+
+// LevelRule 階級表中一階的升降規則（天梯以積分計算，每勝 +1 分，平手不變）
+type LevelRule struct {
+	WinsToPromote int  // 升到下一階需要的積分
+	LossPenalty   bool // 敗場扣 1 分（不會低於 0）
+	DemoteAfter   int  // 積分為 0 時再連敗幾場降一階（降階後積分歸 0）；0 表示不會降階
+}
+
+// Ladder 由規則提供各階的升降規則（選用）；沒有實作的遊戲無法模擬天梯
+type Ladder interface {
+	LevelRule(rank string) (LevelRule, bool)
+}
+
+// LadderFor 取得 gameKey 的天梯規則；沒有階級表或沒有升降規則時 ok 為 false
+func LadderFor(gameKey string) (Ladder, bool) {
+	r := For(gameKey)
+	if len(r.Ranks()) == 0 {
+		return nil, false
+	}
+	l, ok := r.(Ladder)
+	return l, ok
+}
+
+// RankCode 驗證階級並回傳代碼（接受代碼或任一語系的顯示名稱，e.g. "大師 V" → "master-5"）；
+// 與 Rank 不同，不看模式，且遊戲必須有階級表
+func RankCode(r GameRules, rank string) (string, error) {
+	ranks := r.Ranks()
+	if code, ok := lookup(r, FieldRank, rank, ranks); ok {
+		return code, nil
+	}
+	return "", &ValidationError{Field: FieldRank, Value: rank, Allowed: ranks}
+}
@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Master Duel 的對局模式
+const (
+	ModeRanked = "Ranked" // 天梯（記錄階級）
+	ModeRating = "Rating" // 積分賽
+	ModeDC     = "DC"     // Duelist Cup
+)
+
+// MasterDuel Yu-Gi-Oh! Master Duel 的規則：銅 V ～ 大師 I 共 30 階，只有 Ranked 記錄階級，Rating / DC 記錄積分
+type MasterDuel struct{}
+
+// masterDuelTiers 由低到高；階級代碼為 "<tier>-<level>"，level 5 ～ 1 對應 V ～ I，e.g. "gold-4" = 金 IV
+var masterDuelTiers = []string{"bronze", "silver", "gold", "platinum", "diamond", "master"}
+
+// masterDuelLevels 每個 tier 的階數（由低到高為 5 ～ 1）
+const masterDuelLevels = 5
+
+var masterDuelLabels = map[string]map[string]map[string]string{
+	FieldMode: {
+		ModeRanked: {LangZhTW: "天梯", LangEn: "Ranked"},
+		ModeRating: {LangZhTW: "積分賽", LangEn: "Rating"},
+		ModeDC:     {LangZhTW: "決鬥者盃", LangEn: "Duelist Cup"},
+	},
+	FieldRank: {
+		"bronze":   {LangZhTW: "銅", LangEn: "Bronze"},
+		"silver":   {LangZhTW: "銀", LangEn: "Silver"},
+		"gold":     {LangZhTW: "金", LangEn: "Gold"},
+		"platinum": {LangZhTW: "白金", LangEn: "Platinum"},
+		"diamond":  {LangZhTW: "鑽石", LangEn: "Diamond"},
+		"master":   {LangZhTW: "大師", LangEn: "Master"},
+	},
+}
+
+var romanLevels = []string{"", "I", "II", "III", "IV", "V"}
+
+func (MasterDuel) Modes() []string { return []string{ModeRanked, ModeRating, ModeDC} }
+
+func (MasterDuel) Ranks() []string {
+	ranks := make([]string, 0, len(masterDuelTiers)*masterDuelLevels)
+	for _, tier := range masterDuelTiers {
+		for level := masterDuelLevels; level >= 1; level-- {
+			ranks = append(ranks, fmt.Sprintf("%s-%d", tier, level))
+		}
+	}
+	return ranks
+}
+
+func (MasterDuel) PlayOrders() []string { return []string{PlayOrderFirst, PlayOrderSecond} }
+
+func (MasterDuel) RankApplies(mode string) bool { return mode == ModeRanked }
+
+// RatingApplies 積分賽與決鬥者盃記錄積分
+func (MasterDuel) RatingApplies(mode string) bool { return mode == ModeRating || mode == ModeDC }
+
+// Label 模式與階級的顯示名稱（階級為 "<tier 名稱> <羅馬數字>"，e.g. "金 IV" / "Gold IV"）
+func (MasterDuel) Label(field, code, lang string) (string, bool) {
+	if field != FieldRank {
+		l, ok := masterDuelLabels[field][code][lang]
+		return l, ok
+	}
+	tier, levelStr, found := strings.Cut(code, "-")
+	level, err := strconv.Atoi(levelStr)
+	if !found || err != nil || level < 1 || level > masterDuelLevels {
+		return "", false
+	}
+	name, ok := masterDuelLabels[FieldRank][tier][lang]
+	if !ok {
+		return "", false
+	}
+	return name + " " + romanLevels[level], true
+}
+
+// masterDuelLadder 各 tier 的升降規則：銅不扣分，銀、金會扣分但不降階，
+// 白金以上積分為 0 時連敗 3 場降一階；各 tier 的 V 階不會降到下一個 tier
+var masterDuelLadder = map[string]LevelRule{
+	"bronze":   {WinsToPromote: 3},
+	"silver":   {WinsToPromote: 4, LossPenalty: true},
+	"gold":     {WinsToPromote: 5, LossPenalty: true},
+	"platinum": {WinsToPromote: 5, LossPenalty: true, DemoteAfter: 3},
+	"diamond":  {WinsToPromote: 5, LossPenalty: true, DemoteAfter: 3},
+	"master":   {WinsToPromote: 5, LossPenalty: true, DemoteAfter: 3},
+}
+
+// LevelRule 天梯一階的升降規則
+func (MasterDuel) LevelRule(rank string) (LevelRule, bool) {
+	tier, levelStr, _ := strings.Cut(rank, "-")
+	rule, ok := masterDuelLadder[tier]
+	if !ok {
+		return LevelRule{}, false
+	}
+	if levelStr == strconv.Itoa(masterDuelLevels) {
+		rule.DemoteAfter = 0
+	}
+	return rule, true
+}
+
+func init() {
+	Register("master_duel", MasterDuel{})
+}
@@ -0,0 +1,76 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/harvc/duellog/apps/api/models"
+)
+
+// Rater 由規則指定哪些模式記錄積分（選用）；沒有實作的遊戲所有模式都可以記錄積分
+type Rater interface {
+	RatingApplies(mode string) bool
+}
+
+// RatingApplies 該模式是否記錄積分
+func RatingApplies(r GameRules, mode string) bool {
+	if rater, ok := r.(Rater); ok {
+		return rater.RatingApplies(mode)
+	}
+	return true
+}
+
+// RatingModes 記錄積分的模式
+func RatingModes(r GameRules) []string {
+	modes := []string{}
+	for _, mode := range r.Modes() {
+		if RatingApplies(r, mode) {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// Rating 驗證 mode 下的積分並補齊可推算的欄位，回傳要存入的值：
+//   - 沒有填（nil 或三個欄位皆為 nil）時回傳 nil
+//   - 該模式不記錄積分時回傳錯誤
+//   - before / after 不可為負數；三者都有時必須滿足 after = before + delta，任兩者可推算第三者
+func Rating(r GameRules, mode string, rating *models.Rating) (*models.Rating, error) {
+	if rating == nil || rating.Empty() {
+		return nil, nil
+	}
+	if !RatingApplies(r, mode) {
+		return nil, &ValidationError{
+			Field:   FieldRating,
+			Value:   mode,
+			Allowed: RatingModes(r),
+			Message: fmt.Sprintf("%s 模式不記錄積分", mode),
+		}
+	}
+
+	out := models.Rating{Before: rating.Before, After: rating.After, Delta: rating.Delta}
+	switch {
+	case out.Before != nil && out.After != nil:
+		delta := *out.After - *out.Before
+		if out.Delta != nil && *out.Delta != delta {
+			return nil, ratingError(fmt.Sprintf("rating.delta %d 與 after - before = %d 不符", *out.Delta, delta))
+		}
+		out.Delta = &delta
+	case out.Before != nil && out.Delta != nil:
+		after := *out.Before + *out.Delta
+		out.After = &after
+	case out.After != nil && out.Delta != nil:
+		before := *out.After - *out.Delta
+		out.Before = &before
+	}
+	if out.Before != nil && *out.Before < 0 {
+		return nil, ratingError(fmt.Sprintf("rating.before 不可為負數（%d）", *out.Before))
+	}
+	if out.After != nil && *out.After < 0 {
+		return nil, ratingError(fmt.Sprintf("rating.after 不可為負數（%d）", *out.After))
+	}
+	return &out, nil
+}
+
+func ratingError(message string) error {
+	return &ValidationError{Field: FieldRating, Message: message}
+}
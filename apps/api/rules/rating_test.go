@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/harvc/duellog/apps/api/models"
+)
+
+func ptr(n int) *int { return &n }
+
+func TestRating(t *testing.T) {
+	md := For("master_duel")
+	tests := []struct {
+		name   string
+		mode   string
+		rating *models.Rating
+		want   *models.Rating // nil 且 fails 為 false 表示不記錄積分
+		fails  bool
+	}{
+		{"not filled", ModeDC, nil, nil, false},
+		{"all fields nil", ModeDC, &models.Rating{}, nil, false},
+		{"before + after", ModeDC, &models.Rating{Before: ptr(1500), After: ptr(1488)}, &models.Rating{Before: ptr(1500), After: ptr(1488), Delta: ptr(-12)}, false},
+		{"before + delta", ModeDC, &models.Rating{Before: ptr(1500), Delta: ptr(15)}, &models.Rating{Before: ptr(1500), After: ptr(1515), Delta: ptr(15)}, false},
+		{"after + delta", ModeRating, &models.Rating{After: ptr(1515), Delta: ptr(15)}, &models.Rating{Before: ptr(1500), After: ptr(1515), Delta: ptr(15)}, false},
+		{"all three consistent", ModeDC, &models.Rating{Before: ptr(1500), After: ptr(1515), Delta: ptr(15)}, &models.Rating{Before: ptr(1500), After: ptr(1515), Delta: ptr(15)}, false},
+		{"only delta", ModeDC, &models.Rating{Delta: ptr(-8)}, &models.Rating{Delta: ptr(-8)}, false},
+		{"mismatched delta", ModeDC, &models.Rating{Before: ptr(1500), After: ptr(1515), Delta: ptr(10)}, nil, true},
+		{"negative before", ModeDC, &models.Rating{Before: ptr(-1)}, nil, true},
+		{"delta below zero", ModeDC, &models.Rating{Before: ptr(5), Delta: ptr(-10)}, nil, true},
+		{"mode without rating", ModeRanked, &models.Rating{Before: ptr(1500), After: ptr(1515)}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Rating(md, tt.mode, tt.rating)
+		if tt.fails {
+			var ve *ValidationError
+			if !errors.As(err, &ve) || ve.Field != FieldRating {
+				t.Errorf("%s: err = %v, want a rating ValidationError", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Rating = %s, want %s", tt.name, formatRating(got), formatRating(tt.want))
+		}
+	}
+
+	// 沒有 Rater 的遊戲所有模式都可以記錄積分
+	if got, err := Rating(Freeform{}, "Casual", &models.Rating{Before: ptr(10), After: ptr(12)}); err != nil || *got.Delta != 2 {
+		t.Errorf("freeform rating = %v, %v; want delta 2", got, err)
+	}
+}
+
+func formatRating(r *models.Rating) string {
+	if r == nil {
+		return "nil"
+	}
+	field := func(p *int) interface{} {
+		if p == nil {
+			return "nil"
+		}
+		return *p
+	}
+	return fmt.Sprintf("{before %v, after %v, delta %v}", field(r.Before), field(r.After), field(r.Delta))
+}
@@ -0,0 +1,203 @@
+// Package rules 定義各款遊戲的對局規則（階級、模式、先後攻），依 game key 註冊。
+//
+// matches 表不再以 CHECK 寫死這些值；新增對局 / 更新對局時由 handler 依遊戲規則驗證，
+// 前端則透過 GET /games/:key/rules 取得選項。新增遊戲時只需在這裡註冊規則，不必修改 schema。
+//
+// 資料庫與 API 一律使用與語系無關的代碼（e.g. "first"、"gold-4"；階級存成 rank_tier / rank_level），顯示名稱見 labels.go；
+// 驗證時也接受任一語系的顯示名稱（e.g. "先攻"、"金 IV"），並轉成代碼。
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/harvc/duellog/apps/api/models"
+)
+
+// 先後攻的代碼（各遊戲共用）
+const (
+	PlayOrderFirst  = "first"
+	PlayOrderSecond = "second"
+)
+
+// 對局結果的代碼（各遊戲共用）
+const (
+	ResultWin  = "W"
+	ResultLoss = "L"
+	ResultDraw = "D" // 平手（時間到、同時敗北等）
+)
+
+// Results 對局結果的可用值
+var Results = []string{ResultWin, ResultLoss, ResultDraw}
+
+// 驗證與顯示名稱使用的欄位名稱
+const (
+	FieldMode      = "mode"
+	FieldRank      = "rank"
+	FieldPlayOrder = "playOrder"
+	FieldResult    = "result"
+	FieldRating    = "rating"
+)
+
+// GameRules 一款遊戲的對局規則（皆為代碼）
+type GameRules interface {
+	// Modes 對局模式，第一個為預設值
+	Modes() []string
+	// Ranks 階級代碼（"<tier>-<level>"），由低到高；沒有階級制度的遊戲回傳 nil
+	Ranks() []string
+	// PlayOrders 先後攻的可用值
+	PlayOrders() []string
+	// RankApplies 該模式是否記錄階級
+	RankApplies(mode string) bool
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]GameRules{}
+)
+
+// Register 註冊 gameKey 的規則；重複註冊會覆蓋
+func Register(gameKey string, r GameRules) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[gameKey] = r
+}
+
+// For 取得 gameKey 的規則；沒有註冊規則的遊戲回傳 Freeform（不限制任何值）
+func For(gameKey string) GameRules {
+	mu.RLock()
+	defer mu.RUnlock()
+	if r, ok := registry[gameKey]; ok {
+		return r
+	}
+	return Freeform{}
+}
+
+// Registered gameKey 是否有註冊規則
+func Registered(gameKey string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := registry[gameKey]
+	return ok
+}
+
+// Freeform 沒有註冊規則的遊戲：模式與先後攻只要求非空，階級照原樣記錄
+type Freeform struct{}
+
+func (Freeform) Modes() []string              { return nil }
+func (Freeform) Ranks() []string              { return nil }
+func (Freeform) PlayOrders() []string         { return nil }
+func (Freeform) RankApplies(mode string) bool { return true }
+
+// ValidationError 對局欄位不符合遊戲規則
+type ValidationError struct {
+	Field   string   // "mode" | "rank" | "playOrder" | "result" | "rating"
+	Value   string   // 收到的值
+	Allowed []string // 可用值（代碼）
+	Message string   // 自訂的錯誤訊息（選用；空字串時依 Allowed 產生）
+}
+
+func (e *ValidationError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if len(e.Allowed) == 0 {
+		return e.Field + " 不可為空"
+	}
+	return fmt.Sprintf("%s %q 不是有效的值（可用：%s）", e.Field, e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// DefaultMode 規則的預設模式；沒有限制模式時為空字串
+func DefaultMode(r GameRules) string {
+	if modes := r.Modes(); len(modes) > 0 {
+		return modes[0]
+	}
+	return ""
+}
+
+// RankModes 記錄階級的模式
+func RankModes(r GameRules) []string {
+	modes := []string{}
+	for _, mode := range r.Modes() {
+		if r.RankApplies(mode) {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// Mode 驗證模式並回傳代碼
+func Mode(r GameRules, mode string) (string, error) {
+	return oneOf(r, FieldMode, mode, r.Modes())
+}
+
+// PlayOrder 驗證先後攻並回傳代碼
+func PlayOrder(r GameRules, playOrder string) (string, error) {
+	return oneOf(r, FieldPlayOrder, playOrder, r.PlayOrders())
+}
+
+// Result 驗證對局結果並回傳代碼（W / L / D，也接受 "勝"、"Draw" 等顯示名稱）
+func Result(r GameRules, result string) (string, error) {
+	return oneOf(r, FieldResult, result, Results)
+}
+
+// Rank 驗證 mode 下的階級並回傳要存入的 tier / level：
+// 該模式不記錄階級時回傳 nil（不論輸入為何）；否則必須是階級表中的一階，
+// 接受代碼或任一語系的顯示名稱（比對時忽略空白與大小寫，"金IV" 視同 "金 IV"）。
+func Rank(r GameRules, mode, rank string) (*models.Rank, error) {
+	if !r.RankApplies(mode) {
+		return nil, nil
+	}
+	ranks := r.Ranks()
+	if len(ranks) == 0 {
+		// 沒有階級制度：照原樣記錄在 tier，沒填時不記錄
+		if rank = strings.TrimSpace(rank); rank == "" {
+			return nil, nil
+		}
+		return &models.Rank{Tier: rank}, nil
+	}
+	if code, ok := lookup(r, FieldRank, rank, ranks); ok {
+		parsed := models.ParseRankCode(code)
+		return &parsed, nil
+	}
+	return nil, &ValidationError{Field: FieldRank, Value: rank, Allowed: ranks}
+}
+
+// oneOf value 必須非空，且 allowed 不為空時必須是其中之一（代碼或顯示名稱）；回傳代碼
+func oneOf(r GameRules, field, value string, allowed []string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", &ValidationError{Field: field, Value: value, Allowed: allowed}
+	}
+	if len(allowed) == 0 {
+		return value, nil
+	}
+	if code, ok := lookup(r, field, value, allowed); ok {
+		return code, nil
+	}
+	return "", &ValidationError{Field: field, Value: value, Allowed: allowed}
+}
+
+// lookup 在 codes 中找出 value 對應的代碼：先比對代碼，再比對各語系的顯示名稱
+func lookup(r GameRules, field, value string, codes []string) (string, bool) {
+	want := compact(value)
+	for _, code := range codes {
+		if compact(code) == want {
+			return code, true
+		}
+	}
+	for _, code := range codes {
+		for _, lang := range Langs {
+			if label, ok := label(r, field, code, lang); ok && compact(label) == want {
+				return code, true
+			}
+		}
+	}
+	return "", false
+}
+
+// compact 去掉空白並轉成小寫，讓 "金IV" / "金 IV"、"first" / "First" 視為相同
+func compact(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), ""))
+}
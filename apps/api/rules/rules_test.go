@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/harvc/duellog/apps/api/models"
+)
+
+func TestMasterDuelRanks(t *testing.T) {
+	ranks := For("master_duel").Ranks()
+	if len(ranks) != 30 || ranks[0] != "bronze-5" || ranks[4] != "bronze-1" || ranks[5] != "silver-5" || ranks[29] != "master-1" {
+		t.Errorf("ranks = %v, want bronze-5 … master-1 (30)", ranks)
+	}
+
+	// 各 tier 的 V 階不會降到下一個 tier
+	md := MasterDuel{}
+	tests := []struct {
+		rank string
+		want LevelRule
+	}{
+		{"bronze-3", LevelRule{WinsToPromote: 3}},
+		{"gold-2", LevelRule{WinsToPromote: 5, LossPenalty: true}},
+		{"platinum-5", LevelRule{WinsToPromote: 5, LossPenalty: true}},
+		{"platinum-4", LevelRule{WinsToPromote: 5, LossPenalty: true, DemoteAfter: 3}},
+	}
+	for _, tt := range tests {
+		if got, ok := md.LevelRule(tt.rank); !ok || got != tt.want {
+			t.Errorf("LevelRule(%s) = %+v, %v; want %+v", tt.rank, got, ok, tt.want)
+		}
+	}
+	if _, ok := md.LevelRule("legend-1"); ok {
+		t.Error("LevelRule accepted an unknown tier")
+	}
+}
+
+func TestRank(t *testing.T) {
+	md := For("master_duel")
+	tests := []struct {
+		mode, rank string
+		want       string // 階級代碼；"" 表示不記錄
+	}{
+		{ModeRanked, "gold-4", "gold-4"},
+		{ModeRanked, "金 IV", "gold-4"},
+		{ModeRanked, "金IV", "gold-4"},
+		{ModeRanked, " Gold iv ", "gold-4"},
+		{ModeRanked, "大師 I", "master-1"},
+		{ModeRanked, "Platinum V", "platinum-5"},
+		{ModeDC, "gold-4", ""}, // DC 不記錄階級
+		{ModeRating, "anything", ""},
+	}
+	for _, tt := range tests {
+		got, err := Rank(md, tt.mode, tt.rank)
+		if err != nil {
+			t.Errorf("Rank(%s, %q): %v", tt.mode, tt.rank, err)
+			continue
+		}
+		if (got == nil) != (tt.want == "") || (got != nil && got.Code() != tt.want) {
+			t.Errorf("Rank(%s, %q) = %v, want %q", tt.mode, tt.rank, got, tt.want)
+		}
+	}
+
+	for _, rank := range []string{"", "gold", "gold-6", "gold-0", "金 VI", "legend-1"} {
+		var ve *ValidationError
+		if _, err := Rank(md, ModeRanked, rank); !errors.As(err, &ve) || ve.Field != FieldRank || len(ve.Allowed) != 30 {
+			t.Errorf("Rank(Ranked, %q) = %v, want a rank ValidationError", rank, err)
+		}
+	}
+
+	// 沒有階級表的遊戲照原樣記錄在 tier
+	if got, err := Rank(Freeform{}, "Casual", " Legend 500 "); err != nil || got.Tier != "Legend 500" || got.Level != nil {
+		t.Errorf("freeform rank = %+v, %v", got, err)
+	}
+	if got, err := Rank(Freeform{}, "Casual", ""); err != nil || got != nil {
+		t.Errorf("empty freeform rank = %+v, %v; want nil", got, err)
+	}
+}
+
+func TestRankCode(t *testing.T) {
+	md := For("master_duel")
+	for input, want := range map[string]string{"master-5": "master-5", "大師 V": "master-5", "diamond ii": "diamond-2"} {
+		if got, err := RankCode(md, input); err != nil || got != want {
+			t.Errorf("RankCode(%q) = %q, %v; want %s", input, got, err, want)
+		}
+	}
+	if _, err := RankCode(md, "master-6"); err == nil {
+		t.Error("RankCode accepted master-6")
+	}
+	if _, err := RankCode(Freeform{}, "Legend"); err == nil {
+		t.Error("RankCode accepted a rank for a game without a ladder")
+	}
+}
+
+func TestModePlayOrderResult(t *testing.T) {
+	md := For("master_duel")
+	tests := []struct {
+		name  string
+		parse func(string) (string, error)
+		input string
+		want  string // "" 表示應該失敗
+	}{
+		{"mode code", func(v string) (string, error) { return Mode(md, v) }, "DC", ModeDC},
+		{"mode label", func(v string) (string, error) { return Mode(md, v) }, "決鬥者盃", ModeDC},
+		{"mode english label", func(v string) (string, error) { return Mode(md, v) }, "duelist cup", ModeDC},
+		{"unknown mode", func(v string) (string, error) { return Mode(md, v) }, "Arena", ""},
+		{"empty mode", func(v string) (string, error) { return Mode(md, v) }, " ", ""},
+		{"freeform mode", func(v string) (string, error) { return Mode(Freeform{}, v) }, "Arena", "Arena"},
+		{"play order label", func(v string) (string, error) { return PlayOrder(md, v) }, "先攻", PlayOrderFirst},
+		{"play order code", func(v string) (string, error) { return PlayOrder(md, v) }, "Second", PlayOrderSecond},
+		{"unknown play order", func(v string) (string, error) { return PlayOrder(md, v) }, "third", ""},
+		{"result code", func(v string) (string, error) { return Result(md, v) }, "w", ResultWin},
+		{"result label", func(v string) (string, error) { return Result(md, v) }, "平手", ResultDraw},
+		{"result english", func(v string) (string, error) { return Result(md, v) }, "Loss", ResultLoss},
+		{"unknown result", func(v string) (string, error) { return Result(Freeform{}, v) }, "X", ""},
+	}
+	for _, tt := range tests {
+		got, err := tt.parse(tt.input)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: %q accepted as %q", tt.name, tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: %q = %q, %v; want %q", tt.name, tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	md := For("master_duel")
+	tests := []struct {
+		field, code, lang, want string
+	}{
+		{FieldRank, "gold-4", LangZhTW, "金 IV"},
+		{FieldRank, "master-1", LangEn, "Master I"},
+		{FieldRank, "gold-9", LangEn, "gold-9"}, // 不認得時回傳代碼
+		{FieldMode, ModeRanked, LangZhTW, "天梯"},
+		{FieldResult, ResultDraw, LangEn, "Draw"},
+		{FieldPlayOrder, PlayOrderSecond, LangZhTW, "後攻"},
+	}
+	for _, tt := range tests {
+		if got := Label(md, tt.field, tt.code, tt.lang); got != tt.want {
+			t.Errorf("Label(%s, %s, %s) = %q, want %q", tt.field, tt.code, tt.lang, got, tt.want)
+		}
+	}
+	for input, want := range map[string]string{"": LangZhTW, "en-US,en;q=0.9": LangEn, "zh-TW": LangZhTW, "ja": LangZhTW} {
+		if got := ParseLang(input); got != want {
+			t.Errorf("ParseLang(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestParseRankCode(t *testing.T) {
+	level := func(n int) *int { return &n }
+	tests := []struct {
+		code string
+		want models.Rank
+	}{
+		{"gold-4", models.Rank{Tier: "gold", Level: level(4)}},
+		{"master-1", models.Rank{Tier: "master", Level: level(1)}},
+		{"Legend", models.Rank{Tier: "Legend"}},
+		{"ultra-rare-2", models.Rank{Tier: "ultra-rare", Level: level(2)}},
+		{"top-ten", models.Rank{Tier: "top-ten"}},
+		{"-3", models.Rank{Tier: "-3"}},
+	}
+	for _, tt := range tests {
+		got := models.ParseRankCode(tt.code)
+		if got.Tier != tt.want.Tier || (got.Level == nil) != (tt.want.Level == nil) || (got.Level != nil && *got.Level != *tt.want.Level) {
+			t.Errorf("ParseRankCode(%q) = %+v, want %+v", tt.code, got, tt.want)
+		}
+		if got.Code() != tt.code {
+			t.Errorf("ParseRankCode(%q).Code() = %q, want round trip", tt.code, got.Code())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package season 賽季曆：由賽季代碼推算起訖日，或由日期推算所在的賽季。
+//
+// Master Duel 每個月為一季，以 2024 年 8 月 = S32 為基準（與 apps/web/src/utils/season.ts 相同）。
+// 沒有賽季曆的遊戲只認得 YYYY-MM 格式的代碼（視為該月份）。
+package season
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout 起訖日的格式
+const DateLayout = "2006-01-02"
+
+// Calendar 以月份為單位的賽季曆：BaseYear / BaseMonth 為 S<BaseNumber>，之後每個月 +1
+type Calendar struct {
+	BaseYear   int
+	BaseMonth  time.Month
+	BaseNumber int
+}
+
+// MasterDuel Master Duel 的賽季曆（2024 年 8 月 = S32）
+var MasterDuel = Calendar{BaseYear: 2024, BaseMonth: time.August, BaseNumber: 32}
+
+// calendars 各遊戲的賽季曆（依 game key）
+var calendars = map[string]Calendar{
+	"master_duel": MasterDuel,
+}
+
+// For 取得 gameKey 的賽季曆；沒有賽季曆時 ok 為 false
+func For(gameKey string) (Calendar, bool) {
+	c, ok := calendars[gameKey]
+	return c, ok
+}
+
+// Info 一個賽季的代碼與起訖日
+type Info struct {
+	Code   string    // e.g. "S49"
+	Number int       // e.g. 49
+	Start  time.Time // 該月 1 日（UTC）
+	End    time.Time // 該月最後一天（UTC）
+}
+
+// StartDate 開始日（YYYY-MM-DD）
+func (i Info) StartDate() string { return i.Start.Format(DateLayout) }
+
+// EndDate 結束日（YYYY-MM-DD）
+func (i Info) EndDate() string { return i.End.Format(DateLayout) }
+
+var codePattern = regexp.MustCompile(`^[Ss](\d+)$`)
+
+// Season 第 number 季
+func (c Calendar) Season(number int) Info {
+	start := time.Date(c.BaseYear, c.BaseMonth+time.Month(number-c.BaseNumber), 1, 0, 0, 0, 0, time.UTC)
+	return Info{
+		Code:   fmt.Sprintf("S%d", number),
+		Number: number,
+		Start:  start,
+		End:    start.AddDate(0, 1, -1),
+	}
+}
+
+// Parse 解析 "S49" 形式的代碼（不分大小寫）
+func (c Calendar) Parse(code string) (Info, bool) {
+	m := codePattern.FindStringSubmatch(strings.TrimSpace(code))
+	if m == nil {
+		return Info{}, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return Info{}, false
+	}
+	return c.Season(n), true
+}
+
+// ForDate date 所在的賽季（只看年月）
+func (c Calendar) ForDate(date time.Time) Info {
+	months := (date.Year()-c.BaseYear)*12 + int(date.Month()-c.BaseMonth)
+	return c.Season(c.BaseNumber + months)
+}
+
+// Current 目前的賽季
+func (c Calendar) Current(now time.Time) Info {
+	return c.ForDate(now)
+}
+
+// ParseDate 解析對局日期（YYYY-MM-DD，允許後面接時間）
+func ParseDate(date string) (time.Time, bool) {
+	if len(date) > len(DateLayout) {
+		date = date[:len(DateLayout)]
+	}
+	t, err := time.Parse(DateLayout, date)
+	return t, err == nil
+}
+
+// Dates gameKey 的 code 對應的起訖日（YYYY-MM-DD）：
+// 有賽季曆時依賽季曆解析；YYYY-MM 格式的代碼一律視為該月份；都不符合時 ok 為 false
+func Dates(gameKey, code string) (start, end string, ok bool) {
+	if c, hasCalendar := For(gameKey); hasCalendar {
+		if info, parsed := c.Parse(code); parsed {
+			return info.StartDate(), info.EndDate(), true
+		}
+	}
+	if t, err := time.Parse("2006-01", code); err == nil {
+		first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return first.Format(DateLayout), first.AddDate(0, 1, -1).Format(DateLayout), true
+	}
+	return "", "", false
+}
+
+// MismatchError 指定的賽季代碼與對局日期不符
+type MismatchError struct {
+	Code     string // 指定的賽季代碼
+	Date     string // 對局日期
+	Expected string // 依日期推算的賽季代碼（無法推算時為空字串）
+}
+
+func (e *MismatchError) Error() string {
+	if e.Expected == "" {
+		return fmt.Sprintf("seasonCode %s 與日期 %s 不符", e.Code, e.Date)
+	}
+	return fmt.Sprintf("seasonCode %s 與日期 %s 不符（應為 %s）", e.Code, e.Date, e.Expected)
+}
+
+// Resolve 決定對局的賽季代碼：
+//   - code 為空時依 gameKey 的賽季曆由 date 推算；沒有賽季曆時回傳空字串，由呼叫端另行處理
+//   - code 有值且能推算起訖日時，date 必須落在該賽季內，否則回傳 *MismatchError
+//
+// 賽季曆認得的代碼會正規化（e.g. "s49" → "S49"）。
+func Resolve(gameKey, code string, date time.Time) (string, error) {
+	cal, hasCalendar := For(gameKey)
+	code = strings.TrimSpace(code)
+	if code == "" {
+		if !hasCalendar {
+			return "", nil
+		}
+		return cal.ForDate(date).Code, nil
+	}
+
+	if hasCalendar {
+		if info, ok := cal.Parse(code); ok {
+			code = info.Code
+		}
+	}
+	start, end, ok := Dates(gameKey, code)
+	if !ok {
+		return code, nil
+	}
+	if d := date.Format(DateLayout); d < start || d > end {
+		mismatch := &MismatchError{Code: code, Date: date.Format(DateLayout)}
+		if hasCalendar {
+			mismatch.Expected = cal.ForDate(date).Code
+		}
+		return "", mismatch
+	}
+	return code, nil
+}
+
+// LocalDate t 在 loc 當地的日期（YYYY-MM-DD）
+func LocalDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(DateLayout)
+}
+
+// LoadLocation 解析 IANA 時區名稱（e.g. "Asia/Taipei"）；不接受空字串與伺服器相依的 "Local"
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("無效的時區 %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// Reset 賽季切換的時間點：在 Location 當地每月 1 日的 Hour:Minute
+type Reset struct {
+	Location *time.Location
+	Hour     int
+	Minute   int
+}
+
+// ParseReset 解析遊戲的賽季切換時間（HH:MM）與時區
+func ParseReset(clock, timezone string) (Reset, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return Reset{}, fmt.Errorf("無效的切換時間 %q（HH:MM）", clock)
+	}
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return Reset{}, err
+	}
+	return Reset{Location: loc, Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// Date playedAt 用來判斷賽季的日期：換算到 Location 當地時間後減去切換時間，
+// 讓切換前的對局仍算在上個月（e.g. 06:00 切換時，1 日 05:59 的對局屬於上一季）
+func (r Reset) Date(playedAt time.Time) time.Time {
+	local := playedAt.In(r.Location).Add(-time.Duration(r.Hour)*time.Hour - time.Duration(r.Minute)*time.Minute)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,157 @@
+package season
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCalendarParse(t *testing.T) {
+	tests := []struct {
+		code       string
+		want       string
+		start, end string
+	}{
+		{"S32", "S32", "2024-08-01", "2024-08-31"},
+		{"s57", "S57", "2026-09-01", "2026-09-30"},
+		{" S58 ", "S58", "2026-10-01", "2026-10-31"},
+		{"S38", "S38", "2025-02-01", "2025-02-28"},
+		{"S74", "S74", "2028-02-01", "2028-02-29"}, // 閏年
+		{"S1", "S1", "2022-01-01", "2022-01-31"},   // 基準之前
+	}
+	for _, tt := range tests {
+		info, ok := MasterDuel.Parse(tt.code)
+		if !ok || info.Code != tt.want || info.StartDate() != tt.start || info.EndDate() != tt.end {
+			t.Errorf("Parse(%q) = %+v, %v; want %s %s ~ %s", tt.code, info, ok, tt.want, tt.start, tt.end)
+		}
+	}
+	for _, code := range []string{"", "57", "S", "Sx", "S-1", "2026-09", "Season 57"} {
+		if info, ok := MasterDuel.Parse(code); ok {
+			t.Errorf("Parse(%q) = %+v, want not ok", code, info)
+		}
+	}
+}
+
+func TestCalendarForDate(t *testing.T) {
+	tests := map[string]string{
+		"2024-08-01": "S32",
+		"2024-07-31": "S31",
+		"2026-09-30": "S57",
+		"2026-10-01": "S58",
+		"2027-01-15": "S61",
+	}
+	for date, want := range tests {
+		if got := MasterDuel.ForDate(day(date)).Code; got != want {
+			t.Errorf("ForDate(%s) = %s, want %s", date, got, want)
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+	tests := []struct {
+		gameKey, code string
+		start, end    string
+		ok            bool
+	}{
+		{"master_duel", "S57", "2026-09-01", "2026-09-30", true},
+		{"master_duel", "2026-02", "2026-02-01", "2026-02-28", true},
+		{"ptcg", "2026-09", "2026-09-01", "2026-09-30", true},
+		{"ptcg", "S57", "", "", false},
+		{"master_duel", "Spring", "", "", false},
+	}
+	for _, tt := range tests {
+		start, end, ok := Dates(tt.gameKey, tt.code)
+		if start != tt.start || end != tt.end || ok != tt.ok {
+			t.Errorf("Dates(%s, %s) = %s, %s, %v; want %s, %s, %v", tt.gameKey, tt.code, start, end, ok, tt.start, tt.end, tt.ok)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		gameKey, code, date string
+		want                string
+		expected            string // 不符時 MismatchError.Expected；"-" 表示不應出錯
+	}{
+		{"master_duel", "", "2026-09-03", "S57", "-"},
+		{"master_duel", "s57", "2026-09-30", "S57", "-"},
+		{"master_duel", "S57", "2026-10-01", "", "S58"},
+		{"master_duel", "2026-09", "2026-09-15", "2026-09", "-"},
+		{"ptcg", "", "2026-09-03", "", "-"},
+		{"ptcg", "Spring", "2026-09-03", "Spring", "-"},
+		{"ptcg", "2026-09", "2026-10-01", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.gameKey, tt.code, day(tt.date))
+		if tt.expected == "-" {
+			if err != nil || got != tt.want {
+				t.Errorf("Resolve(%s, %q, %s) = %q, %v; want %q", tt.gameKey, tt.code, tt.date, got, err, tt.want)
+			}
+			continue
+		}
+		var mismatch *MismatchError
+		if !errors.As(err, &mismatch) || mismatch.Expected != tt.expected || mismatch.Date != tt.date {
+			t.Errorf("Resolve(%s, %q, %s) = %q, %v; want a mismatch expecting %q", tt.gameKey, tt.code, tt.date, got, err, tt.expected)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for input, want := range map[string]string{
+		"2026-09-03":           "2026-09-03",
+		"2026-09-03T23:59:00Z": "2026-09-03",
+		"2026-09-03 10:00:00":  "2026-09-03",
+	} {
+		if got, ok := ParseDate(input); !ok || got.Format(DateLayout) != want {
+			t.Errorf("ParseDate(%q) = %v, %v; want %s", input, got, ok, want)
+		}
+	}
+	for _, input := range []string{"", "2026-9-3", "2026/09/03", "2026-13-01"} {
+		if got, ok := ParseDate(input); ok {
+			t.Errorf("ParseDate(%q) = %v, want not ok", input, got)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	reset, err := ParseReset("06:00", "Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"2026-09-30T20:59:00Z": "2026-09-30", // 東京 10/1 05:59，切換前仍屬上一季
+		"2026-09-30T21:00:00Z": "2026-10-01", // 東京 10/1 06:00
+		"2026-10-01T12:00:00Z": "2026-10-01",
+	}
+	for playedAt, want := range tests {
+		at, err := time.Parse(time.RFC3339, playedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reset.Date(at).Format(DateLayout); got != want {
+			t.Errorf("Date(%s) = %s, want %s", playedAt, got, want)
+		}
+	}
+
+	utc, err := ParseReset("00:00", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := utc.Date(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)).Format(DateLayout); got != "2026-10-01" {
+		t.Errorf("UTC midnight = %s, want 2026-10-01", got)
+	}
+
+	for _, bad := range [][2]string{{"6am", "UTC"}, {"25:00", "UTC"}, {"06:00", "Local"}, {"06:00", ""}, {"06:00", "Mars/Olympus"}} {
+		if _, err := ParseReset(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseReset(%q, %q) accepted", bad[0], bad[1])
+		}
+	}
+}
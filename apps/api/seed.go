@@ -0,0 +1,8 @@
+package main
+
+import _ "embed"
+
+// seedSQL 內嵌的 seed.sql（預設資料），讓執行檔不依賴工作目錄
+//
+//go:embed seed.sql
+var seedSQL string
@@ -3,19 +3,22 @@
 
 -- Note:
 -- - 假設 migrations 已建立 tables。
+-- - 使用 ON CONFLICT DO NOTHING（SQLite 3.24+ 與 PostgreSQL 皆支援），同一份 seed 兩種資料庫都能用。
 -- - 本檔案僅提供「最小可用」的示範資料，讓新使用者一進來就看得懂資料長相。
 
 -- 插入預設使用者（MVP 單人模式）
-INSERT OR IGNORE INTO users (id, email, password_hash, created_at, updated_at)
-VALUES ('user-001', 'demo@duellog.com', 'placeholder_hash', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+INSERT INTO users (id, email, password_hash, created_at, updated_at)
+VALUES ('user-001', 'demo@duellog.com', 'placeholder_hash', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+ON CONFLICT DO NOTHING;
 
 -- 插入遊戲：Yu-Gi-Oh! Master Duel
-INSERT OR IGNORE INTO games (id, key, name)
-VALUES ('game-md', 'master_duel', 'Yu-Gi-Oh! Master Duel');
+INSERT INTO games (id, key, name)
+VALUES ('game-md', 'master_duel', 'Yu-Gi-Oh! Master Duel')
+ON CONFLICT DO NOTHING;
 
 -- 插入牌組模板（前端下拉選項用；theme 可之後在 UI 調整）
 -- main：主軸，sub：副軸
-INSERT OR IGNORE INTO deck_templates (id, game_id, main, theme, deck_type) VALUES
+INSERT INTO deck_templates (id, game_id, main, theme, deck_type) VALUES
   ('tpl-auto-e5860cb2', 'game-md', 'DDD', '超量', 'main'),
   ('tpl-auto-a4c5a59a', 'game-md', 'FTK', '無', 'main'),
   ('tpl-auto-c82dcfe0', 'game-md', 'HERO', '融合', 'main'),
@@ -148,6 +151,7 @@ INSERT OR IGNORE INTO deck_templates (id, game_id, main, theme, deck_type) VALUE
   ('tpl-auto-79831f65', 'game-md', '龍女僕', '融合', 'main'),
   ('tpl-auto-eb349bfd', 'game-md', '龍華', '魔法', 'main'),
   ('tpl-auto-730d31a1', 'game-md', '龍輝巧', '儀式', 'main'),
-  ('tpl-auto-2b24fcc5', 'game-md', '龐克', '同步', 'main');
+  ('tpl-auto-2b24fcc5', 'game-md', '龐克', '同步', 'main')
+ON CONFLICT DO NOTHING;
 
 -- 注意：本 seed 不會插入 decks / matches（避免把個人對局資料帶進公開 repo）。
@@ -0,0 +1,254 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// IntervalMethod 信賴區間的計算方式
+type IntervalMethod string
+
+const (
+	IntervalWilson IntervalMethod = "wilson" // Wilson score interval
+	IntervalBeta   IntervalMethod = "beta"   // Beta 後驗分布的等尾區間（先驗以整體勝率為中心）
+)
+
+// 預設參數
+const (
+	DefaultLevel      = 0.95
+	DefaultPriorGames = 10.0 // 收縮強度：相當於先加入幾場「整體勝率」的虛擬對局
+	DefaultMaxWidth   = 30.0 // 區間寬度（百分點）超過此值即標記為低信心
+)
+
+// Estimate 勝率的區間估計與收縮估計（數值皆為 0-100）
+type Estimate struct {
+	Method        IntervalMethod `json:"method"`
+	Level         float64        `json:"level"`         // 信賴水準，e.g. 0.95
+	Lower         float64        `json:"lower"`         // 區間下界
+	Upper         float64        `json:"upper"`         // 區間上界
+	Shrunk        float64        `json:"shrunk"`        // 往整體勝率收縮後的估計
+	LowConfidence bool           `json:"lowConfidence"` // 區間寬度超過 MaxWidth
+}
+
+// Estimator 為統計列計算 Estimate。
+// 收縮目標（baseline）是同一組篩選條件下的整體勝率 / 先攻勝率 / 後攻勝率，由各統計函式自動帶入。
+type Estimator struct {
+	Method     IntervalMethod
+	Level      float64
+	PriorGames float64
+	MaxWidth   float64
+
+	baseWin, baseFirst, baseSecond float64 // 0-1
+}
+
+// NewEstimator 建立 Estimator。priorGames 為 0 時不收縮（Beta 區間改用 Jeffreys prior）。
+func NewEstimator(method IntervalMethod, level, priorGames, maxWidth float64) (*Estimator, error) {
+	if method != IntervalWilson && method != IntervalBeta {
+		return nil, fmt.Errorf("未知的區間方法: %s（可用 wilson 或 beta）", method)
+	}
+	// 以 !(...) 的寫法讓 NaN 也無法通過（與 NaN 比較一律為 false）
+	if !(level > 0 && level < 1) {
+		return nil, errors.New("level 必須介於 0 與 1 之間")
+	}
+	if !(priorGames >= 0) || math.IsInf(priorGames, 1) {
+		return nil, errors.New("prior 必須是非負的有限數字")
+	}
+	if !(maxWidth >= 0) || math.IsInf(maxWidth, 1) {
+		return nil, errors.New("maxWidth 必須是非負的有限數字")
+	}
+	return &Estimator{Method: method, Level: level, PriorGames: priorGames, MaxWidth: maxWidth}, nil
+}
+
+// withBaseline 回傳帶有該篩選條件整體勝率的副本；e 為 nil 時回傳 nil（不計算區間）
+func (e *Estimator) withBaseline(db *storage.DB, f models.MatchFilter) (*Estimator, error) {
+	if e == nil {
+		return nil, nil
+	}
+	from, args := filterSQL(f)
+	var c counts
+	if err := db.QueryRow("SELECT "+countColumns+from, args...).Scan(c.scanTargets()...); err != nil {
+		return nil, err
+	}
+	withBase := *e
+	withBase.baseWin = baselineRate(c.Wins, c.Total)
+	withBase.baseFirst = baselineRate(c.FirstWins, c.First)
+	withBase.baseSecond = baselineRate(c.SecondWins, c.Second)
+	return &withBase, nil
+}
+
+// winRate / firstWinRate / secondWinRate 各自以對應的整體比率為收縮目標（e 為 nil 時回傳 nil）
+func (e *Estimator) winRate(wins, games int) *Estimate {
+	if e == nil {
+		return nil
+	}
+	return e.estimate(wins, games, e.baseWin)
+}
+
+func (e *Estimator) firstWinRate(wins, games int) *Estimate {
+	if e == nil {
+		return nil
+	}
+	return e.estimate(wins, games, e.baseFirst)
+}
+
+func (e *Estimator) secondWinRate(wins, games int) *Estimate {
+	if e == nil {
+		return nil
+	}
+	return e.estimate(wins, games, e.baseSecond)
+}
+
+// estimate 計算 wins/games 的區間與收縮估計；e 為 nil 或 games 為 0 時回傳 nil
+func (e *Estimator) estimate(wins, games int, baseline float64) *Estimate {
+	if e == nil || games <= 0 {
+		return nil
+	}
+
+	// Beta 先驗：Beta(k*p0, k*(1-p0))；p0 夾在 (0,1) 內避免退化
+	p0 := math.Min(math.Max(baseline, 0.01), 0.99)
+	priorA := e.PriorGames * p0
+	priorB := e.PriorGames * (1 - p0)
+
+	n := float64(games)
+	w := float64(wins)
+	shrunk := (w + priorA) / (n + priorA + priorB)
+
+	var lower, upper float64
+	switch e.Method {
+	case IntervalBeta:
+		// 無先驗時退回 Jeffreys prior Beta(0.5, 0.5)
+		if e.PriorGames == 0 {
+			priorA, priorB = 0.5, 0.5
+		}
+		a := w + priorA
+		b := n - w + priorB
+		tail := (1 - e.Level) / 2
+		lower = betaQuantile(tail, a, b)
+		upper = betaQuantile(1-tail, a, b)
+	default:
+		lower, upper = wilsonInterval(w, n, e.Level)
+	}
+
+	return &Estimate{
+		Method:        e.Method,
+		Level:         e.Level,
+		Lower:         lower * 100,
+		Upper:         upper * 100,
+		Shrunk:        shrunk * 100,
+		LowConfidence: (upper-lower)*100 > e.MaxWidth,
+	}
+}
+
+// baselineRate part/whole（0-1）；沒有資料時以 0.5 作為中性先驗
+func baselineRate(part, whole int) float64 {
+	if whole <= 0 {
+		return 0.5
+	}
+	return float64(part) / float64(whole)
+}
+
+// wilsonInterval Wilson score interval（回傳 0-1）；n 為 0 時沒有資訊，回傳 [0, 1]
+func wilsonInterval(wins, n, level float64) (float64, float64) {
+	if n <= 0 {
+		return 0, 1
+	}
+	z := math.Sqrt2 * math.Erfinv(level)
+	p := wins / n
+	z2 := z * z
+	denom := 1 + z2/n
+	center := (p + z2/(2*n)) / denom
+	margin := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / denom
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// betaQuantile 以二分法求 Beta(a, b) 的 q 分位數
+func betaQuantile(q, a, b float64) float64 {
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if regIncBeta(mid, a, b) < q {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regIncBeta 正則化不完全 Beta 函數 I_x(a, b)（連分式展開，Numerical Recipes §6.4）
+func regIncBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgA, _ := math.Lgamma(a)
+	lgB, _ := math.Lgamma(b)
+	lgAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgAB - lgA - lgB + a*math.Log(x) + b*math.Log(1-x))
+
+	// 依收斂條件選擇直接展開或使用對稱關係 I_x(a,b) = 1 - I_{1-x}(b,a)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction modified Lentz 法計算 I_x 的連分式
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIter = 300
+		eps     = 1e-14
+		tiny    = 1e-300
+	)
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return h
+}
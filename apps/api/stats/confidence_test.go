@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-4
+
+func near(a, b float64) bool { return math.Abs(a-b) < tolerance }
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		name         string
+		wins, n      float64
+		level        float64
+		lower, upper float64
+	}{
+		{"0/10", 0, 10, 0.95, 0, 0.277533},
+		{"10/10", 10, 10, 0.95, 0.722467, 1},
+		{"5/10", 5, 10, 0.95, 0.236593, 0.763407},
+		{"81/263", 81, 263, 0.95, 0.255289, 0.366210},
+		{"5/10 at 90%", 5, 10, 0.90, 0.269272, 0.730728},
+		{"n=0", 0, 0, 0.95, 0, 1},
+	}
+	for _, tt := range tests {
+		lower, upper := wilsonInterval(tt.wins, tt.n, tt.level)
+		if !near(lower, tt.lower) || !near(upper, tt.upper) {
+			t.Errorf("%s: wilsonInterval = [%.6f, %.6f], want [%.6f, %.6f]", tt.name, lower, upper, tt.lower, tt.upper)
+		}
+	}
+}
+
+func TestBetaQuantile(t *testing.T) {
+	// 有封閉解的分布：Beta(a, 1) 的 CDF 為 x^a，Beta(1, b) 為 1-(1-x)^b，Beta(½, ½) 為 (2/π)·asin(√x)
+	tests := []struct {
+		name string
+		q    float64
+		a, b float64
+		want float64
+	}{
+		{"uniform", 0.3, 1, 1, 0.3},
+		{"Beta(2,1) median", 0.5, 2, 1, math.Sqrt(0.5)},
+		{"Beta(1,2) q=0.75", 0.75, 1, 2, 0.5},
+		{"symmetric median", 0.5, 5.5, 5.5, 0.5},
+		{"0/10 with uniform prior, upper", 0.975, 1, 11, 1 - math.Pow(0.025, 1.0/11)},
+		{"10/10 with uniform prior, lower", 0.025, 11, 1, math.Pow(0.025, 1.0/11)},
+		{"Jeffreys prior, no data", 0.025, 0.5, 0.5, math.Pow(math.Sin(math.Pi*0.025/2), 2)},
+	}
+	for _, tt := range tests {
+		if got := betaQuantile(tt.q, tt.a, tt.b); !near(got, tt.want) {
+			t.Errorf("%s: betaQuantile(%v, %v, %v) = %.6f, want %.6f", tt.name, tt.q, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	wilson, err := NewEstimator(IntervalWilson, 0.95, 0, DefaultMaxWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := NewEstimator(IntervalBeta, 0.95, 0, DefaultMaxWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shrinking, err := NewEstimator(IntervalWilson, 0.95, 10, DefaultMaxWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e := wilson.estimate(0, 0, 0.5); e != nil {
+		t.Errorf("estimate with no games = %+v, want nil", e)
+	}
+
+	e := wilson.estimate(10, 10, 0.5)
+	if !near(e.Lower, 72.2467) || !near(e.Upper, 100) || e.Shrunk != 100 || e.LowConfidence {
+		t.Errorf("wilson 10/10 = %+v, want [72.25, 100], shrunk 100, width below 30", e)
+	}
+	if e = wilson.estimate(2, 4, 0.5); !e.LowConfidence {
+		t.Errorf("wilson 2/4 = %+v, want low confidence", e)
+	}
+
+	// Jeffreys：0/10 → Beta(0.5, 10.5)，下界貼近 0
+	e = beta.estimate(0, 10, 0.5)
+	if e.Lower < 0 || e.Lower > 0.01 || e.Upper < 15 || e.Upper > 25 {
+		t.Errorf("beta 0/10 = %+v, want lower ≈ 0 and upper ≈ 21", e)
+	}
+
+	// 10 場虛擬對局往 50% 收縮：(0 + 5) / (10 + 10)
+	e = shrinking.estimate(0, 10, 0.5)
+	if !near(e.Shrunk, 25) {
+		t.Errorf("shrunk 0/10 toward 50%% = %.4f, want 25", e.Shrunk)
+	}
+
+	e = wilson.estimate(500, 1000, 0.5)
+	if e.LowConfidence {
+		t.Errorf("1000 games should not be low confidence: %+v", e)
+	}
+}
+
+func TestNewEstimatorRejectsNonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name                   string
+		level, prior, maxWidth float64
+	}{
+		{"level NaN", nan, 10, 30},
+		{"level 1", 1, 10, 30},
+		{"prior NaN", 0.95, nan, 30},
+		{"prior Inf", 0.95, inf, 30},
+		{"prior negative", 0.95, -1, 30},
+		{"maxWidth NaN", 0.95, 10, nan},
+		{"maxWidth Inf", 0.95, 10, inf},
+	}
+	for _, tt := range tests {
+		if _, err := NewEstimator(IntervalWilson, tt.level, tt.prior, tt.maxWidth); err == nil {
+			t.Errorf("%s: NewEstimator accepted level=%v prior=%v maxWidth=%v", tt.name, tt.level, tt.prior, tt.maxWidth)
+		}
+	}
+}
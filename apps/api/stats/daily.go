@@ -0,0 +1,184 @@
+package stats
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+const dateLayout = "2006-01-02"
+
+// MaxDailyDays 補 0 的日期區間最多幾天（約一年；單一賽季遠小於此值）
+const MaxDailyDays = 366
+
+// ErrDailyRangeTooLong 補 0 的日期區間超過 MaxDailyDays
+var ErrDailyRangeTooLong = fmt.Errorf("日期區間最多 %d 天", MaxDailyDays)
+
+// DailyRow 每日統計（對應前端 DailyStatRow）
+// 比率為 nil 表示分母為 0（例如補 0 的空白日）。
+type DailyRow struct {
+	Date          string   `json:"date"` // YYYY-MM-DD
+	Games         int      `json:"games"`
+	Wins          int      `json:"wins"`
+	Losses        int      `json:"losses"`
+	Draws         int      `json:"draws"`
+	First         int      `json:"first"`
+	Second        int      `json:"second"`
+	FirstWins     int      `json:"firstWins"`
+	FirstLosses   int      `json:"firstLosses"`
+	FirstDraws    int      `json:"firstDraws"`
+	SecondWins    int      `json:"secondWins"`
+	SecondLosses  int      `json:"secondLosses"`
+	SecondDraws   int      `json:"secondDraws"`
+	FirstRate     *float64 `json:"firstRate"`
+	WinRate       *float64 `json:"winRate"`
+	FirstWinRate  *float64 `json:"firstWinRate"`
+	SecondWinRate *float64 `json:"secondWinRate"`
+
+	// 區間 / 收縮估計（僅在請求 ci 參數時提供）
+	WinRateCI       *Estimate `json:"winRateCI,omitempty"`
+	FirstWinRateCI  *Estimate `json:"firstWinRateCI,omitempty"`
+	SecondWinRateCI *Estimate `json:"secondWinRateCI,omitempty"`
+}
+
+// GetDaily 按日期（使用者當地的日期，見 models.Match.Date）分組統計。
+// 若有指定日期區間（dateFrom + dateTo）或賽季，區間內沒有對局的日子會補上 0 筆資料；
+// 否則只回傳有對局的日期。結果依日期升冪排序；est 不為 nil 時附上區間估計。
+// 補 0 的區間超過 MaxDailyDays 天時回傳 ErrDailyRangeTooLong。
+func GetDaily(db *storage.DB, f models.MatchFilter, est *Estimator) ([]DailyRow, error) {
+	start, end, err := dailyRange(db, f)
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsZero() && end.Sub(start) >= MaxDailyDays*24*time.Hour {
+		return nil, ErrDailyRangeTooLong
+	}
+
+	est, err = est.withBaseline(db, f)
+	if err != nil {
+		return nil, err
+	}
+	from, args := filterSQL(f)
+
+	rows, err := db.Query("SELECT m.date,"+countColumns+from+" GROUP BY m.date ORDER BY m.date ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byDate := map[string]counts{}
+	dates := []string{}
+	for rows.Next() {
+		var date string
+		var c counts
+		if err := rows.Scan(append([]interface{}{&date}, c.scanTargets()...)...); err != nil {
+			return nil, err
+		}
+		date = normalizeDate(date)
+		if existing, ok := byDate[date]; ok {
+			c = existing.add(c)
+		} else {
+			dates = append(dates, date)
+		}
+		byDate[date] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if start.IsZero() || end.IsZero() {
+		daily := make([]DailyRow, 0, len(dates))
+		for _, d := range dates {
+			daily = append(daily, toDailyRow(d, byDate[d], est))
+		}
+		return daily, nil
+	}
+
+	daily := []DailyRow{}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format(dateLayout)
+		daily = append(daily, toDailyRow(key, byDate[key], est))
+	}
+	return daily, nil
+}
+
+// dailyRange 決定補 0 的日期區間：
+// 優先使用 dateFrom/dateTo，缺少的一端再以賽季起訖日補上；兩端都無法決定時回傳零值（不補 0）。
+func dailyRange(db *storage.DB, f models.MatchFilter) (time.Time, time.Time, error) {
+	from, to := f.DateFrom, f.DateTo
+
+	if (from == "" || to == "") && f.SeasonCode != "" {
+		// 不同遊戲可能有相同的賽季代碼
+		query, args := "SELECT start_date, end_date FROM seasons WHERE code = ?", []interface{}{f.SeasonCode}
+		if f.GameKey != "" {
+			query += " AND game_id = (SELECT id FROM games WHERE key = ?)"
+			args = append(args, f.GameKey)
+		}
+		var startDate, endDate sql.NullString
+		err := db.QueryRow(query+" LIMIT 1", args...).Scan(&startDate, &endDate)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, time.Time{}, err
+		}
+		if from == "" && startDate.Valid {
+			from = normalizeDate(startDate.String)
+		}
+		if to == "" && endDate.Valid {
+			to = normalizeDate(endDate.String)
+		}
+	}
+
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	start, err := time.Parse(dateLayout, normalizeDate(from))
+	if err != nil {
+		return time.Time{}, time.Time{}, nil
+	}
+	end, err := time.Parse(dateLayout, normalizeDate(to))
+	if err != nil || end.Before(start) {
+		return time.Time{}, time.Time{}, nil
+	}
+	return start, end, nil
+}
+
+func toDailyRow(date string, c counts, est *Estimator) DailyRow {
+	return DailyRow{
+		Date:          date,
+		Games:         c.Total,
+		Wins:          c.Wins,
+		Losses:        c.Losses,
+		Draws:         c.Draws,
+		First:         c.First,
+		Second:        c.Second,
+		FirstWins:     c.FirstWins,
+		FirstLosses:   c.FirstLosses,
+		FirstDraws:    c.FirstDraws,
+		SecondWins:    c.SecondWins,
+		SecondLosses:  c.SecondLosses,
+		SecondDraws:   c.SecondDraws,
+		FirstRate:     optionalPercent(c.First, c.Total),
+		WinRate:       optionalPercent(c.Wins, c.Total),
+		FirstWinRate:  optionalPercent(c.FirstWins, c.First),
+		SecondWinRate: optionalPercent(c.SecondWins, c.Second),
+
+		WinRateCI:       est.winRate(c.Wins, c.Total),
+		FirstWinRateCI:  est.firstWinRate(c.FirstWins, c.First),
+		SecondWinRateCI: est.secondWinRate(c.SecondWins, c.Second),
+	}
+}
+
+// normalizeDate 將 DB 回傳的日期（可能是 YYYY-MM-DD 或 ISO datetime）統一為 YYYY-MM-DD
+func normalizeDate(s string) string {
+	if i := strings.Index(s, "T"); i >= 0 {
+		return s[:i]
+	}
+	if len(s) > len(dateLayout) && s[len(dateLayout)] == ' ' {
+		return s[:len(dateLayout)]
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// DeckSide 統計哪一方的牌組
+type DeckSide string
+
+const (
+	SideOpponent DeckSide = "opp" // 對手牌組（對手分布）
+	SideMine     DeckSide = "my"  // 我方牌組
+)
+
+// alias 回傳該方牌組在 baseFrom 中的別名
+func (s DeckSide) alias() string {
+	if s == SideMine {
+		return "my_deck"
+	}
+	return "opp_deck"
+}
+
+// DeckRow 牌組統計（對應前端 DeckStatRow，另含 spec §6.3 的 deckMain/count/pct）
+type DeckRow struct {
+	Name     string  `json:"name"`     // 顯示名稱：大軸，或依小軸分組時為「大軸 / 小軸」
+	DeckMain string  `json:"deckMain"` // 大軸
+	DeckSub  *string `json:"deckSub"`  // 小軸（僅依小軸分組時有值）
+	Count    int     `json:"count"`    // 場數（同 games）
+	Pct      float64 `json:"pct"`      // 占篩選後總場數的比例 (0-100)
+
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	WinRate float64 `json:"winRate"`
+
+	First         int      `json:"first"`
+	Second        int      `json:"second"`
+	FirstRate     float64  `json:"firstRate"`
+	FirstWins     int      `json:"firstWins"`
+	FirstLosses   int      `json:"firstLosses"`
+	FirstDraws    int      `json:"firstDraws"`
+	SecondWins    int      `json:"secondWins"`
+	SecondLosses  int      `json:"secondLosses"`
+	SecondDraws   int      `json:"secondDraws"`
+	FirstWinRate  *float64 `json:"firstWinRate"`  // 沒有先攻場次時為 nil
+	SecondWinRate *float64 `json:"secondWinRate"` // 沒有後攻場次時為 nil
+
+	// 區間 / 收縮估計（僅在請求 ci 參數時提供）
+	WinRateCI       *Estimate `json:"winRateCI,omitempty"`
+	FirstWinRateCI  *Estimate `json:"firstWinRateCI,omitempty"`
+	SecondWinRateCI *Estimate `json:"secondWinRateCI,omitempty"`
+}
+
+// GetDecks 依牌組分組統計（預設以大軸分組；bySub 為 true 時以大軸+小軸分組）。
+// 結果依場數降冪排序；est 不為 nil 時附上區間估計（收縮目標為篩選後的整體勝率）。
+func GetDecks(db *storage.DB, f models.MatchFilter, side DeckSide, bySub bool, est *Estimator) ([]DeckRow, error) {
+	est, err := est.withBaseline(db, f)
+	if err != nil {
+		return nil, err
+	}
+	from, args := filterSQL(f)
+	alias := side.alias()
+
+	groupCols := alias + ".main"
+	subCol := "NULL"
+	if bySub {
+		groupCols += ", " + alias + ".sub"
+		subCol = alias + ".sub"
+	}
+
+	rows, err := db.Query(
+		"SELECT "+alias+".main, "+subCol+","+countColumns+from+" GROUP BY "+groupCols,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type group struct {
+		main string
+		sub  *string
+		c    counts
+	}
+	groups := []group{}
+	total := 0
+	for rows.Next() {
+		var g group
+		var sub sql.NullString
+		if err := rows.Scan(append([]interface{}{&g.main, &sub}, g.c.scanTargets()...)...); err != nil {
+			return nil, err
+		}
+		if sub.Valid {
+			g.sub = &sub.String
+		}
+		total += g.c.Total
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	decks := make([]DeckRow, 0, len(groups))
+	for _, g := range groups {
+		decks = append(decks, toDeckRow(deckName(g.main, g.sub), g.main, g.sub, g.c, total, est))
+	}
+
+	sort.SliceStable(decks, func(i, j int) bool {
+		if decks[i].Games != decks[j].Games {
+			return decks[i].Games > decks[j].Games
+		}
+		return decks[i].Name < decks[j].Name
+	})
+	return decks, nil
+}
+
+func toDeckRow(name, main string, sub *string, c counts, total int, est *Estimator) DeckRow {
+	return DeckRow{
+		Name:     name,
+		DeckMain: main,
+		DeckSub:  sub,
+		Count:    c.Total,
+		Pct:      percent(c.Total, total),
+
+		Games:   c.Total,
+		Wins:    c.Wins,
+		Losses:  c.Losses,
+		Draws:   c.Draws,
+		WinRate: percent(c.Wins, c.Total),
+
+		First:         c.First,
+		Second:        c.Second,
+		FirstRate:     percent(c.First, c.Total),
+		FirstWins:     c.FirstWins,
+		FirstLosses:   c.FirstLosses,
+		FirstDraws:    c.FirstDraws,
+		SecondWins:    c.SecondWins,
+		SecondLosses:  c.SecondLosses,
+		SecondDraws:   c.SecondDraws,
+		FirstWinRate:  optionalPercent(c.FirstWins, c.First),
+		SecondWinRate: optionalPercent(c.SecondWins, c.Second),
+
+		WinRateCI:       est.winRate(c.Wins, c.Total),
+		FirstWinRateCI:  est.firstWinRate(c.FirstWins, c.First),
+		SecondWinRateCI: est.secondWinRate(c.SecondWins, c.Second),
+	}
+}
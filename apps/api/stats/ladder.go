@@ -0,0 +1,276 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/ladder"
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// 天梯模擬的預設值
+const (
+	DefaultLadderTarget    = "master-5" // 大師 V
+	DefaultLadderRecent    = 100        // 以最近幾場對局計算勝率
+	DefaultLadderMinSample = 30         // 推算比率的對局少於此數即標記為低信心
+)
+
+// ErrNoRankedMatches 沒有記錄階級的對局，無法推算目前階級或勝率（可改由參數指定）
+var ErrNoRankedMatches = errors.New("沒有記錄階級的對局，請指定 from 與勝率")
+
+// LadderOptions 天梯模擬的參數；比率為 0-100，nil 表示由對局推算
+type LadderOptions struct {
+	From          string // 目前階級；空字串時使用最近一場對局的階級
+	Target        string // 目標階級
+	Recent        int    // 以最近幾場有記錄階級的對局計算勝率
+	MinSample     int    // 由對局推算的比率，場數少於此值時標記 LowConfidence
+	FirstRate     *float64
+	FirstWinRate  *float64
+	SecondWinRate *float64
+	Runs          int
+	MaxGames      int
+	Seed          int64 // 0 表示每次隨機
+}
+
+// NewLadderOptions 預設參數：目標大師 V、最近 100 場、模擬 10000 次
+func NewLadderOptions() LadderOptions {
+	return LadderOptions{
+		Target:    DefaultLadderTarget,
+		Recent:    DefaultLadderRecent,
+		MinSample: DefaultLadderMinSample,
+		Runs:      ladder.DefaultRuns,
+		MaxGames:  ladder.DefaultMaxGames,
+	}
+}
+
+// Validate 驗證參數並將階級轉成代碼（接受顯示名稱，e.g. "大師 V"）
+func (o LadderOptions) Validate(gameKey string) (LadderOptions, error) {
+	if _, ok := rules.LadderFor(gameKey); !ok {
+		return o, fmt.Errorf("%s 沒有天梯升降規則，無法模擬", gameKey)
+	}
+	r := rules.For(gameKey)
+	var err error
+	if o.From != "" {
+		if o.From, err = rules.RankCode(r, o.From); err != nil {
+			return o, err
+		}
+	}
+	if o.Target, err = rules.RankCode(r, o.Target); err != nil {
+		return o, err
+	}
+	if o.Recent < 1 {
+		return o, errors.New("recent 必須是正整數")
+	}
+	if o.MinSample < 0 {
+		return o, errors.New("minSample 不可為負數")
+	}
+	if o.Runs < 1 || o.Runs > ladder.MaxRuns {
+		return o, fmt.Errorf("runs 必須介於 1 與 %d 之間", ladder.MaxRuns)
+	}
+	if o.MaxGames < 1 || o.MaxGames > ladder.MaxMaxGames {
+		return o, fmt.Errorf("maxGames 必須介於 1 與 %d 之間", ladder.MaxMaxGames)
+	}
+	if o.Runs*o.MaxGames > ladder.MaxSteps {
+		return o, fmt.Errorf("runs × maxGames 不可超過 %d（e.g. runs=%d 時 maxGames 最多 %d）", ladder.MaxSteps, o.Runs, ladder.MaxSteps/o.Runs)
+	}
+	rates := []struct {
+		name  string
+		value *float64
+	}{{"firstRate", o.FirstRate}, {"firstWinRate", o.FirstWinRate}, {"secondWinRate", o.SecondWinRate}}
+	for _, rate := range rates {
+		if rate.value != nil && (math.IsNaN(*rate.value) || *rate.value < 0 || *rate.value > 100) {
+			return o, fmt.Errorf("%s 必須介於 0 與 100 之間", rate.name)
+		}
+	}
+	return o, nil
+}
+
+// LadderSample 推算目前階級與勝率的對局（最近 recent 場有記錄階級的對局）
+type LadderSample struct {
+	CurrentRank *string `json:"currentRank"` // 最近一場對局的階級
+	LastPlayed  *string `json:"lastPlayed"`  // 最近一場對局的日期
+	Games       int     `json:"games"`
+	First       int     `json:"first"`
+	FirstWins   int     `json:"firstWins"`
+	FirstDraws  int     `json:"firstDraws"`
+	Second      int     `json:"second"`
+	SecondWins  int     `json:"secondWins"`
+	SecondDraws int     `json:"secondDraws"`
+}
+
+// LadderRates 模擬使用的比率（0-100）
+type LadderRates struct {
+	FirstRate      float64 `json:"firstRate"`
+	FirstWinRate   float64 `json:"firstWinRate"`
+	FirstDrawRate  float64 `json:"firstDrawRate"`
+	SecondWinRate  float64 `json:"secondWinRate"`
+	SecondDrawRate float64 `json:"secondDrawRate"`
+}
+
+// LadderSimulation 天梯模擬的結果
+type LadderSimulation struct {
+	From   string       `json:"from"`
+	Target string       `json:"target"`
+	Seed   int64        `json:"seed"` // 以相同 seed 重跑可得到相同結果
+	Sample LadderSample `json:"sample"`
+	Rates  LadderRates  `json:"rates"`
+	// LowConfidence 有比率由少於 MinSample 場的對局推算（opts 指定的比率不計），Warning 說明是哪些比率
+	LowConfidence bool   `json:"lowConfidence"`
+	Warning       string `json:"warning,omitempty"`
+	ladder.Result
+}
+
+// SimulateLadder 以最近的對局推算目前階級與先後攻勝率（opts 有指定時以 opts 為準），
+// 模擬到達目標階級需要的場數。opts 須先經過 Validate；ctx 取消時中止模擬並回傳 ctx.Err()。
+func SimulateLadder(ctx context.Context, db *storage.DB, f models.MatchFilter, opts LadderOptions) (LadderSimulation, error) {
+	matches, err := loadRankMatches(db, f)
+	if err != nil {
+		return LadderSimulation{}, err
+	}
+	sample := ladderSample(matches, opts.Recent)
+
+	from := opts.From
+	if from == "" {
+		if sample.CurrentRank == nil {
+			return LadderSimulation{}, ErrNoRankedMatches
+		}
+		from = *sample.CurrentRank
+	}
+	rates, err := ladderRates(sample, opts)
+	if err != nil {
+		return LadderSimulation{}, err
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	l, _ := rules.LadderFor(f.GameKey)
+	result, err := ladder.Simulate(ctx, ladder.Config{
+		Ranks:  rules.For(f.GameKey).Ranks(),
+		Rules:  l,
+		From:   from,
+		Target: opts.Target,
+		Rates: ladder.Rates{
+			FirstRate: rates.FirstRate / 100,
+			First:     ladder.Side{Win: rates.FirstWinRate / 100, Draw: rates.FirstDrawRate / 100},
+			Second:    ladder.Side{Win: rates.SecondWinRate / 100, Draw: rates.SecondDrawRate / 100},
+		},
+		Runs:     opts.Runs,
+		MaxGames: opts.MaxGames,
+		Seed:     seed,
+	})
+	if err != nil {
+		return LadderSimulation{}, err
+	}
+	sim := LadderSimulation{
+		From:   from,
+		Target: opts.Target,
+		Seed:   seed,
+		Sample: sample,
+		Rates:  rates,
+		Result: result,
+	}
+	if low := lowSampleRates(sample, opts); len(low) > 0 {
+		sim.LowConfidence = true
+		sim.Warning = fmt.Sprintf("%s 由少於 %d 場的對局推算，模擬結果僅供參考", strings.Join(low, "、"), opts.MinSample)
+	}
+	return sim, nil
+}
+
+// ladderSample 統計最後 recent 場對局（matches 依對局順序）
+func ladderSample(matches []rankMatch, recent int) LadderSample {
+	var s LadderSample
+	if len(matches) == 0 {
+		return s
+	}
+	last := matches[len(matches)-1].point
+	s.CurrentRank, s.LastPlayed = &last.Rank, &last.Date
+
+	if len(matches) > recent {
+		matches = matches[len(matches)-recent:]
+	}
+	for _, m := range matches {
+		s.Games++
+		win, draw := m.point.Result == rules.ResultWin, m.point.Result == rules.ResultDraw
+		switch m.playOrder {
+		case playOrderFirst:
+			s.First++
+			s.FirstWins += boolInt(win)
+			s.FirstDraws += boolInt(draw)
+		default:
+			s.Second++
+			s.SecondWins += boolInt(win)
+			s.SecondDraws += boolInt(draw)
+		}
+	}
+	return s
+}
+
+// ladderRates 由樣本算出比率：某一方沒有對局時改用整體勝率；opts 指定的勝率覆蓋推算值
+// （平手率沿用推算值，但勝率 + 平手率不超過 100）
+func ladderRates(s LadderSample, opts LadderOptions) (LadderRates, error) {
+	if s.Games == 0 && (opts.FirstWinRate == nil || opts.SecondWinRate == nil) {
+		return LadderRates{}, ErrNoRankedMatches
+	}
+	wins, draws := s.FirstWins+s.SecondWins, s.FirstDraws+s.SecondDraws
+	sideRates := func(games, sideWins, sideDraws int) (float64, float64) {
+		if games == 0 {
+			games, sideWins, sideDraws = s.Games, wins, draws
+		}
+		return percent(sideWins, games), percent(sideDraws, games)
+	}
+
+	r := LadderRates{FirstRate: 50}
+	if s.Games > 0 {
+		r.FirstRate = percent(s.First, s.Games)
+	}
+	r.FirstWinRate, r.FirstDrawRate = sideRates(s.First, s.FirstWins, s.FirstDraws)
+	r.SecondWinRate, r.SecondDrawRate = sideRates(s.Second, s.SecondWins, s.SecondDraws)
+
+	if opts.FirstRate != nil {
+		r.FirstRate = *opts.FirstRate
+	}
+	if opts.FirstWinRate != nil {
+		r.FirstWinRate = *opts.FirstWinRate
+		r.FirstDrawRate = min(r.FirstDrawRate, 100-r.FirstWinRate)
+	}
+	if opts.SecondWinRate != nil {
+		r.SecondWinRate = *opts.SecondWinRate
+		r.SecondDrawRate = min(r.SecondDrawRate, 100-r.SecondWinRate)
+	}
+	return r, nil
+}
+
+// lowSampleRates 列出由少於 opts.MinSample 場對局推算的比率（先後攻勝率以該方的場數計算；opts 指定的比率不計）
+func lowSampleRates(s LadderSample, opts LadderOptions) []string {
+	var low []string
+	rates := []struct {
+		name     string
+		override *float64
+		games    int
+	}{
+		{"firstRate", opts.FirstRate, s.Games},
+		{"firstWinRate", opts.FirstWinRate, s.First},
+		{"secondWinRate", opts.SecondWinRate, s.Second},
+	}
+	for _, r := range rates {
+		if r.override == nil && r.games < opts.MinSample {
+			low = append(low, r.name)
+		}
+	}
+	return low
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/harvc/duellog/apps/api/ladder"
+)
+
+func TestLadderOptionsValidate(t *testing.T) {
+	nan, rate := math.NaN(), 55.0
+	tests := []struct {
+		name   string
+		modify func(*LadderOptions)
+		ok     bool
+	}{
+		{"defaults", func(o *LadderOptions) {}, true},
+		{"rank label", func(o *LadderOptions) { o.From = "鑽石 II" }, true},
+		{"rate override", func(o *LadderOptions) { o.FirstWinRate = &rate }, true},
+		{"at the step budget", func(o *LadderOptions) { o.Runs, o.MaxGames = ladder.MaxRuns, ladder.MaxSteps/ladder.MaxRuns }, true},
+		{"over the step budget", func(o *LadderOptions) { o.Runs, o.MaxGames = ladder.MaxRuns, ladder.MaxMaxGames }, false},
+		{"NaN rate", func(o *LadderOptions) { o.SecondWinRate = &nan }, false},
+		{"unknown rank", func(o *LadderOptions) { o.Target = "mythic" }, false},
+		{"no recent matches", func(o *LadderOptions) { o.Recent = 0 }, false},
+	}
+	for _, tt := range tests {
+		opts := NewLadderOptions()
+		tt.modify(&opts)
+		if _, err := opts.Validate("master_duel"); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate error = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+	if _, err := NewLadderOptions().Validate("ptcg"); err == nil {
+		t.Error("Validate accepted a game without ladder rules")
+	}
+}
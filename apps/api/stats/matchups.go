@@ -0,0 +1,165 @@
+package stats
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// MatchupCell 對戰矩陣中的一格（我方牌組 × 對手牌組）
+type MatchupCell struct {
+	MyDeck      string  `json:"myDeck"`  // 顯示名稱（同 DeckRow.Name）
+	OppDeck     string  `json:"oppDeck"` // 顯示名稱（同 DeckRow.Name）
+	MyDeckMain  string  `json:"myDeckMain"`
+	MyDeckSub   *string `json:"myDeckSub"`
+	OppDeckMain string  `json:"oppDeckMain"`
+	OppDeckSub  *string `json:"oppDeckSub"`
+
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	WinRate float64 `json:"winRate"`
+
+	First         int      `json:"first"`
+	Second        int      `json:"second"`
+	FirstWins     int      `json:"firstWins"`
+	SecondWins    int      `json:"secondWins"`
+	FirstWinRate  *float64 `json:"firstWinRate"`  // 沒有先攻場次時為 nil
+	SecondWinRate *float64 `json:"secondWinRate"` // 沒有後攻場次時為 nil
+
+	// 區間 / 收縮估計（僅在請求 ci 參數時提供）
+	WinRateCI       *Estimate `json:"winRateCI,omitempty"`
+	FirstWinRateCI  *Estimate `json:"firstWinRateCI,omitempty"`
+	SecondWinRateCI *Estimate `json:"secondWinRateCI,omitempty"`
+}
+
+// Matchups 稀疏對戰矩陣：只列出場數達門檻的格子。
+// MyDecks / OppDecks 為出現在 Cells 中的列/欄名稱，依場數降冪排序。
+type Matchups struct {
+	MyDecks  []string      `json:"myDecks"`
+	OppDecks []string      `json:"oppDecks"`
+	Cells    []MatchupCell `json:"cells"`
+	MinGames int           `json:"minGames"`
+}
+
+// GetMatchups 計算我方牌組 × 對手牌組的對戰矩陣。
+// minGames 為每格最少場數（小於 1 視為 1）；bySub 為 true 時以「大軸 + 小軸」為單位；
+// est 不為 nil 時附上區間估計。
+func GetMatchups(db *storage.DB, f models.MatchFilter, minGames int, bySub bool, est *Estimator) (Matchups, error) {
+	if minGames < 1 {
+		minGames = 1
+	}
+	est, err := est.withBaseline(db, f)
+	if err != nil {
+		return Matchups{}, err
+	}
+	from, args := filterSQL(f)
+
+	mySub, oppSub := "NULL", "NULL"
+	groupCols := "my_deck.main, opp_deck.main"
+	if bySub {
+		mySub, oppSub = "my_deck.sub", "opp_deck.sub"
+		groupCols = "my_deck.main, my_deck.sub, opp_deck.main, opp_deck.sub"
+	}
+
+	rows, err := db.Query(
+		"SELECT my_deck.main, "+mySub+", opp_deck.main, "+oppSub+","+countColumns+from+
+			" GROUP BY "+groupCols+" HAVING COUNT(*) >= ?",
+		append(args, minGames)...,
+	)
+	if err != nil {
+		return Matchups{}, err
+	}
+	defer rows.Close()
+
+	cells := []MatchupCell{}
+	myGames := map[string]int{}
+	oppGames := map[string]int{}
+	for rows.Next() {
+		var cell MatchupCell
+		var mySubValue, oppSubValue sql.NullString
+		var c counts
+		targets := append([]interface{}{&cell.MyDeckMain, &mySubValue, &cell.OppDeckMain, &oppSubValue}, c.scanTargets()...)
+		if err := rows.Scan(targets...); err != nil {
+			return Matchups{}, err
+		}
+		if mySubValue.Valid {
+			cell.MyDeckSub = &mySubValue.String
+		}
+		if oppSubValue.Valid {
+			cell.OppDeckSub = &oppSubValue.String
+		}
+		cell.MyDeck = deckName(cell.MyDeckMain, cell.MyDeckSub)
+		cell.OppDeck = deckName(cell.OppDeckMain, cell.OppDeckSub)
+
+		cell.Games = c.Total
+		cell.Wins = c.Wins
+		cell.Losses = c.Losses
+		cell.Draws = c.Draws
+		cell.WinRate = percent(c.Wins, c.Total)
+		cell.First = c.First
+		cell.Second = c.Second
+		cell.FirstWins = c.FirstWins
+		cell.SecondWins = c.SecondWins
+		cell.FirstWinRate = optionalPercent(c.FirstWins, c.First)
+		cell.SecondWinRate = optionalPercent(c.SecondWins, c.Second)
+		cell.WinRateCI = est.winRate(c.Wins, c.Total)
+		cell.FirstWinRateCI = est.firstWinRate(c.FirstWins, c.First)
+		cell.SecondWinRateCI = est.secondWinRate(c.SecondWins, c.Second)
+
+		myGames[cell.MyDeck] += c.Total
+		oppGames[cell.OppDeck] += c.Total
+		cells = append(cells, cell)
+	}
+	if err := rows.Err(); err != nil {
+		return Matchups{}, err
+	}
+
+	// 依列（我方牌組總場數）分組，列內再依格子場數降冪
+	sort.SliceStable(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if a.MyDeck != b.MyDeck {
+			if myGames[a.MyDeck] != myGames[b.MyDeck] {
+				return myGames[a.MyDeck] > myGames[b.MyDeck]
+			}
+			return a.MyDeck < b.MyDeck
+		}
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.OppDeck < b.OppDeck
+	})
+
+	return Matchups{
+		MyDecks:  sortedByGames(myGames),
+		OppDecks: sortedByGames(oppGames),
+		Cells:    cells,
+		MinGames: minGames,
+	}, nil
+}
+
+// deckName 組出牌組顯示名稱：大軸，或「大軸 / 小軸」
+func deckName(main string, sub *string) string {
+	if sub == nil {
+		return main
+	}
+	return main + " / " + *sub
+}
+
+// sortedByGames 依場數降冪（同場數依名稱）回傳名稱列表
+func sortedByGames(games map[string]int) []string {
+	names := make([]string, 0, len(games))
+	for name := range games {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if games[names[i]] != games[names[j]] {
+			return games[names[i]] > games[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,258 @@
+package stats
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// 階級變化的方向
+const (
+	RankPromotion = "promotion" // 升階
+	RankDemotion  = "demotion"  // 降階
+	RankChanged   = "change"    // 沒有階級表的遊戲無法比較高低，只記錄有變化
+)
+
+// RankPoint 時間軸上的一場對局（依對局順序）
+type RankPoint struct {
+	MatchID  string     `json:"matchId"`
+	Date     string     `json:"date"` // YYYY-MM-DD
+	PlayedAt *time.Time `json:"playedAt"`
+	Rank     string     `json:"rank"` // 階級代碼，e.g. "gold-4"
+	Tier     string     `json:"tier"`
+	Level    *int       `json:"level"`
+	Ordinal  int        `json:"ordinal"` // 在遊戲階級表中的位置（0 為最低）；沒有階級表或不在表中時為 -1
+	Result   string     `json:"result"`
+}
+
+// RankChange 相鄰兩場對局之間的階級變化（記錄對局時的階級，因此變化出現在下一場）
+type RankChange struct {
+	MatchID   string `json:"matchId"` // 變化後的第一場對局
+	Date      string `json:"date"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Direction string `json:"direction"` // "promotion" | "demotion" | "change"
+	Steps     int    `json:"steps"`     // 跨越的階數（沒有階級表時為 0）
+}
+
+// TierRow 單一 tier 的場數與勝率
+type TierRow struct {
+	Tier    string   `json:"tier"`
+	Games   int      `json:"games"`
+	Wins    int      `json:"wins"`
+	Losses  int      `json:"losses"`
+	Draws   int      `json:"draws"`
+	WinRate *float64 `json:"winRate"`
+
+	// 區間 / 收縮估計（僅在請求 ci 參數時提供）
+	WinRateCI *Estimate `json:"winRateCI,omitempty"`
+}
+
+// SeasonRankTimeline 一個賽季的階級變化
+type SeasonRankTimeline struct {
+	SeasonCode string       `json:"seasonCode"`
+	Games      int          `json:"games"`
+	StartRank  string       `json:"startRank"` // 第一場對局的階級
+	EndRank    string       `json:"endRank"`   // 最後一場對局的階級
+	PeakRank   string       `json:"peakRank"`  // 最高階級（沒有階級表時同 endRank）
+	Promotions int          `json:"promotions"`
+	Demotions  int          `json:"demotions"`
+	Timeline   []RankPoint  `json:"timeline"`
+	Changes    []RankChange `json:"changes"`
+	Tiers      []TierRow    `json:"tiers"` // 依階級表由低到高（沒有階級表時依出現順序）
+}
+
+// matchOrder 對局的先後順序：日期 → 兩者都有 played_at 時比 played_at → 建立時間
+type matchOrder struct {
+	date      string
+	playedAt  *time.Time
+	createdAt time.Time
+}
+
+func (m matchOrder) before(o matchOrder) bool {
+	if m.date != o.date {
+		return m.date < o.date
+	}
+	if m.playedAt != nil && o.playedAt != nil && !m.playedAt.Equal(*o.playedAt) {
+		return m.playedAt.Before(*o.playedAt)
+	}
+	return m.createdAt.Before(o.createdAt)
+}
+
+// rankMatch 查詢結果的一列
+type rankMatch struct {
+	matchOrder
+	season    string
+	playOrder string
+	point     RankPoint
+}
+
+// GetRankTimeline 依賽季整理有記錄階級的對局（不記錄階級的模式自動略過）：
+// 階級隨時間的變化、相鄰對局間的升降階、各 tier 的場數與勝率。
+// 對局依日期排序，同一天有 played_at 時依 played_at；賽季依第一場對局的日期排序。
+func GetRankTimeline(db *storage.DB, f models.MatchFilter, est *Estimator) ([]SeasonRankTimeline, error) {
+	est, err := est.withBaseline(db, f)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := loadRankMatches(db, f)
+	if err != nil {
+		return nil, err
+	}
+
+	ladder := rules.For(f.GameKey).Ranks()
+	ordinals := make(map[string]int, len(ladder))
+	for i, code := range ladder {
+		ordinals[code] = i
+	}
+
+	bySeason := map[string]*SeasonRankTimeline{}
+	seasons := []*SeasonRankTimeline{}
+	for _, m := range matches {
+		p := m.point
+		p.Ordinal = -1
+		if i, ok := ordinals[p.Rank]; ok {
+			p.Ordinal = i
+		}
+
+		s, ok := bySeason[m.season]
+		if !ok {
+			s = &SeasonRankTimeline{SeasonCode: m.season, Timeline: []RankPoint{}, Changes: []RankChange{}}
+			bySeason[m.season] = s
+			seasons = append(seasons, s)
+		}
+		s.Timeline = append(s.Timeline, p)
+	}
+
+	result := make([]SeasonRankTimeline, 0, len(seasons))
+	for _, s := range seasons {
+		s.summarize(ladder, est)
+		result = append(result, *s)
+	}
+	return result, nil
+}
+
+// loadRankMatches 有記錄階級的對局，依對局順序（見 matchOrder）
+func loadRankMatches(db *storage.DB, f models.MatchFilter) ([]rankMatch, error) {
+	from, args := filterSQL(f)
+
+	rows, err := db.Query(`
+		SELECT s.code, m.id, m.date, m.played_at, m.rank_tier, m.rank_level, m.play_order, m.result, m.created_at
+	`+from+` AND m.rank_tier IS NOT NULL ORDER BY m.date ASC, m.created_at ASC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []rankMatch
+	for rows.Next() {
+		var r rankMatch
+		var playedAt sql.NullTime
+		var level sql.NullInt64
+		if err := rows.Scan(&r.season, &r.point.MatchID, &r.point.Date, &playedAt,
+			&r.point.Tier, &level, &r.playOrder, &r.point.Result, &r.createdAt); err != nil {
+			return nil, err
+		}
+		r.point.Date = normalizeDate(r.point.Date)
+		if playedAt.Valid {
+			r.point.PlayedAt = &playedAt.Time
+		}
+		if level.Valid {
+			l := int(level.Int64)
+			r.point.Level = &l
+		}
+		r.point.Rank = models.Rank{Tier: r.point.Tier, Level: r.point.Level}.Code()
+		r.date, r.playedAt = r.point.Date, r.point.PlayedAt
+		matches = append(matches, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].before(matches[j].matchOrder) })
+	return matches, nil
+}
+
+// summarize 由 Timeline 算出升降階與各 tier 統計
+func (s *SeasonRankTimeline) summarize(ladder []string, est *Estimator) {
+	s.Games = len(s.Timeline)
+	first, last := s.Timeline[0], s.Timeline[len(s.Timeline)-1]
+	s.StartRank, s.EndRank, s.PeakRank = first.Rank, last.Rank, last.Rank
+
+	peak := -1
+	tierCounts := map[string]*counts{}
+	var tierOrder []string
+	for i, p := range s.Timeline {
+		if p.Ordinal > peak {
+			peak, s.PeakRank = p.Ordinal, p.Rank
+		}
+
+		c, ok := tierCounts[p.Tier]
+		if !ok {
+			c = &counts{}
+			tierCounts[p.Tier] = c
+			tierOrder = append(tierOrder, p.Tier)
+		}
+		c.Total++
+		switch p.Result {
+		case rules.ResultWin:
+			c.Wins++
+		case rules.ResultLoss:
+			c.Losses++
+		case rules.ResultDraw:
+			c.Draws++
+		}
+
+		if i == 0 || p.Rank == s.Timeline[i-1].Rank {
+			continue
+		}
+		prev := s.Timeline[i-1]
+		change := RankChange{MatchID: p.MatchID, Date: p.Date, From: prev.Rank, To: p.Rank, Direction: RankChanged}
+		if p.Ordinal >= 0 && prev.Ordinal >= 0 {
+			change.Steps = p.Ordinal - prev.Ordinal
+			if change.Steps > 0 {
+				change.Direction = RankPromotion
+				s.Promotions++
+			} else {
+				change.Direction = RankDemotion
+				change.Steps = -change.Steps
+				s.Demotions++
+			}
+		}
+		s.Changes = append(s.Changes, change)
+	}
+
+	// tier 依階級表的順序（"<tier>-<level>"），不在表中的 tier 排在最後、依出現順序
+	rank := map[string]int{}
+	for _, code := range ladder {
+		tier := models.ParseRankCode(code).Tier
+		if _, ok := rank[tier]; !ok {
+			rank[tier] = len(rank)
+		}
+	}
+	sort.SliceStable(tierOrder, func(i, j int) bool {
+		ri, okI := rank[tierOrder[i]]
+		rj, okJ := rank[tierOrder[j]]
+		if okI != okJ {
+			return okI
+		}
+		return okI && ri < rj
+	})
+
+	s.Tiers = make([]TierRow, 0, len(tierOrder))
+	for _, tier := range tierOrder {
+		c := tierCounts[tier]
+		s.Tiers = append(s.Tiers, TierRow{
+			Tier:      tier,
+			Games:     c.Total,
+			Wins:      c.Wins,
+			Losses:    c.Losses,
+			Draws:     c.Draws,
+			WinRate:   optionalPercent(c.Wins, c.Total),
+			WinRateCI: est.winRate(c.Wins, c.Total),
+		})
+	}
+}
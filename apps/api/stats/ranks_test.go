@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/rules"
+)
+
+// rankTimeline 依 ladder 填入 Tier / Level / Ordinal（同 GetRankTimeline）
+func rankTimeline(ladder []string, points ...[2]string) SeasonRankTimeline {
+	s := SeasonRankTimeline{SeasonCode: "S57", Changes: []RankChange{}}
+	for i, p := range points {
+		rank := models.ParseRankCode(p[0])
+		point := RankPoint{MatchID: string(rune('a' + i)), Date: "2026-09-01", Rank: p[0], Tier: rank.Tier, Level: rank.Level, Ordinal: -1, Result: p[1]}
+		for j, code := range ladder {
+			if code == p[0] {
+				point.Ordinal = j
+			}
+		}
+		s.Timeline = append(s.Timeline, point)
+	}
+	return s
+}
+
+func TestSeasonRankTimelineSummarize(t *testing.T) {
+	ladder := rules.For("master_duel").Ranks()
+	s := rankTimeline(ladder,
+		[2]string{"gold-5", rules.ResultWin},
+		[2]string{"gold-4", rules.ResultWin},
+		[2]string{"gold-4", rules.ResultLoss},
+		[2]string{"gold-5", rules.ResultLoss},
+		[2]string{"gold-5", rules.ResultWin},
+		[2]string{"gold-3", rules.ResultWin}, // 中間沒有記錄：一次跨兩階
+		[2]string{"platinum-5", rules.ResultDraw},
+	)
+	s.summarize(ladder, nil)
+
+	if s.Games != 7 || s.StartRank != "gold-5" || s.EndRank != "platinum-5" || s.PeakRank != "platinum-5" {
+		t.Errorf("games %d, start %s, end %s, peak %s", s.Games, s.StartRank, s.EndRank, s.PeakRank)
+	}
+	wantChanges := []RankChange{
+		{MatchID: "b", Date: "2026-09-01", From: "gold-5", To: "gold-4", Direction: RankPromotion, Steps: 1},
+		{MatchID: "d", Date: "2026-09-01", From: "gold-4", To: "gold-5", Direction: RankDemotion, Steps: 1},
+		{MatchID: "f", Date: "2026-09-01", From: "gold-5", To: "gold-3", Direction: RankPromotion, Steps: 2},
+		{MatchID: "g", Date: "2026-09-01", From: "gold-3", To: "platinum-5", Direction: RankPromotion, Steps: 3},
+	}
+	if !reflect.DeepEqual(s.Changes, wantChanges) || s.Promotions != 3 || s.Demotions != 1 {
+		t.Errorf("changes = %+v (%d up, %d down), want %+v", s.Changes, s.Promotions, s.Demotions, wantChanges)
+	}
+	if len(s.Tiers) != 2 {
+		t.Fatalf("tiers = %+v, want gold and platinum", s.Tiers)
+	}
+	gold, platinum := s.Tiers[0], s.Tiers[1]
+	if gold.Tier != "gold" || gold.Games != 6 || gold.Wins != 4 || gold.Losses != 2 || platinum.Tier != "platinum" || platinum.Draws != 1 {
+		t.Errorf("tiers = %+v", s.Tiers)
+	}
+}
+
+func TestSeasonRankTimelineTierOrder(t *testing.T) {
+	// tier 依階級表由低到高，不在表中的排在最後
+	ladder := rules.For("master_duel").Ranks()
+	s := rankTimeline(ladder,
+		[2]string{"diamond-1", rules.ResultLoss},
+		[2]string{"legend", rules.ResultWin},
+		[2]string{"bronze-2", rules.ResultWin},
+	)
+	s.summarize(ladder, nil)
+
+	var tiers []string
+	for _, row := range s.Tiers {
+		tiers = append(tiers, row.Tier)
+	}
+	if !reflect.DeepEqual(tiers, []string{"bronze", "diamond", "legend"}) {
+		t.Errorf("tier order = %v, want bronze, diamond, legend", tiers)
+	}
+	// legend 不在階級表中：與相鄰對局只記錄變化
+	if s.Changes[0].Direction != RankChanged || s.Changes[1].Direction != RankChanged || s.Promotions != 0 || s.Demotions != 0 {
+		t.Errorf("changes = %+v, want two unranked changes", s.Changes)
+	}
+	if s.PeakRank != "diamond-1" {
+		t.Errorf("peak = %s, want diamond-1", s.PeakRank)
+	}
+}
+
+func TestMatchOrder(t *testing.T) {
+	at := func(h int) *time.Time {
+		t := time.Date(2026, 9, 1, h, 0, 0, 0, time.UTC)
+		return &t
+	}
+	created := time.Date(2026, 9, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		a, b matchOrder
+		want bool
+	}{
+		{"earlier date", matchOrder{date: "2026-09-01", createdAt: created}, matchOrder{date: "2026-09-02", createdAt: created.Add(-time.Hour)}, true},
+		{"played_at on the same day", matchOrder{date: "2026-09-01", playedAt: at(10), createdAt: created}, matchOrder{date: "2026-09-01", playedAt: at(12), createdAt: created.Add(-time.Hour)}, true},
+		{"only one played_at falls back to created_at", matchOrder{date: "2026-09-01", playedAt: at(10), createdAt: created}, matchOrder{date: "2026-09-01", createdAt: created.Add(-time.Hour)}, false},
+		{"same played_at falls back to created_at", matchOrder{date: "2026-09-01", playedAt: at(10), createdAt: created}, matchOrder{date: "2026-09-01", playedAt: at(10), createdAt: created.Add(time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.a.before(tt.b); got != tt.want {
+			t.Errorf("%s: before = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
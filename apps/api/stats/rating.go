@@ -0,0 +1,181 @@
+package stats
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/rules"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// RatingPoint 積分曲線上的一場對局（依對局順序）
+type RatingPoint struct {
+	MatchID   string     `json:"matchId"`
+	Date      string     `json:"date"` // YYYY-MM-DD
+	PlayedAt  *time.Time `json:"playedAt"`
+	Before    *int       `json:"before"`
+	After     *int       `json:"after"`
+	Delta     *int       `json:"delta"`
+	Rating    *int       `json:"rating"`    // 對局後的積分：after；沒有 after 時由上一場的積分 + delta 推算
+	Estimated bool       `json:"estimated"` // rating 是推算的
+	Result    string     `json:"result"`
+}
+
+// RatingEvent 一個活動（同一賽季的同一模式，e.g. S49 的 DC）的積分曲線與統計
+type RatingEvent struct {
+	Mode        string   `json:"mode"`
+	SeasonCode  string   `json:"seasonCode"`
+	Games       int      `json:"games"`
+	Wins        int      `json:"wins"`
+	Losses      int      `json:"losses"`
+	Draws       int      `json:"draws"`
+	WinRate     *float64 `json:"winRate"`
+	StartRating *int     `json:"startRating"` // 第一個已知的積分（第一場有 before 時為 before）
+	EndRating   *int     `json:"endRating"`   // 最後一個已知的積分
+	NetChange   *int     `json:"netChange"`   // endRating - startRating
+	Peak        *int     `json:"peak"`        // 最高積分（含 before）
+	PeakDate    *string  `json:"peakDate"`
+	Low         *int     `json:"low"` // 最低積分（含 before）
+	LowDate     *string  `json:"lowDate"`
+
+	Series []RatingPoint `json:"series"`
+
+	// 區間 / 收縮估計（僅在請求 ci 參數時提供）
+	WinRateCI *Estimate `json:"winRateCI,omitempty"`
+}
+
+// ratingMatch 查詢結果的一列
+type ratingMatch struct {
+	matchOrder
+	season string
+	mode   string
+	point  RatingPoint
+}
+
+// GetRatingHistory 依活動（模式 + 賽季）整理有記錄積分的對局：積分隨時間的變化與最高 / 最低積分。
+// 活動依第一場對局的順序排列；沒有記錄積分的對局不列入。
+func GetRatingHistory(db *storage.DB, f models.MatchFilter, est *Estimator) ([]RatingEvent, error) {
+	est, err := est.withBaseline(db, f)
+	if err != nil {
+		return nil, err
+	}
+	from, args := filterSQL(f)
+
+	rows, err := db.Query(`
+		SELECT s.code, m.mode, m.id, m.date, m.played_at, m.rating_before, m.rating_after, m.rating_delta, m.result, m.created_at
+	`+from+` AND (m.rating_before IS NOT NULL OR m.rating_after IS NOT NULL OR m.rating_delta IS NOT NULL)
+		ORDER BY m.date ASC, m.created_at ASC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []ratingMatch
+	for rows.Next() {
+		var r ratingMatch
+		var playedAt sql.NullTime
+		var before, after, delta sql.NullInt64
+		if err := rows.Scan(&r.season, &r.mode, &r.point.MatchID, &r.point.Date, &playedAt,
+			&before, &after, &delta, &r.point.Result, &r.createdAt); err != nil {
+			return nil, err
+		}
+		r.point.Date = normalizeDate(r.point.Date)
+		if playedAt.Valid {
+			r.point.PlayedAt = &playedAt.Time
+		}
+		r.point.Before, r.point.After, r.point.Delta = nullInt(before), nullInt(after), nullInt(delta)
+		r.date, r.playedAt = r.point.Date, r.point.PlayedAt
+		matches = append(matches, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].before(matches[j].matchOrder) })
+
+	byEvent := map[[2]string]*RatingEvent{}
+	events := []*RatingEvent{}
+	for _, m := range matches {
+		key := [2]string{m.mode, m.season}
+		e, ok := byEvent[key]
+		if !ok {
+			e = &RatingEvent{Mode: m.mode, SeasonCode: m.season, Series: []RatingPoint{}}
+			byEvent[key] = e
+			events = append(events, e)
+		}
+		e.Series = append(e.Series, m.point)
+	}
+
+	result := make([]RatingEvent, 0, len(events))
+	for _, e := range events {
+		e.summarize(est)
+		result = append(result, *e)
+	}
+	return result, nil
+}
+
+// summarize 推算各場的積分並算出勝敗、起訖與最高 / 最低積分
+func (e *RatingEvent) summarize(est *Estimator) {
+	var last *int // 上一個已知的積分
+	observe := func(v int, date string) {
+		if e.StartRating == nil {
+			start := v
+			e.StartRating = &start
+		}
+		if e.Peak == nil || v > *e.Peak {
+			peak, d := v, date
+			e.Peak, e.PeakDate = &peak, &d
+		}
+		if e.Low == nil || v < *e.Low {
+			low, d := v, date
+			e.Low, e.LowDate = &low, &d
+		}
+		end := v
+		e.EndRating, last = &end, &end
+	}
+
+	for i := range e.Series {
+		p := &e.Series[i]
+		e.Games++
+		switch p.Result {
+		case rules.ResultWin:
+			e.Wins++
+		case rules.ResultLoss:
+			e.Losses++
+		case rules.ResultDraw:
+			e.Draws++
+		}
+
+		if p.Before != nil {
+			observe(*p.Before, p.Date)
+		}
+		switch {
+		case p.After != nil:
+			p.Rating = p.After
+		case p.Delta != nil && last != nil:
+			rating := *last + *p.Delta
+			p.Rating, p.Estimated = &rating, true
+		default:
+			last = nil // 不知道這場之後的積分，後續只有 delta 的對局無法推算
+			continue
+		}
+		observe(*p.Rating, p.Date)
+	}
+
+	if e.StartRating != nil {
+		change := *e.EndRating - *e.StartRating
+		e.NetChange = &change
+	}
+	e.WinRate = optionalPercent(e.Wins, e.Games)
+	e.WinRateCI = est.winRate(e.Wins, e.Games)
+}
+
+// nullInt sql.NullInt64 → *int
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/harvc/duellog/apps/api/rules"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestRatingEventSummarize(t *testing.T) {
+	e := RatingEvent{Series: []RatingPoint{
+		{Date: "2026-09-01", Before: intPtr(1500), After: intPtr(1510), Result: rules.ResultWin},
+		{Date: "2026-09-02", Delta: intPtr(-10), Result: rules.ResultLoss},
+		// 只有 before：這場之後的積分不明，下一場只有 delta 時無法推算
+		{Date: "2026-09-03", Before: intPtr(1490), Result: rules.ResultLoss},
+		{Date: "2026-09-04", Delta: intPtr(20), Result: rules.ResultWin},
+		{Date: "2026-09-05", After: intPtr(1530), Result: rules.ResultWin},
+		{Date: "2026-09-06", Delta: intPtr(-5), Result: rules.ResultDraw},
+	}}
+	e.summarize(nil)
+
+	want := []struct {
+		rating    *int
+		estimated bool
+	}{
+		{intPtr(1510), false},
+		{intPtr(1500), true},
+		{nil, false},
+		{nil, false},
+		{intPtr(1530), false},
+		{intPtr(1525), true},
+	}
+	for i, w := range want {
+		p := e.Series[i]
+		if (p.Rating == nil) != (w.rating == nil) || (p.Rating != nil && *p.Rating != *w.rating) || p.Estimated != w.estimated {
+			t.Errorf("series[%d] (%s): rating %v estimated %v, want %v estimated %v", i, p.Date, deref(p.Rating), p.Estimated, deref(w.rating), w.estimated)
+		}
+	}
+
+	if e.Games != 6 || e.Wins != 3 || e.Losses != 2 || e.Draws != 1 {
+		t.Errorf("games %d, W/L/D %d/%d/%d; want 6, 3/2/1", e.Games, e.Wins, e.Losses, e.Draws)
+	}
+	if *e.StartRating != 1500 || *e.EndRating != 1525 || *e.NetChange != 25 {
+		t.Errorf("start %d, end %d, net %d; want 1500, 1525, +25", *e.StartRating, *e.EndRating, *e.NetChange)
+	}
+	if *e.Peak != 1530 || *e.PeakDate != "2026-09-05" || *e.Low != 1490 || *e.LowDate != "2026-09-03" {
+		t.Errorf("peak %d on %s, low %d on %s; want 1530 on 2026-09-05, 1490 on 2026-09-03", *e.Peak, *e.PeakDate, *e.Low, *e.LowDate)
+	}
+	if e.WinRateCI != nil {
+		t.Errorf("winRateCI = %+v without an estimator, want nil", e.WinRateCI)
+	}
+}
+
+func TestRatingEventSummarizeWithoutKnownRating(t *testing.T) {
+	e := RatingEvent{Series: []RatingPoint{
+		{Date: "2026-09-01", Delta: intPtr(12), Result: rules.ResultWin},
+		{Date: "2026-09-02", Delta: intPtr(-8), Result: rules.ResultLoss},
+	}}
+	e.summarize(nil)
+	for i, p := range e.Series {
+		if p.Rating != nil || p.Estimated {
+			t.Errorf("series[%d]: rating %v, want unknown", i, *p.Rating)
+		}
+	}
+	if e.StartRating != nil || e.EndRating != nil || e.NetChange != nil || e.Peak != nil || e.Low != nil {
+		t.Errorf("only deltas: %+v, want no start / end / peak / low", e)
+	}
+	if e.WinRate == nil || *e.WinRate != 50 {
+		t.Errorf("winRate = %v, want 50", e.WinRate)
+	}
+}
+
+// deref 印出 *int（nil 印成 <nil>）
+func deref(p *int) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
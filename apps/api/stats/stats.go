@@ -0,0 +1,107 @@
+// Package stats 以 SQL 聚合計算對局統計，口徑固定（見 docs/spec.md §5.3）。
+//
+// 所有統計都基於「篩選後的 matches 集合 N」，篩選條件與 GET /matches 相同，
+// 讓 API、CLI 與前端拿到的數字一致。百分比一律以 0-100 表示。
+// 平手（D）計入場數 N 但不算勝也不算敗，勝 + 敗 + 平 = N。
+package stats
+
+import (
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/rules"
+)
+
+const (
+	playOrderFirst  = rules.PlayOrderFirst
+	playOrderSecond = rules.PlayOrderSecond
+)
+
+// baseFrom 統計查詢共用的 FROM/JOIN 片段，別名與 GET /matches 相同，
+// 因此可直接套用 models.MatchFilter.Conditions()。
+const baseFrom = `
+	FROM matches m
+	JOIN seasons s ON m.season_id = s.id
+	JOIN decks my_deck ON m.my_deck_id = my_deck.id
+	JOIN decks opp_deck ON m.opp_deck_id = opp_deck.id
+	WHERE 1=1
+`
+
+// countColumns 各統計共用的計數欄位（順序需與 counts.scanTargets 一致）
+const countColumns = `
+	COUNT(*),
+	COALESCE(SUM(CASE WHEN m.result = 'W' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.result = 'L' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.result = 'D' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderFirst + `' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderSecond + `' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderFirst + `' AND m.result = 'W' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderFirst + `' AND m.result = 'L' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderFirst + `' AND m.result = 'D' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderSecond + `' AND m.result = 'W' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderSecond + `' AND m.result = 'L' THEN 1 ELSE 0 END), 0),
+	COALESCE(SUM(CASE WHEN m.play_order = '` + playOrderSecond + `' AND m.result = 'D' THEN 1 ELSE 0 END), 0)
+`
+
+// counts 一組對局的原始計數
+type counts struct {
+	Total        int
+	Wins         int
+	Losses       int
+	Draws        int
+	First        int
+	Second       int
+	FirstWins    int
+	FirstLosses  int
+	FirstDraws   int
+	SecondWins   int
+	SecondLosses int
+	SecondDraws  int
+}
+
+func (c *counts) scanTargets() []interface{} {
+	return []interface{}{
+		&c.Total, &c.Wins, &c.Losses, &c.Draws,
+		&c.First, &c.Second,
+		&c.FirstWins, &c.FirstLosses, &c.FirstDraws,
+		&c.SecondWins, &c.SecondLosses, &c.SecondDraws,
+	}
+}
+
+func (c counts) add(o counts) counts {
+	return counts{
+		Total:        c.Total + o.Total,
+		Wins:         c.Wins + o.Wins,
+		Losses:       c.Losses + o.Losses,
+		Draws:        c.Draws + o.Draws,
+		First:        c.First + o.First,
+		Second:       c.Second + o.Second,
+		FirstWins:    c.FirstWins + o.FirstWins,
+		FirstLosses:  c.FirstLosses + o.FirstLosses,
+		FirstDraws:   c.FirstDraws + o.FirstDraws,
+		SecondWins:   c.SecondWins + o.SecondWins,
+		SecondLosses: c.SecondLosses + o.SecondLosses,
+		SecondDraws:  c.SecondDraws + o.SecondDraws,
+	}
+}
+
+// filterSQL 組出 FROM ... WHERE 片段與參數
+func filterSQL(f models.MatchFilter) (string, []interface{}) {
+	conditions, args := f.Conditions()
+	return baseFrom + conditions, args
+}
+
+// percent 回傳 part/whole*100；whole 為 0 時回傳 0
+func percent(part, whole int) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
+
+// optionalPercent 與 percent 相同，但 whole 為 0 時回傳 nil
+func optionalPercent(part, whole int) *float64 {
+	if whole <= 0 {
+		return nil
+	}
+	v := percent(part, whole)
+	return &v
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"github.com/harvc/duellog/apps/api/models"
+	"github.com/harvc/duellog/apps/api/storage"
+)
+
+// Summary KPI 統計（對應前端 SeasonStats 的 KPI 欄位；比率為 nil 表示分母為 0）
+type Summary struct {
+	Total         int      `json:"total"`
+	Wins          int      `json:"wins"`
+	Losses        int      `json:"losses"`
+	Draws         int      `json:"draws"`
+	WinRate       *float64 `json:"winRate"`  // count(W) / N（平手計入 N）
+	DrawRate      *float64 `json:"drawRate"` // count(D) / N
+	FirstCount    int      `json:"firstCount"`
+	SecondCount   int      `json:"secondCount"`
+	FirstWins     int      `json:"firstWins"`
+	SecondWins    int      `json:"secondWins"`
+	FirstRate     *float64 `json:"firstRate"`     // count(先攻) / N
+	FirstWinRate  *float64 `json:"firstWinRate"`  // count(W & 先攻) / count(先攻)
+	SecondWinRate *float64 `json:"secondWinRate"` // count(W & 後攻) / count(後攻)
+
+	// 區間 / 收縮估計（僅在請求 ci 參數時提供）
+	WinRateCI       *Estimate `json:"winRateCI,omitempty"`
+	FirstWinRateCI  *Estimate `json:"firstWinRateCI,omitempty"`
+	SecondWinRateCI *Estimate `json:"secondWinRateCI,omitempty"`
+}
+
+// GetSummary 計算篩選後對局集合的 KPI；est 不為 nil 時附上區間估計
+func GetSummary(db *storage.DB, f models.MatchFilter, est *Estimator) (Summary, error) {
+	est, err := est.withBaseline(db, f)
+	if err != nil {
+		return Summary{}, err
+	}
+	from, args := filterSQL(f)
+
+	var c counts
+	if err := db.QueryRow("SELECT "+countColumns+from, args...).Scan(c.scanTargets()...); err != nil {
+		return Summary{}, err
+	}
+
+	return Summary{
+		Total:         c.Total,
+		Wins:          c.Wins,
+		Losses:        c.Losses,
+		Draws:         c.Draws,
+		WinRate:       optionalPercent(c.Wins, c.Total),
+		DrawRate:      optionalPercent(c.Draws, c.Total),
+		FirstCount:    c.First,
+		SecondCount:   c.Second,
+		FirstWins:     c.FirstWins,
+		SecondWins:    c.SecondWins,
+		FirstRate:     optionalPercent(c.First, c.Total),
+		FirstWinRate:  optionalPercent(c.FirstWins, c.First),
+		SecondWinRate: optionalPercent(c.SecondWins, c.Second),
+
+		WinRateCI:       est.winRate(c.Wins, c.Total),
+		FirstWinRateCI:  est.firstWinRate(c.FirstWins, c.First),
+		SecondWinRateCI: est.secondWinRate(c.SecondWins, c.Second),
+	}, nil
+}
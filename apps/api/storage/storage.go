@@ -0,0 +1,212 @@
+// Package storage 包裝 database/sql，讓同一套 handler / SQL 可同時跑在 SQLite 與 PostgreSQL 上。
+//
+// 程式碼中的 SQL 一律使用 `?` 佔位符；連到 PostgreSQL 時會自動改寫為 $1, $2 ...
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Dialect 資料庫種類
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// Querier DB 與 Tx 共同的查詢介面
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// DB 帶有 Dialect 的資料庫連線
+type DB struct {
+	sql     *sql.DB
+	Dialect Dialect
+}
+
+// Open 依設定開啟資料庫：
+// databaseURL 為 postgres:// 或 postgresql:// 開頭時連到 PostgreSQL，否則開啟 sqlitePath 的 SQLite 檔案。
+func Open(databaseURL, sqlitePath string) (*DB, error) {
+	if IsPostgresURL(databaseURL) {
+		db, err := sql.Open("pgx", databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return &DB{sql: db, Dialect: Postgres}, nil
+	}
+
+	db, err := sql.Open("sqlite", sqliteDSN(sqlitePath))
+	if err != nil {
+		return nil, err
+	}
+	return &DB{sql: db, Dialect: SQLite}, nil
+}
+
+// sqliteDSN 為 SQLite 檔案加上連線參數：
+// 寫入衝突時最多等 5 秒，transaction 一開始就取得寫入鎖（避免兩個 transaction 互相等待升級鎖）
+func sqliteDSN(path string) string {
+	if strings.Contains(path, "?") {
+		return path // 已自行指定參數
+	}
+	return path + "?_pragma=busy_timeout(5000)&_txlock=immediate"
+}
+
+// IsUniqueViolation 判斷錯誤是否為 UNIQUE / PRIMARY KEY 衝突
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// IsPostgresURL 判斷連線字串是否為 PostgreSQL
+func IsPostgresURL(databaseURL string) bool {
+	return strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://")
+}
+
+// Ping 測試連線
+func (db *DB) Ping() error { return db.sql.Ping() }
+
+// Close 關閉連線
+func (db *DB) Close() error { return db.sql.Close() }
+
+// Exec 執行不回傳資料列的語句
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.sql.Exec(db.Rebind(query), args...)
+}
+
+// Query 執行查詢
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.sql.Query(db.Rebind(query), args...)
+}
+
+// QueryRow 執行單列查詢
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.sql.QueryRow(db.Rebind(query), args...)
+}
+
+// Begin 開始 transaction
+func (db *DB) Begin() (*Tx, error) {
+	tx, err := db.sql.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, dialect: db.Dialect}, nil
+}
+
+// Rebind 將 `?` 佔位符改寫成該 Dialect 的格式（字串常值、識別字與行註解內的 ? 不受影響）
+func (db *DB) Rebind(query string) string {
+	return rebind(db.Dialect, query)
+}
+
+// Tx 帶有 Dialect 的 transaction
+type Tx struct {
+	tx      *sql.Tx
+	dialect Dialect
+}
+
+// Exec 執行不回傳資料列的語句
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.tx.Exec(rebind(tx.dialect, query), args...)
+}
+
+// Query 執行查詢
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.tx.Query(rebind(tx.dialect, query), args...)
+}
+
+// QueryRow 執行單列查詢
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.tx.QueryRow(rebind(tx.dialect, query), args...)
+}
+
+// Commit 提交
+func (tx *Tx) Commit() error { return tx.tx.Commit() }
+
+// Rollback 回滾（已 Commit 後呼叫不會有作用）
+func (tx *Tx) Rollback() error { return tx.tx.Rollback() }
+
+func rebind(dialect Dialect, query string) string {
+	if dialect != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote byte // 目前所在的引號（' 或 "），0 表示不在引號內
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			// 行註解原樣保留
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+		case ch == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// TableExists 檢查資料表是否存在
+func (db *DB) TableExists(table string) (bool, error) {
+	var query string
+	switch db.Dialect {
+	case Postgres:
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?"
+	default:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	}
+	var count int
+	if err := db.QueryRow(query, table).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ColumnExists 檢查資料表是否有指定欄位
+func (db *DB) ColumnExists(table, column string) (bool, error) {
+	var query string
+	switch db.Dialect {
+	case Postgres:
+		query = "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?"
+	default:
+		query = "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	}
+	var count int
+	if err := db.QueryRow(query, table, column).Scan(&count); err != nil {
+		return false, fmt.Errorf("inspect %s.%s: %w", table, column, err)
+	}
+	return count > 0, nil
+}
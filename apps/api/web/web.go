@@ -0,0 +1,22 @@
+// Package web 內嵌前端建置結果（apps/web 執行 `npm run build` 會輸出到 web/static/dist）。
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:static
+var static embed.FS
+
+// Dist 回傳內嵌的前端檔案；若建置時尚未產生 dist/index.html，ok 為 false。
+func Dist() (dist fs.FS, ok bool) {
+	sub, err := fs.Sub(static, "static/dist")
+	if err != nil {
+		return nil, false
+	}
+	if _, err := fs.Stat(sub, "index.html"); err != nil {
+		return nil, false
+	}
+	return sub, true
+}
@@ -3,8 +3,8 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
 import { matchesService } from '../services/matchesService'
 import { decksService } from '../services/decksService'
 import { useTheme } from '../contexts/ThemeContext'
-import { getCurrentSeasonCode } from '../utils/season'
-import type { Match } from '../types/match'
+import type { Match, MatchResult, PlayOrder, RatingValue } from '../types/match'
+import { getTodayYMD } from '../utils/season'
 
 interface DefaultValues {
   date?: string
@@ -19,28 +19,55 @@ interface MatchFormProps {
   onSuccess: () => void
   defaultValues?: DefaultValues
   editMatch?: Match  // 如果有值，代表是編輯模式
-  seasonCode?: string
+  seasonCode?: string // 新增時指定的賽季（省略時由後端依日期推算）
   mode?: MatchMode
 }
 
 type MatchMode = 'Ranked' | 'Rating' | 'DC'
 
-// 階級選項
-const RANK_TIERS = ['銅', '銀', '金', '白金', '鑽石', '大師'] as const
-const RANK_LEVELS = ['V', 'IV', 'III', 'II', 'I'] as const
-
-// 從階級字串解析 tier 和 level
-function parseRank(rank: string): { tier: string; level: string } {
-  for (const tier of RANK_TIERS) {
-    if (rank.startsWith(tier)) {
-      // 移除 tier 並去除空格
-      const level = rank.replace(tier, '').trim()
-      if (RANK_LEVELS.includes(level as typeof RANK_LEVELS[number])) {
-        return { tier, level }
-      }
-    }
+// 階級選項（階級代碼為 "<tier>-<level>"，e.g. "gold-4" = 金 IV）
+const RANK_TIERS = [
+  { code: 'bronze', label: '銅' },
+  { code: 'silver', label: '銀' },
+  { code: 'gold', label: '金' },
+  { code: 'platinum', label: '白金' },
+  { code: 'diamond', label: '鑽石' },
+  { code: 'master', label: '大師' },
+] as const
+const RANK_LEVELS = [
+  { level: 5, label: 'V' },
+  { level: 4, label: 'IV' },
+  { level: 3, label: 'III' },
+  { level: 2, label: 'II' },
+  { level: 1, label: 'I' },
+] as const
+
+// 從階級代碼解析 tier 和 level
+function parseRank(rank: string): { tier: string; level: number } {
+  const [tier, level] = rank.split('-')
+  const n = Number(level)
+  if (RANK_TIERS.some(t => t.code === tier) && RANK_LEVELS.some(l => l.level === n)) {
+    return { tier, level: n }
   }
-  return { tier: '金', level: 'V' }
+  return { tier: 'gold', level: 5 }
+}
+
+// 階級代碼的顯示名稱，e.g. "gold-4" → "金 IV"
+function rankLabel(tier: string, level: number): string {
+  const tierLabel = RANK_TIERS.find(t => t.code === tier)?.label ?? tier
+  const levelLabel = RANK_LEVELS.find(l => l.level === level)?.label ?? String(level)
+  return `${tierLabel} ${levelLabel}`
+}
+
+// 積分 → 輸入框的值
+function ratingInput(value: number | null | undefined): string {
+  return value == null ? '' : String(value)
+}
+
+// 輸入框的值 → 積分（空白或非整數視為未填）
+function parseRatingInput(value: string): number | null {
+  const n = Number(value.trim())
+  return value.trim() === '' || !Number.isInteger(n) ? null : n
 }
 
 export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatch, seasonCode, mode: modeFromParent }: MatchFormProps) {
@@ -69,7 +96,7 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
   const initialData = isEditMode ? {
     date: editMatch.date.split('T')[0],
     mode: editMatch.mode,
-    rank: editMatch.rank,
+    rank: editMatch.rank ?? '',
     myDeckMain: editMatch.myDeck.main,
     myDeckSub: editMatch.myDeck.sub || '無',
     oppDeckMain: editMatch.oppDeck.main,
@@ -77,17 +104,23 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
     playOrder: editMatch.playOrder,
     result: editMatch.result,
     note: editMatch.note || '',
+    ratingBefore: ratingInput(editMatch.rating?.before),
+    ratingAfter: ratingInput(editMatch.rating?.after),
+    ratingDelta: ratingInput(editMatch.rating?.delta),
   } : {
-    date: defaultValues?.date?.split('T')[0] || new Date().toISOString().split('T')[0],
+    date: defaultValues?.date?.split('T')[0] || getTodayYMD(),
     mode: defaultValues?.mode || modeFromParent || 'Ranked',
-    rank: defaultValues?.rank || '金 V',
+    rank: defaultValues?.rank || 'gold-5',
     myDeckMain: defaultValues?.myDeckMain || '',
     myDeckSub: defaultValues?.myDeckSub || '無',
     oppDeckMain: '',
     oppDeckSub: '無',
-    playOrder: '先攻' as const,
+    playOrder: 'first' as PlayOrder,
     result: 'W' as const,
     note: '',
+    ratingBefore: '',
+    ratingAfter: '',
+    ratingDelta: '',
   }
 
   // 解析預設階級
@@ -97,14 +130,17 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
   const [date, setDate] = useState(initialData.date)
   const [mode, setMode] = useState<MatchMode>(initialData.mode)
   const [rankTier, setRankTier] = useState<string>(defaultRank.tier)
-  const [rankLevel, setRankLevel] = useState<string>(defaultRank.level)
+  const [rankLevel, setRankLevel] = useState<number>(defaultRank.level)
   const [myDeckMain, setMyDeckMain] = useState(initialData.myDeckMain)
   const [myDeckSub, setMyDeckSub] = useState(initialData.myDeckSub)
   const [oppDeckMain, setOppDeckMain] = useState(initialData.oppDeckMain)
   const [oppDeckSub, setOppDeckSub] = useState(initialData.oppDeckSub)
-  const [playOrder, setPlayOrder] = useState<'先攻' | '後攻'>(initialData.playOrder)
-  const [result, setResult] = useState<'W' | 'L'>(initialData.result)
+  const [playOrder, setPlayOrder] = useState<PlayOrder>(initialData.playOrder)
+  const [result, setResult] = useState<MatchResult>(initialData.result)
   const [note, setNote] = useState(initialData.note)
+  const [ratingBefore, setRatingBefore] = useState(initialData.ratingBefore)
+  const [ratingAfter, setRatingAfter] = useState(initialData.ratingAfter)
+  const [ratingDelta, setRatingDelta] = useState(initialData.ratingDelta)
 
   // 搜尋狀態
   const [myDeckSearch, setMyDeckSearch] = useState('')
@@ -137,11 +173,22 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
     return allDecks.filter(d => d.toLowerCase().includes(oppSubSearch.toLowerCase()))
   }, [oppSubSearch, allDecks])
 
-  // 組合階級字串（tier 和 level 之間加空格，與資料庫格式一致）
+  // 組合階級代碼（e.g. "gold-4"，與資料庫格式一致）
   // 非 Ranked 模式時：UI 不顯示 rank；送出空字串讓 API 端以 DB 需求補佔位值。
-  const rank = mode === 'Ranked' ? `${rankTier} ${rankLevel}` : ''
-
-  const seasonCodeForCreate = seasonCode ?? getCurrentSeasonCode()
+  const rank = mode === 'Ranked' ? `${rankTier}-${rankLevel}` : ''
+
+  // 組合積分：Ranked 不送；before、after 都有時不送 delta，由後端計算（避免修改後與舊的 delta 不符）
+  const rating = useMemo((): RatingValue | undefined => {
+    if (mode === 'Ranked') return undefined
+    const before = parseRatingInput(ratingBefore)
+    const after = parseRatingInput(ratingAfter)
+    const delta = before !== null && after !== null ? null : parseRatingInput(ratingDelta)
+    if (before === null && after === null && delta === null) {
+      // 編輯時全部清空代表清除積分
+      return isEditMode ? {} : undefined
+    }
+    return { before, after, delta }
+  }, [mode, ratingBefore, ratingAfter, ratingDelta, isEditMode])
 
   // 處理副軸值（空白、「無」都視為 null）
   const getSubValue = (sub: string, subSearch: string) => {
@@ -154,10 +201,12 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
   const createMutation = useMutation({
     mutationFn: () => matchesService.createMatch({
       gameKey: 'master_duel',
-      seasonCode: seasonCodeForCreate,
+      // 沒有指定賽季時由後端依日期推算；有指定時後端會檢查與日期是否相符
+      seasonCode,
       date,
       mode,
       rank,
+      rating,
       myDeck: { main: myDeckMain || myDeckSearch, sub: getSubValue(myDeckSub, mySubSearch) },
       oppDeck: { main: oppDeckMain || oppDeckSearch, sub: getSubValue(oppDeckSub, oppSubSearch) },
       playOrder,
@@ -176,6 +225,7 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
       date,
       mode,
       rank,
+      rating,
       myDeck: { main: myDeckMain || myDeckSearch, sub: getSubValue(myDeckSub, mySubSearch) },
       oppDeck: { main: oppDeckMain || oppDeckSearch, sub: getSubValue(oppDeckSub, oppSubSearch) },
       playOrder,
@@ -269,19 +319,19 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
               {/* Level 標題列 */}
               <div className="grid grid-cols-6 gap-1 mb-2">
                 <div></div>
-                {RANK_LEVELS.map(level => (
+                {RANK_LEVELS.map(({ level, label }) => (
                   <div key={level} className={`text-center text-xs font-medium ${isDark ? 'text-gray-400' : 'text-gray-500'}`}>
-                    {level}
+                    {label}
                   </div>
                 ))}
               </div>
               {/* Tier 行 */}
-              {RANK_TIERS.map(tier => (
+              {RANK_TIERS.map(({ code: tier, label: tierLabel }) => (
                 <div key={tier} className="grid grid-cols-6 gap-1 mb-1">
                   <div className={`text-xs font-medium flex items-center ${isDark ? 'text-gray-300' : 'text-gray-700'}`}>
-                    {tier}
+                    {tierLabel}
                   </div>
-                  {RANK_LEVELS.map(level => {
+                  {RANK_LEVELS.map(({ level }) => {
                     const isSelected = rankTier === tier && rankLevel === level
                     return (
                       <button
@@ -303,12 +353,49 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
                 </div>
               ))}
               <div className={`mt-2 text-sm text-center ${isDark ? 'text-gray-400' : 'text-gray-600'}`}>
-                已選擇：<span className="font-bold text-indigo-400">{rank}</span>
+                已選擇：<span className="font-bold text-indigo-400">{rankLabel(rankTier, rankLevel)}</span>
               </div>
             </div>
           )}
         </div>
 
+        {/* 積分（Rating / DC） */}
+        {mode !== 'Ranked' && (
+          <div>
+            <label className={labelClass}>積分（選填）</label>
+            <div className="grid grid-cols-3 gap-2">
+              <input
+                type="number"
+                inputMode="numeric"
+                placeholder="對局前"
+                value={ratingBefore}
+                onChange={(e) => setRatingBefore(e.target.value)}
+                className={inputClass}
+              />
+              <input
+                type="number"
+                inputMode="numeric"
+                placeholder="對局後"
+                value={ratingAfter}
+                onChange={(e) => setRatingAfter(e.target.value)}
+                className={inputClass}
+              />
+              <input
+                type="number"
+                inputMode="numeric"
+                placeholder="增減"
+                value={ratingDelta}
+                onChange={(e) => setRatingDelta(e.target.value)}
+                disabled={ratingBefore.trim() !== '' && ratingAfter.trim() !== ''}
+                className={inputClass}
+              />
+            </div>
+            <p className={`mt-1 text-xs ${isDark ? 'text-gray-500' : 'text-gray-400'}`}>
+              填任兩項即可自動算出第三項；也可以只填增減
+            </p>
+          </div>
+        )}
+
         {/* 我方牌組 */}
         <div className="grid grid-cols-2 gap-4">
           <div className="relative">
@@ -475,22 +562,23 @@ export default function MatchForm({ onCancel, onSuccess, defaultValues, editMatc
             <label className={labelClass}>先後攻</label>
             <select
               value={playOrder}
-              onChange={(e) => setPlayOrder(e.target.value as '先攻' | '後攻')}
+              onChange={(e) => setPlayOrder(e.target.value as PlayOrder)}
               className={inputClass}
             >
-              <option value="先攻">先攻</option>
-              <option value="後攻">後攻</option>
+              <option value="first">先攻</option>
+              <option value="second">後攻</option>
             </select>
           </div>
           <div>
             <label className={labelClass}>結果</label>
             <select
               value={result}
-              onChange={(e) => setResult(e.target.value as 'W' | 'L')}
+              onChange={(e) => setResult(e.target.value as MatchResult)}
               className={inputClass}
             >
               <option value="W">勝 (W)</option>
               <option value="L">敗 (L)</option>
+              <option value="D">平手 (D)</option>
             </select>
           </div>
         </div>
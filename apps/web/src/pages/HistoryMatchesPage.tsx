@@ -7,6 +7,7 @@ import { useTheme } from '../contexts/ThemeContext'
 import MatchForm from '../components/MatchForm'
 import type { Match } from '../types/match'
 import { buildSeasonStats } from '../utils/stats'
+import { getTodayYMD } from '../utils/season'
 
 // 視圖模式
 type ViewMode = 'both' | 'stats' | 'records'
@@ -19,7 +20,7 @@ interface VirtualizedTableProps {
   matches: Match[]
   isDark: boolean
   viewMode: ViewMode
-  getRankColor: (rank: string, isDark: boolean) => string
+  getRankColor: (tier: string | null, isDark: boolean) => string
   getDeckColor: (deckName: string) => { bg: string; text: string }
   onEdit: (match: Match) => void
   onDelete: (id: string) => void
@@ -115,12 +116,12 @@ function VirtualizedTable({
                     <div className="flex items-center gap-1 flex-wrap">
                       {match.mode !== 'Ranked' && (
                         <span className={`px-2 py-0.5 text-xs font-bold rounded whitespace-nowrap ${getModeTagColor(match.mode as MatchMode, isDark)}`}>
-                          {match.mode}
+                          {match.labels.mode}
                         </span>
                       )}
                       {match.mode === 'Ranked' && (
-                        <span className={`px-2 py-0.5 text-xs font-medium rounded whitespace-nowrap ${getRankColor(match.rank, isDark)}`}>
-                          {match.rank}
+                        <span className={`px-2 py-0.5 text-xs font-medium rounded whitespace-nowrap ${getRankColor(match.rankTier, isDark)}`}>
+                          {match.labels.rank}
                         </span>
                       )}
                     </div>
@@ -141,11 +142,11 @@ function VirtualizedTable({
                   {/* 先/後 */}
                   <div className="px-3 text-center w-[60px]">
                     <span className={`px-1.5 py-0.5 text-xs font-medium rounded whitespace-nowrap ${
-                      match.playOrder === '先攻'
+                      match.playOrder === 'first'
                         ? 'bg-blue-500/20 text-blue-400'
                         : 'bg-orange-500/20 text-orange-400'
                     }`}>
-                      {match.playOrder === '先攻' ? '先' : '後'}
+                      {match.labels.playOrder.charAt(0)}
                     </span>
                   </div>
                   {/* 結果 */}
@@ -153,7 +154,9 @@ function VirtualizedTable({
                     <span className={`inline-flex items-center justify-center w-6 h-6 rounded-full text-xs font-bold ${
                       match.result === 'W'
                         ? 'bg-green-500/20 text-green-500'
-                        : 'bg-red-500/20 text-red-500'
+                        : match.result === 'D'
+                          ? 'bg-gray-500/20 text-gray-400'
+                          : 'bg-red-500/20 text-red-500'
                     }`}>
                       {match.result}
                     </span>
@@ -218,34 +221,34 @@ function VirtualizedTable({
   )
 }
 
-// 根據階級返回對應顏色 (深色/淺色模式)
-function getRankColor(rank: string, isDark: boolean): string {
-  if (rank.startsWith('銅')) {
+// 根據階級的 tier（e.g. "gold"）返回對應顏色 (深色/淺色模式)
+function getRankColor(tier: string | null, isDark: boolean): string {
+  if (tier === 'bronze') {
     return isDark 
       ? 'bg-amber-700/30 text-amber-500' 
       : 'bg-amber-700/20 text-amber-800 border border-amber-600'
   }
-  if (rank.startsWith('銀')) {
+  if (tier === 'silver') {
     return isDark 
       ? 'bg-gray-400/20 text-gray-300' 
       : 'bg-gray-200 text-gray-700 border border-gray-400'
   }
-  if (rank.startsWith('金')) {
+  if (tier === 'gold') {
     return isDark 
       ? 'bg-yellow-500/20 text-yellow-400' 
       : 'bg-yellow-100 text-yellow-700 border border-yellow-400'
   }
-  if (rank.startsWith('白金')) {
+  if (tier === 'platinum') {
     return isDark 
       ? 'bg-cyan-500/20 text-cyan-300' 
       : 'bg-cyan-100 text-cyan-700 border border-cyan-400'
   }
-  if (rank.startsWith('鑽石')) {
+  if (tier === 'diamond') {
     return isDark 
       ? 'bg-pink-500/20 text-pink-400' 
       : 'bg-pink-100 text-pink-700 border border-pink-400'
   }
-  if (rank.startsWith('大師')) {
+  if (tier === 'master') {
     return isDark 
       ? 'bg-orange-500/20 text-orange-400' 
       : 'bg-orange-100 text-orange-700 border border-orange-400'
@@ -343,21 +346,22 @@ export default function HistoryMatchesPage() {
   const matchupRows = useMemo(() => {
     if (!selectedMyDeckMain) return []
 
-    const map = new Map<string, { wins: number; losses: number }>()
+    const map = new Map<string, { wins: number; losses: number; draws: number }>()
     for (const m of matches) {
       if (m.myDeck?.main !== selectedMyDeckMain) continue
       const opp = m.oppDeck?.main || '未知'
-      const entry = map.get(opp) ?? { wins: 0, losses: 0 }
+      const entry = map.get(opp) ?? { wins: 0, losses: 0, draws: 0 }
       if (m.result === 'W') entry.wins += 1
       else if (m.result === 'L') entry.losses += 1
+      else entry.draws += 1
       map.set(opp, entry)
     }
 
     return Array.from(map.entries())
       .map(([name, v]) => {
-        const games = v.wins + v.losses
+        const games = v.wins + v.losses + v.draws
         const winRate = games > 0 ? (v.wins / games) * 100 : 0
-        return { name, games, wins: v.wins, losses: v.losses, winRate }
+        return { name, games, wins: v.wins, losses: v.losses, draws: v.draws, winRate }
       })
       .sort((a, b) => {
         if (matchupSortBy === 'winRate') {
@@ -393,12 +397,12 @@ export default function HistoryMatchesPage() {
     const latestMatch = data?.matches[0]
     const defaultValues = latestMatch ? {
       date: latestMatch.date.split('T')[0],
-      rank: latestMatch.rank,
+      rank: latestMatch.rank ?? 'gold-5',
       myDeckMain: latestMatch.myDeck.main,
       myDeckSub: latestMatch.myDeck.sub || '無',
     } : {
-      date: new Date().toISOString().split('T')[0],
-      rank: '金 V',
+      date: getTodayYMD(),
+      rank: 'gold-5',
       myDeckMain: '',
       myDeckSub: '無',
     }
@@ -583,7 +587,7 @@ export default function HistoryMatchesPage() {
                         <span className={`font-bold ${row.winRate >= 50 ? 'text-green-500' : 'text-red-500'}`}>{pct(row.winRate)}</span>
                         {showWlCounts && (
                           <span className={`ml-1 ${isDark ? 'text-gray-500' : 'text-gray-500'}`}>
-                            ({row.wins}W-{row.losses}L)
+                            ({row.wins}W-{row.losses}L{row.draws > 0 ? `-${row.draws}D` : ''})
                           </span>
                         )}
                       </td>
@@ -691,7 +695,7 @@ export default function HistoryMatchesPage() {
                       <span className={`font-bold ${row.winRate >= 50 ? 'text-green-500' : 'text-red-500'}`}>{row.winRate.toFixed(1)}%</span>
                       {showWlCounts && (
                         <span className={`ml-1 ${isDark ? 'text-gray-500' : 'text-gray-500'}`}>
-                          ({row.wins}W-{row.losses}L)
+                          ({row.wins}W-{row.losses}L{row.draws > 0 ? `-${row.draws}D` : ''})
                         </span>
                       )}
                     </td>
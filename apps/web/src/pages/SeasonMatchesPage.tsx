@@ -5,41 +5,41 @@ import { decksService, THEME_COLORS, type DeckTheme } from '../services/decksSer
 import { useTheme } from '../contexts/ThemeContext'
 import MatchForm from '../components/MatchForm'
 import type { Match } from '../types/match'
-import { getCurrentSeasonCode, getRecentSeasonCodes, getSeasonInfo } from '../utils/season'
+import { getCurrentSeasonCode, getRecentSeasonCodes, getSeasonInfo, getTodayYMD } from '../utils/season'
 import ReactECharts from 'echarts-for-react'
 import { buildSeasonStats } from '../utils/stats'
 
 // 視圖模式
 type ViewMode = 'both' | 'stats' | 'records'
 
-// 根據階級返回對應顏色 (深色/淺色模式)
-function getRankColor(rank: string, isDark: boolean): string {
-  if (rank.startsWith('銅')) {
+// 根據階級的 tier（e.g. "gold"）返回對應顏色 (深色/淺色模式)
+function getRankColor(tier: string | null, isDark: boolean): string {
+  if (tier === 'bronze') {
     return isDark 
       ? 'bg-amber-700/30 text-amber-500' 
       : 'bg-amber-700/20 text-amber-800 border border-amber-600'
   }
-  if (rank.startsWith('銀')) {
+  if (tier === 'silver') {
     return isDark 
       ? 'bg-gray-400/20 text-gray-300' 
       : 'bg-gray-200 text-gray-700 border border-gray-400'
   }
-  if (rank.startsWith('金')) {
+  if (tier === 'gold') {
     return isDark 
       ? 'bg-yellow-500/20 text-yellow-400' 
       : 'bg-yellow-100 text-yellow-700 border border-yellow-400'
   }
-  if (rank.startsWith('白金')) {
+  if (tier === 'platinum') {
     return isDark 
       ? 'bg-cyan-500/20 text-cyan-300' 
       : 'bg-cyan-100 text-cyan-700 border border-cyan-400'
   }
-  if (rank.startsWith('鑽石')) {
+  if (tier === 'diamond') {
     return isDark 
       ? 'bg-pink-500/20 text-pink-400' 
       : 'bg-pink-100 text-pink-700 border border-pink-400'
   }
-  if (rank.startsWith('大師')) {
+  if (tier === 'master') {
     return isDark 
       ? 'bg-orange-500/20 text-orange-400' 
       : 'bg-orange-100 text-orange-700 border border-orange-400'
@@ -208,12 +208,12 @@ export default function SeasonMatchesPage() {
     const latestMatch = data?.matches[0]
     const defaultValues = latestMatch ? {
       date: latestMatch.date.split('T')[0],
-      rank: latestMatch.rank,
+      rank: latestMatch.rank ?? 'gold-5',
       myDeckMain: latestMatch.myDeck.main,
       myDeckSub: latestMatch.myDeck.sub || '無',
     } : {
-      date: selectedSeasonInfo?.start || new Date().toISOString().split('T')[0],
-      rank: '金 V',
+      date: selectedSeasonInfo?.start || getTodayYMD(),
+      rank: 'gold-5',
       myDeckMain: '',
       myDeckSub: '無',
     }
@@ -533,7 +533,7 @@ export default function SeasonMatchesPage() {
                       <span className={`font-bold ${row.winRate >= 50 ? 'text-green-500' : 'text-red-500'}`}>{row.winRate.toFixed(1)}%</span>
                       {showMyDeckWlCounts && (
                         <span className={`ml-1 ${isDark ? 'text-gray-500' : 'text-gray-500'}`}>
-                          ({row.wins}W-{row.losses}L)
+                          ({row.wins}W-{row.losses}L{row.draws > 0 ? `-${row.draws}D` : ''})
                         </span>
                       )}
                     </td>
@@ -769,12 +769,12 @@ export default function SeasonMatchesPage() {
                       <div className="flex items-center gap-1 flex-wrap">
                         {match.mode !== 'Ranked' && (
                           <span className={`px-2 py-0.5 text-xs font-bold rounded whitespace-nowrap ${getModeTagColor(match.mode, isDark)}`}>
-                            {match.mode}
+                            {match.labels.mode}
                           </span>
                         )}
                         {match.mode === 'Ranked' && (
-                          <span className={`px-2 py-0.5 text-xs font-medium rounded whitespace-nowrap ${getRankColor(match.rank, isDark)}`}>
-                            {match.rank}
+                          <span className={`px-2 py-0.5 text-xs font-medium rounded whitespace-nowrap ${getRankColor(match.rankTier, isDark)}`}>
+                            {match.labels.rank}
                           </span>
                         )}
                       </div>
@@ -793,18 +793,20 @@ export default function SeasonMatchesPage() {
                     </td>
                     <td className="px-3 py-2 text-center">
                       <span className={`px-2 py-0.5 text-xs font-medium rounded whitespace-nowrap ${
-                        match.playOrder === '先攻'
+                        match.playOrder === 'first'
                           ? 'bg-blue-500/20 text-blue-400'
                           : 'bg-orange-500/20 text-orange-400'
                       }`}>
-                        {match.playOrder}
+                        {match.labels.playOrder}
                       </span>
                     </td>
                     <td className="px-3 py-2 text-center">
                       <span className={`inline-flex items-center justify-center w-7 h-7 rounded-full text-xs font-bold ${
                         match.result === 'W'
                           ? 'bg-green-500/20 text-green-500'
-                          : 'bg-red-500/20 text-red-500'
+                          : match.result === 'D'
+                            ? 'bg-gray-500/20 text-gray-400'
+                            : 'bg-red-500/20 text-red-500'
                       }`}>
                         {match.result}
                       </span>
@@ -0,0 +1,32 @@
+import api from './api'
+
+// 遊戲帳號（同一個使用者可以有多個遊戲帳號）
+export interface Account {
+  id: string
+  gameId: string
+  name: string
+  createdAt: string
+}
+
+interface GetAccountsResponse {
+  accounts: Account[]
+  total: number
+}
+
+export const accountsService = {
+  async getAccounts(gameKey?: string): Promise<GetAccountsResponse> {
+    const params = gameKey ? { gameKey } : {}
+    const response = await api.get<GetAccountsResponse>('/accounts', { params })
+    return response.data
+  },
+
+  async createAccount(name: string, gameKey = 'master_duel'): Promise<Account> {
+    const response = await api.post<Account>('/accounts', { gameKey, name })
+    return response.data
+  },
+
+  async deleteAccount(id: string): Promise<{ id: string; message: string }> {
+    const response = await api.delete(`/accounts/${id}`)
+    return response.data
+  },
+}
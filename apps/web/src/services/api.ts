@@ -5,13 +5,33 @@ const api = axios.create({
   baseURL: import.meta.env.VITE_API_BASE_URL || '/',
   headers: {
     'Content-Type': 'application/json',
+    // 對局的顯示名稱（labels）與介面一致使用繁體中文
+    'Accept-Language': 'zh-TW',
   },
 })
 
-// 請求攔截器（可以加入 token 等）
+// 登入 token 存在 localStorage（未登入時後端視為預設使用者）
+const TOKEN_KEY = 'duellog_token'
+
+export function getAuthToken(): string | null {
+  return localStorage.getItem(TOKEN_KEY)
+}
+
+export function setAuthToken(token: string | null) {
+  if (token) {
+    localStorage.setItem(TOKEN_KEY, token)
+  } else {
+    localStorage.removeItem(TOKEN_KEY)
+  }
+}
+
+// 請求攔截器（加入 auth token）
 api.interceptors.request.use(
   (config) => {
-    // 未來可以在這裡加入 auth token
+    const token = getAuthToken()
+    if (token) {
+      config.headers.Authorization = `Bearer ${token}`
+    }
     return config
   },
   (error) => {
@@ -23,6 +43,10 @@ api.interceptors.request.use(
 api.interceptors.response.use(
   (response) => response,
   (error) => {
+    // token 失效（過期或伺服器重設密鑰）時清掉，避免之後的請求一直 401
+    if (error.response?.status === 401 && getAuthToken()) {
+      setAuthToken(null)
+    }
     console.error('API Error:', error.response?.data || error.message)
     return Promise.reject(error)
   }
@@ -0,0 +1,58 @@
+import api, { getAuthToken, setAuthToken } from './api'
+
+export interface User {
+  id: string
+  email: string
+  timezone: string // IANA 時區，決定對局的當地日期
+  createdAt: string
+  updatedAt: string
+}
+
+interface AuthResponse {
+  token: string
+  expiresIn: number
+  user: User
+}
+
+interface UpdateMeResponse {
+  user: User
+  relocalizedMatches: number // 依新時區重新計算日期的對局數
+}
+
+// 瀏覽器的時區（e.g. "Asia/Taipei"）
+export function browserTimezone(): string {
+  return Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC'
+}
+
+export const authService = {
+  async register(email: string, password: string): Promise<User> {
+    // 以瀏覽器的時區作為使用者時區
+    const response = await api.post<AuthResponse>('/auth/register', { email, password, timezone: browserTimezone() })
+    setAuthToken(response.data.token)
+    return response.data.user
+  },
+
+  async login(email: string, password: string): Promise<User> {
+    const response = await api.post<AuthResponse>('/auth/login', { email, password })
+    setAuthToken(response.data.token)
+    return response.data.user
+  },
+
+  async me(): Promise<User> {
+    const response = await api.get<{ user: User }>('/auth/me')
+    return response.data.user
+  },
+
+  async updateMe(changes: { timezone: string }): Promise<UpdateMeResponse> {
+    const response = await api.patch<UpdateMeResponse>('/auth/me', changes)
+    return response.data
+  },
+
+  logout() {
+    setAuthToken(null)
+  },
+
+  isLoggedIn(): boolean {
+    return getAuthToken() !== null
+  },
+}
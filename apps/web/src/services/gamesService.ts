@@ -0,0 +1,57 @@
+import api from './api'
+
+export interface Game {
+  id: string
+  key: string // e.g. "master_duel"
+  name: string
+  seasonResetTime: string // 每月 1 日賽季切換的時間（HH:MM）
+  seasonTimezone: string // 賽季切換時間的時區（IANA）
+}
+
+// 更新遊戲（省略的欄位不變）
+export interface UpdateGameRequest {
+  name?: string
+  seasonResetTime?: string
+  seasonTimezone?: string
+}
+
+// 遊戲的對局規則（GET /games/:key/rules）
+export interface GameRules {
+  gameKey: string
+  freeform: boolean // 沒有註冊規則：各欄位不限制
+  modes: string[] // 第一個為預設
+  defaultMode: string
+  ranks: string[] // 由低到高
+  playOrders: string[]
+  results: string[]
+  rankModes: string[] // 記錄階級的模式
+  ratingModes: string[] // 記錄積分的模式
+  labels: Record<string, Record<string, string>> // field → 代碼 → 顯示名稱
+}
+
+interface GetGamesResponse {
+  games: Game[]
+  total: number
+}
+
+export const gamesService = {
+  async getGames(): Promise<GetGamesResponse> {
+    const response = await api.get<GetGamesResponse>('/games')
+    return response.data
+  },
+
+  async createGame(key: string, name: string): Promise<Game> {
+    const response = await api.post<Game>('/games', { key, name })
+    return response.data
+  },
+
+  async updateGame(key: string, changes: UpdateGameRequest): Promise<Game> {
+    const response = await api.patch<Game>(`/games/${key}`, changes)
+    return response.data
+  },
+
+  async getGameRules(key: string): Promise<GameRules> {
+    const response = await api.get<GameRules>(`/games/${key}/rules`)
+    return response.data
+  },
+}
@@ -1,13 +1,14 @@
 import api from './api'
-import type { MatchesResponse, CreateMatchRequest, UpdateMatchRequest } from '../types/match'
+import type { Match, MatchesResponse, CreateMatchRequest, UpdateMatchRequest, MatchResult, PlayOrder } from '../types/match'
 
 // 查詢參數介面
 interface GetMatchesParams {
+  accountId?: string
   seasonCode?: string
   myDeckMain?: string
   oppDeckMain?: string
-  result?: 'W' | 'L'
-  playOrder?: '先攻' | '後攻'
+  result?: MatchResult
+  playOrder?: PlayOrder
   dateFrom?: string
   dateTo?: string
   mode?: 'Ranked' | 'Rating' | 'DC'
@@ -21,9 +22,9 @@ export const matchesService = {
     return response.data
   },
 
-  // 新增對局
-  async createMatch(data: CreateMatchRequest): Promise<{ id: string; message: string }> {
-    const response = await api.post('/matches', data)
+  // 新增對局（回傳建立後的完整對局）
+  async createMatch(data: CreateMatchRequest): Promise<Match> {
+    const response = await api.post<Match>('/matches', data)
     return response.data
   },
 
@@ -0,0 +1,40 @@
+import api from './api'
+
+// 賽季（新增對局時會依賽季曆自動建立）
+export interface Season {
+  id: string
+  gameId: string
+  code: string // e.g. "S49"
+  startDate: string | null // YYYY-MM-DD
+  endDate: string | null
+}
+
+interface GetSeasonsResponse {
+  seasons: Season[]
+  current: string | null // 目前賽季的代碼（遊戲沒有賽季曆時為 null）
+  total: number
+}
+
+interface SeasonDates {
+  startDate?: string
+  endDate?: string
+}
+
+export const seasonsService = {
+  async getSeasons(gameKey?: string): Promise<GetSeasonsResponse> {
+    const params = gameKey ? { gameKey } : {}
+    const response = await api.get<GetSeasonsResponse>('/seasons', { params })
+    return response.data
+  },
+
+  // 省略起訖日時依賽季曆推算
+  async createSeason(code: string, dates: SeasonDates = {}, gameKey = 'master_duel'): Promise<Season> {
+    const response = await api.post<Season>('/seasons', { gameKey, code, ...dates })
+    return response.data
+  },
+
+  async updateSeason(code: string, dates: SeasonDates, gameKey = 'master_duel'): Promise<Season> {
+    const response = await api.patch<Season>(`/seasons/${code}`, dates, { params: { gameKey } })
+    return response.data
+  },
+}
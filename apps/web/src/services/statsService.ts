@@ -0,0 +1,332 @@
+import api from './api'
+
+// 統計查詢參數（與 GET /matches 篩選條件相同）
+export interface StatsParams {
+  accountId?: string
+  seasonCode?: string
+  mode?: 'Ranked' | 'Rating' | 'DC'
+  myDeckMain?: string
+  oppDeckMain?: string
+  dateFrom?: string
+  dateTo?: string
+}
+
+// 勝率的區間估計與收縮估計（0-100），僅在帶 ci 參數時回傳
+export interface RateEstimate {
+  method: 'wilson' | 'beta'
+  level: number
+  lower: number
+  upper: number
+  shrunk: number
+  lowConfidence: boolean
+}
+
+// 區間估計參數：ci 指定方法；prior 為收縮強度（虛擬場數）；maxWidth 為低信心門檻（百分點）
+export interface ConfidenceParams {
+  ci?: 'wilson' | 'beta'
+  level?: number
+  prior?: number
+  maxWidth?: number
+}
+
+interface RateEstimates {
+  winRateCI?: RateEstimate
+  firstWinRateCI?: RateEstimate
+  secondWinRateCI?: RateEstimate
+}
+
+// KPI 統計（口徑見 docs/spec.md §5.3，百分比為 0-100；比率為 null 表示分母為 0）
+export interface StatsSummary extends RateEstimates {
+  total: number
+  wins: number
+  losses: number
+  draws: number
+  winRate: number | null // 平手計入場數
+  drawRate: number | null
+  firstCount: number
+  secondCount: number
+  firstWins: number
+  secondWins: number
+  firstRate: number | null
+  firstWinRate: number | null
+  secondWinRate: number | null
+}
+
+// 每日統計（比率為 null 表示分母為 0）
+export interface StatsDailyRow extends RateEstimates {
+  date: string // YYYY-MM-DD
+  games: number
+  wins: number
+  losses: number
+  draws: number
+  first: number
+  second: number
+  firstWins: number
+  firstLosses: number
+  firstDraws: number
+  secondWins: number
+  secondLosses: number
+  secondDraws: number
+  firstRate: number | null
+  winRate: number | null
+  firstWinRate: number | null
+  secondWinRate: number | null
+}
+
+interface StatsDailyResponse {
+  daily: StatsDailyRow[]
+  total: number
+}
+
+// 牌組統計（對應 DeckStatRow，另含 deckMain/count/pct）
+export interface StatsDeckRow extends RateEstimates {
+  name: string
+  deckMain: string
+  deckSub: string | null
+  count: number
+  pct: number
+  games: number
+  wins: number
+  losses: number
+  draws: number
+  winRate: number
+  first: number
+  second: number
+  firstRate: number
+  firstWins: number
+  firstLosses: number
+  firstDraws: number
+  secondWins: number
+  secondLosses: number
+  secondDraws: number
+  firstWinRate: number | null // 沒有先攻場次時為 null
+  secondWinRate: number | null // 沒有後攻場次時為 null
+}
+
+interface StatsDecksResponse {
+  decks: StatsDeckRow[]
+  total: number
+}
+
+// 牌組分組方式：只看大軸，或大軸 + 小軸
+export type DeckGroupBy = 'main' | 'sub'
+
+// 對戰矩陣的一格（我方牌組 × 對手牌組）
+export interface MatchupCell extends RateEstimates {
+  myDeck: string
+  oppDeck: string
+  myDeckMain: string
+  myDeckSub: string | null
+  oppDeckMain: string
+  oppDeckSub: string | null
+  games: number
+  wins: number
+  losses: number
+  draws: number
+  winRate: number
+  first: number
+  second: number
+  firstWins: number
+  secondWins: number
+  firstWinRate: number | null
+  secondWinRate: number | null
+}
+
+// 稀疏對戰矩陣（只含場數 >= minGames 的格子）
+export interface StatsMatchups {
+  myDecks: string[]
+  oppDecks: string[]
+  cells: MatchupCell[]
+  minGames: number
+}
+
+// 時間軸上的一場對局（rank 為階級代碼，ordinal 為在階級表中的位置，-1 表示不在表中）
+export interface RankPoint {
+  matchId: string
+  date: string // YYYY-MM-DD
+  playedAt: string | null
+  rank: string
+  tier: string
+  level: number | null
+  ordinal: number
+  result: 'W' | 'L' | 'D'
+}
+
+// 相鄰兩場對局之間的階級變化
+export interface RankChange {
+  matchId: string
+  date: string
+  from: string
+  to: string
+  direction: 'promotion' | 'demotion' | 'change'
+  steps: number
+}
+
+// 單一 tier 的場數與勝率（比率為 null 表示分母為 0）
+export interface RankTierRow {
+  tier: string
+  games: number
+  wins: number
+  losses: number
+  draws: number
+  winRate: number | null
+  winRateCI?: RateEstimate
+}
+
+// 一個賽季的階級變化
+export interface SeasonRankTimeline {
+  seasonCode: string
+  games: number
+  startRank: string
+  endRank: string
+  peakRank: string
+  promotions: number
+  demotions: number
+  timeline: RankPoint[]
+  changes: RankChange[]
+  tiers: RankTierRow[]
+}
+
+interface StatsRankTimelineResponse {
+  seasons: SeasonRankTimeline[]
+  total: number
+}
+
+// 積分曲線上的一場對局（rating 為對局後的積分；estimated 表示由上一場的積分 + delta 推算）
+export interface RatingPoint {
+  matchId: string
+  date: string // YYYY-MM-DD
+  playedAt: string | null
+  before: number | null
+  after: number | null
+  delta: number | null
+  rating: number | null
+  estimated: boolean
+  result: 'W' | 'L' | 'D'
+}
+
+// 一個活動（同一賽季的同一模式）的積分變化
+export interface RatingEvent {
+  mode: 'Rating' | 'DC'
+  seasonCode: string
+  games: number
+  wins: number
+  losses: number
+  draws: number
+  winRate: number | null
+  startRating: number | null
+  endRating: number | null
+  netChange: number | null
+  peak: number | null
+  peakDate: string | null
+  low: number | null
+  lowDate: string | null
+  series: RatingPoint[]
+  winRateCI?: RateEstimate
+}
+
+interface StatsRatingResponse {
+  events: RatingEvent[]
+  total: number
+}
+
+// 天梯模擬參數（比率為 0-100，省略時由最近的對局推算）
+export interface LadderSimParams {
+  from?: string
+  target?: string
+  recent?: number
+  minSample?: number
+  firstRate?: number
+  firstWinRate?: number
+  secondWinRate?: number
+  runs?: number
+  maxGames?: number
+  seed?: number
+}
+
+// 天梯模擬結果（場數只計到達目標的模擬；percentiles 的 games 為 null 表示在 maxGames 內未到達）
+export interface LadderSimulation {
+  from: string
+  target: string
+  seed: number
+  sample: {
+    currentRank: string | null
+    lastPlayed: string | null
+    games: number
+    first: number
+    firstWins: number
+    firstDraws: number
+    second: number
+    secondWins: number
+    secondDraws: number
+  }
+  rates: {
+    firstRate: number
+    firstWinRate: number
+    firstDrawRate: number
+    secondWinRate: number
+    secondDrawRate: number
+  }
+  lowConfidence: boolean // 有比率由少於 minSample 場的對局推算
+  warning?: string
+  runs: number
+  maxGames: number
+  reached: number
+  reachedRate: number
+  mean: number | null
+  min: number | null
+  max: number | null
+  percentiles: { p: number; games: number | null }[]
+  histogram: { from: number; to: number; count: number; pct: number; cumulativePct: number }[]
+}
+
+// Stats API Service
+export const statsService = {
+  // KPI 統計
+  async getSummary(params?: StatsParams & ConfidenceParams): Promise<StatsSummary> {
+    const response = await api.get<StatsSummary>('/stats/summary', { params })
+    return response.data
+  },
+
+  // 每日統計（指定日期區間或賽季時會補 0）
+  async getDaily(params?: StatsParams & ConfidenceParams): Promise<StatsDailyResponse> {
+    const response = await api.get<StatsDailyResponse>('/stats/daily', { params })
+    return response.data
+  },
+
+  // 對手牌組分布
+  async getOpponents(params?: StatsParams & ConfidenceParams & { groupBy?: DeckGroupBy }): Promise<StatsDecksResponse> {
+    const response = await api.get<StatsDecksResponse>('/stats/opponents', { params })
+    return response.data
+  },
+
+  // 我方牌組統計
+  async getMyDecks(params?: StatsParams & ConfidenceParams & { groupBy?: DeckGroupBy }): Promise<StatsDecksResponse> {
+    const response = await api.get<StatsDecksResponse>('/stats/my-decks', { params })
+    return response.data
+  },
+
+  // 對戰矩陣
+  async getMatchups(params?: StatsParams & ConfidenceParams & { groupBy?: DeckGroupBy; minGames?: number }): Promise<StatsMatchups> {
+    const response = await api.get<StatsMatchups>('/stats/matchups', { params })
+    return response.data
+  },
+
+  // 各賽季的階級變化
+  async getRankTimeline(params?: StatsParams & ConfidenceParams): Promise<StatsRankTimelineResponse> {
+    const response = await api.get<StatsRankTimelineResponse>('/stats/rank-timeline', { params })
+    return response.data
+  },
+
+  // 各活動（模式 + 賽季）的積分曲線與最高 / 最低積分
+  async getRatingHistory(params?: StatsParams & ConfidenceParams): Promise<StatsRatingResponse> {
+    const response = await api.get<StatsRatingResponse>('/stats/rating', { params })
+    return response.data
+  },
+
+  // 天梯模擬：到達目標階級需要的場數分布
+  async simulateLadder(params?: StatsParams & LadderSimParams): Promise<LadderSimulation> {
+    const response = await api.get<LadderSimulation>('/stats/ladder-sim', { params })
+    return response.data
+  },
+}
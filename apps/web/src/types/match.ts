@@ -6,17 +6,59 @@ export interface DeckInfo {
   sub: string | null
 }
 
+// 對局結果：勝 / 敗 / 平手（時間到、同時敗北等）
+export type MatchResult = 'W' | 'L' | 'D'
+
+// 先後攻代碼（顯示名稱見 MatchLabels.playOrder）
+export type PlayOrder = 'first' | 'second'
+
+// 階級：代碼（e.g. "gold-4"）、顯示名稱（e.g. "金 IV"），或 tier + level（level 5 ～ 1 對應 V ～ I）
+export type RankValue = string | { tier: string; level: number }
+
+// 代碼欄位的顯示名稱（語系依 Accept-Language）
+export interface MatchLabels {
+  mode: string
+  rank: string | null
+  playOrder: string
+  result: string
+}
+
+// 積分（Rating / DC 等模式）：對局前後的積分與增減，皆可省略；後端會補齊可推算的欄位
+export interface MatchRating {
+  before: number | null
+  after: number | null
+  delta: number | null
+}
+
+// 送出時的積分（任一欄位即可，e.g. 只填 after 或 delta）
+export interface RatingValue {
+  before?: number | null
+  after?: number | null
+  delta?: number | null
+}
+
+export interface AccountInfo {
+  id: string
+  name: string
+}
+
 export interface Match {
   id: string
-  date: string
+  date: string // 使用者當地的日期
+  playedAt: string | null // 對局時間（ISO 8601；舊資料為 null）
   mode: 'Ranked' | 'Rating' | 'DC'
-  rank: string
+  rank: string | null // 階級代碼，e.g. "gold-4"；不記錄階級的模式為 null
+  rankTier: string | null // e.g. "gold"
+  rankLevel: number | null // e.g. 4（= IV）
+  rating: MatchRating | null // 沒有記錄積分時為 null
   myDeck: DeckInfo
   oppDeck: DeckInfo
-  playOrder: '先攻' | '後攻'
-  result: 'W' | 'L'
+  playOrder: PlayOrder
+  result: MatchResult
   note: string | null
   seasonCode: string
+  account: AccountInfo | null
+  labels: MatchLabels
   createdAt: string
   updatedAt: string
 }
@@ -28,10 +70,13 @@ export interface MatchesResponse {
 
 export interface CreateMatchRequest {
   gameKey: string
-  seasonCode: string
-  date: string
+  seasonCode?: string // 省略時由後端依日期推算
+  accountId?: string
+  date?: string // 使用者當地的日期；有 playedAt 時可省略（由後端依使用者時區換算）
+  playedAt?: string // 對局時間（ISO 8601）
   mode?: 'Ranked' | 'Rating' | 'DC'
-  rank: string
+  rank: RankValue
+  rating?: RatingValue // 只限記錄積分的模式
   myDeck: {
     main: string
     sub: string | null
@@ -40,15 +85,18 @@ export interface CreateMatchRequest {
     main: string
     sub: string | null
   }
-  playOrder: '先攻' | '後攻'
-  result: 'W' | 'L'
+  playOrder: PlayOrder
+  result: MatchResult
   note?: string
 }
 
 export interface UpdateMatchRequest {
+  seasonCode?: string
   date?: string
+  playedAt?: string
   mode?: 'Ranked' | 'Rating' | 'DC'
-  rank?: string
+  rank?: RankValue
+  rating?: RatingValue // {} 為清除
   myDeck?: {
     main: string
     sub: string | null
@@ -57,7 +105,7 @@ export interface UpdateMatchRequest {
     main: string
     sub: string | null
   }
-  playOrder?: '先攻' | '後攻'
-  result?: 'W' | 'L'
+  playOrder?: PlayOrder
+  result?: MatchResult
   note?: string
 }
@@ -84,17 +84,22 @@ export function getCurrentMonthRange(): { start: string; end: string } {
   const start = new Date(year, month, 1)
   const end = new Date(year, month + 1, 0) // 該月最後一天
   
+  // 以當地日期格式化（toISOString 會轉成 UTC，在 UTC+ 時區會變成前一天）
   return {
-    start: start.toISOString().split('T')[0],
-    end: end.toISOString().split('T')[0],
+    start: formatDateYMD(start),
+    end: formatDateYMD(end),
   }
 }
 
+// 今天的當地日期（YYYY-MM-DD）
+export function getTodayYMD(): string {
+  return formatDateYMD(new Date())
+}
+
 // 根據日期計算賽季代碼
 export function getSeasonCodeFromDate(dateStr: string): string {
-  const date = new Date(dateStr)
-  const year = date.getFullYear()
-  const month = date.getMonth() + 1
+  // YYYY-MM-DD 直接取年月（new Date('YYYY-MM-DD') 會當成 UTC，在 UTC- 時區會變成前一天）
+  const [year, month] = dateStr.split('T')[0].split('-').map(Number)
 
   const monthsDiff = (year - BASE_YEAR) * 12 + (month - BASE_MONTH)
   const seasonNumber = BASE_SEASON + monthsDiff
@@ -5,15 +5,18 @@ export interface DeckStatRow {
   games: number
   wins: number
   losses: number
-  winRate: number // 0-100
+  draws: number
+  winRate: number // 0-100（平手計入場數）
 
   first: number
   second: number
   firstRate: number // 0-100
   firstWins: number
   firstLosses: number
+  firstDraws: number
   secondWins: number
   secondLosses: number
+  secondDraws: number
   firstWinRate: number // 0-100
   secondWinRate: number // 0-100
 }
@@ -23,12 +26,15 @@ export interface DailyStatRow {
   games: number
   wins: number
   losses: number
+  draws: number
   first: number
   second: number
   firstWins: number
   firstLosses: number
+  firstDraws: number
   secondWins: number
   secondLosses: number
+  secondDraws: number
   firstRate: number | null // 0-100
   winRate: number | null // null when games == 0
   firstWinRate: number | null
@@ -39,6 +45,7 @@ export interface SeasonStats {
   total: number
   wins: number
   losses: number
+  draws: number
   winRate: number
   firstCount: number
   secondCount: number
@@ -71,27 +78,83 @@ function parseYMD(s: string): Date {
   return new Date(y, (m ?? 1) - 1, d ?? 1)
 }
 
+// 一組對局的勝 / 敗 / 平計數（平手不算勝也不算敗，games = wins + losses + draws）
+interface Tally {
+  wins: number
+  losses: number
+  draws: number
+  first: number
+  second: number
+  firstWins: number
+  firstLosses: number
+  firstDraws: number
+  secondWins: number
+  secondLosses: number
+  secondDraws: number
+}
+
+function emptyTally(): Tally {
+  return {
+    wins: 0,
+    losses: 0,
+    draws: 0,
+    first: 0,
+    second: 0,
+    firstWins: 0,
+    firstLosses: 0,
+    firstDraws: 0,
+    secondWins: 0,
+    secondLosses: 0,
+    secondDraws: 0,
+  }
+}
+
+function addMatch(t: Tally, m: Match): void {
+  const isFirst = m.playOrder === 'first'
+  if (isFirst) t.first += 1
+  else t.second += 1
+
+  if (m.result === 'W') {
+    t.wins += 1
+    if (isFirst) t.firstWins += 1
+    else t.secondWins += 1
+  } else if (m.result === 'L') {
+    t.losses += 1
+    if (isFirst) t.firstLosses += 1
+    else t.secondLosses += 1
+  } else {
+    t.draws += 1
+    if (isFirst) t.firstDraws += 1
+    else t.secondDraws += 1
+  }
+}
+
+function tallyGames(t: Tally): number {
+  return t.wins + t.losses + t.draws
+}
+
+function toDailyRow(date: string, t: Tally): DailyStatRow {
+  const games = tallyGames(t)
+  return {
+    date,
+    games,
+    ...t,
+    firstRate: games > 0 ? (t.first / games) * 100 : null,
+    winRate: games > 0 ? (t.wins / games) * 100 : null,
+    firstWinRate: t.first > 0 ? (t.firstWins / t.first) * 100 : null,
+    secondWinRate: t.second > 0 ? (t.secondWins / t.second) * 100 : null,
+  }
+}
+
 function dateKeyFromMatch(match: Match): string {
   // API date may be YYYY-MM-DD or ISO; normalize to YYYY-MM-DD.
   return match.date.includes('T') ? match.date.split('T')[0] : match.date
 }
 
-function toDeckStats(
-  rows: Array<{
-    name: string
-    wins: number
-    losses: number
-    first: number
-    second: number
-    firstWins: number
-    firstLosses: number
-    secondWins: number
-    secondLosses: number
-  }>,
-): DeckStatRow[] {
+function toDeckStats(rows: Array<{ name: string } & Tally>): DeckStatRow[] {
   return rows
     .map(r => {
-      const games = r.wins + r.losses
+      const games = tallyGames(r)
       const winRate = games > 0 ? clamp01(r.wins / games) * 100 : 0
       const firstRate = games > 0 ? clamp01(r.first / games) * 100 : 0
       const firstWinRate = r.first > 0 ? clamp01(r.firstWins / r.first) * 100 : 0
@@ -101,6 +164,7 @@ function toDeckStats(
         games,
         wins: r.wins,
         losses: r.losses,
+        draws: r.draws,
         winRate,
 
         first: r.first,
@@ -108,8 +172,10 @@ function toDeckStats(
         firstRate,
         firstWins: r.firstWins,
         firstLosses: r.firstLosses,
+        firstDraws: r.firstDraws,
         secondWins: r.secondWins,
         secondLosses: r.secondLosses,
+        secondDraws: r.secondDraws,
         firstWinRate,
         secondWinRate,
       }
@@ -121,10 +187,11 @@ export function buildSeasonStats(matches: Match[], range?: { start: string; end:
   const total = matches.length
   const wins = matches.filter(m => m.result === 'W').length
   const losses = matches.filter(m => m.result === 'L').length
+  const draws = matches.filter(m => m.result === 'D').length
   const winRate = total > 0 ? (wins / total) * 100 : 0
 
-  const firstMatches = matches.filter(m => m.playOrder === '先攻')
-  const secondMatches = matches.filter(m => m.playOrder === '後攻')
+  const firstMatches = matches.filter(m => m.playOrder === 'first')
+  const secondMatches = matches.filter(m => m.playOrder === 'second')
   const firstCount = firstMatches.length
   const secondCount = secondMatches.length
   const firstWins = firstMatches.filter(m => m.result === 'W').length
@@ -133,156 +200,30 @@ export function buildSeasonStats(matches: Match[], range?: { start: string; end:
   const firstWinRate = firstCount > 0 ? (firstWins / firstCount) * 100 : 0
   const secondWinRate = secondCount > 0 ? (secondWins / secondCount) * 100 : 0
 
-  const oppMap = new Map<
-    string,
-    {
-      wins: number
-      losses: number
-      first: number
-      second: number
-      firstWins: number
-      firstLosses: number
-      secondWins: number
-      secondLosses: number
-    }
-  >()
-  const myMap = new Map<
-    string,
-    {
-      wins: number
-      losses: number
-      first: number
-      second: number
-      firstWins: number
-      firstLosses: number
-      secondWins: number
-      secondLosses: number
-    }
-  >()
+  const oppMap = new Map<string, Tally>()
+  const myMap = new Map<string, Tally>()
 
   for (const m of matches) {
     const opp = m.oppDeck?.main || '未知'
     const mine = m.myDeck?.main || '未知'
 
-    const isWin = m.result === 'W'
-
-    const isFirst = m.playOrder === '先攻'
-
-    const oppEntry = oppMap.get(opp) ?? {
-      wins: 0,
-      losses: 0,
-      first: 0,
-      second: 0,
-      firstWins: 0,
-      firstLosses: 0,
-      secondWins: 0,
-      secondLosses: 0,
-    }
-    if (isWin) oppEntry.wins += 1
-    else oppEntry.losses += 1
-    if (isFirst) {
-      oppEntry.first += 1
-      if (isWin) oppEntry.firstWins += 1
-      else oppEntry.firstLosses += 1
-    } else {
-      oppEntry.second += 1
-      if (isWin) oppEntry.secondWins += 1
-      else oppEntry.secondLosses += 1
-    }
+    const oppEntry = oppMap.get(opp) ?? emptyTally()
+    addMatch(oppEntry, m)
     oppMap.set(opp, oppEntry)
 
-    const myEntry = myMap.get(mine) ?? {
-      wins: 0,
-      losses: 0,
-      first: 0,
-      second: 0,
-      firstWins: 0,
-      firstLosses: 0,
-      secondWins: 0,
-      secondLosses: 0,
-    }
-    if (isWin) myEntry.wins += 1
-    else myEntry.losses += 1
-    if (isFirst) {
-      myEntry.first += 1
-      if (isWin) myEntry.firstWins += 1
-      else myEntry.firstLosses += 1
-    } else {
-      myEntry.second += 1
-      if (isWin) myEntry.secondWins += 1
-      else myEntry.secondLosses += 1
-    }
+    const myEntry = myMap.get(mine) ?? emptyTally()
+    addMatch(myEntry, m)
     myMap.set(mine, myEntry)
   }
 
-  const oppDecks = toDeckStats(
-    Array.from(oppMap.entries()).map(([name, v]) => ({
-      name,
-      wins: v.wins,
-      losses: v.losses,
-      first: v.first,
-      second: v.second,
-      firstWins: v.firstWins,
-      firstLosses: v.firstLosses,
-      secondWins: v.secondWins,
-      secondLosses: v.secondLosses,
-    })),
-  )
-
-  const myDecks = toDeckStats(
-    Array.from(myMap.entries()).map(([name, v]) => ({
-      name,
-      wins: v.wins,
-      losses: v.losses,
-      first: v.first,
-      second: v.second,
-      firstWins: v.firstWins,
-      firstLosses: v.firstLosses,
-      secondWins: v.secondWins,
-      secondLosses: v.secondLosses,
-    })),
-  )
+  const oppDecks = toDeckStats(Array.from(oppMap.entries()).map(([name, v]) => ({ name, ...v })))
+  const myDecks = toDeckStats(Array.from(myMap.entries()).map(([name, v]) => ({ name, ...v })))
 
-  const dailyMap = new Map<
-    string,
-    {
-      wins: number
-      losses: number
-      first: number
-      second: number
-      firstWins: number
-      firstLosses: number
-      secondWins: number
-      secondLosses: number
-    }
-  >()
+  const dailyMap = new Map<string, Tally>()
   for (const m of matches) {
     const key = dateKeyFromMatch(m)
-    const entry = dailyMap.get(key) ?? {
-      wins: 0,
-      losses: 0,
-      first: 0,
-      second: 0,
-      firstWins: 0,
-      firstLosses: 0,
-      secondWins: 0,
-      secondLosses: 0,
-    }
-
-    const isWin = m.result === 'W'
-    if (isWin) entry.wins += 1
-    else entry.losses += 1
-
-    if (m.playOrder === '先攻') {
-      entry.first += 1
-      if (isWin) entry.firstWins += 1
-      else entry.firstLosses += 1
-    } else {
-      entry.second += 1
-      if (isWin) entry.secondWins += 1
-      else entry.secondLosses += 1
-    }
-
+    const entry = dailyMap.get(key) ?? emptyTally()
+    addMatch(entry, m)
     dailyMap.set(key, entry)
   }
 
@@ -292,54 +233,11 @@ export function buildSeasonStats(matches: Match[], range?: { start: string; end:
     const end = parseYMD(range.end)
     for (let d = new Date(start); d <= end; d.setDate(d.getDate() + 1)) {
       const key = formatDateYMD(d)
-      const entry = dailyMap.get(key)
-      const games = entry ? entry.wins + entry.losses : 0
-      const win = entry ? entry.wins : 0
-      const loss = entry ? entry.losses : 0
-      const first = entry ? entry.first : 0
-      const second = entry ? entry.second : 0
-      const firstWinsDay = entry ? entry.firstWins : 0
-      const secondWinsDay = entry ? entry.secondWins : 0
-      const firstLossesDay = entry ? entry.firstLosses : 0
-      const secondLossesDay = entry ? entry.secondLosses : 0
-      daily.push({
-        date: key,
-        games,
-        wins: win,
-        losses: loss,
-        first,
-        second,
-        firstWins: firstWinsDay,
-        firstLosses: firstLossesDay,
-        secondWins: secondWinsDay,
-        secondLosses: secondLossesDay,
-        firstRate: games > 0 ? (first / games) * 100 : null,
-        winRate: games > 0 ? (win / games) * 100 : null,
-        firstWinRate: first > 0 ? (firstWinsDay / first) * 100 : null,
-        secondWinRate: second > 0 ? (secondWinsDay / second) * 100 : null,
-      })
+      daily.push(toDailyRow(key, dailyMap.get(key) ?? emptyTally()))
     }
   } else {
     daily = Array.from(dailyMap.entries())
-      .map(([date, v]) => {
-        const games = v.wins + v.losses
-        return {
-          date,
-          games,
-          wins: v.wins,
-          losses: v.losses,
-          first: v.first,
-          second: v.second,
-          firstWins: v.firstWins,
-          firstLosses: v.firstLosses,
-          secondWins: v.secondWins,
-          secondLosses: v.secondLosses,
-          firstRate: games > 0 ? (v.first / games) * 100 : null,
-          winRate: games > 0 ? (v.wins / games) * 100 : null,
-          firstWinRate: v.first > 0 ? (v.firstWins / v.first) * 100 : null,
-          secondWinRate: v.second > 0 ? (v.secondWins / v.second) * 100 : null,
-        }
-      })
+      .map(([date, v]) => toDailyRow(date, v))
       .sort((a, b) => a.date.localeCompare(b.date))
   }
 
@@ -347,6 +245,7 @@ export function buildSeasonStats(matches: Match[], range?: { start: string; end:
     total,
     wins,
     losses,
+    draws,
     winRate,
     firstCount,
     secondCount,
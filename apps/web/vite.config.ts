@@ -4,4 +4,9 @@ import react from '@vitejs/plugin-react'
 // https://vite.dev/config/
 export default defineConfig({
   plugins: [react()],
+  build: {
+    // 輸出到後端的 web/static/dist，`go build` 時會以 go:embed 打包進執行檔
+    outDir: '../api/web/static/dist',
+    emptyOutDir: true,
+  },
 })